@@ -0,0 +1,173 @@
+package acl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultUser is used by connections which did not authenticate. As long as it is enabled
+// and has no password new connections are authenticated as this user.
+const DefaultUser = "default"
+
+// ACL holds the users allowed to connect and what each of them may run.
+// Changes are applied through Raft so every node of the cluster holds the same users.
+type ACL struct {
+	mu    sync.RWMutex
+	users map[string]*User
+}
+
+func New() *ACL {
+	a := &ACL{}
+	a.reset()
+	return a
+}
+
+func (a *ACL) reset() {
+	a.users = map[string]*User{
+		DefaultUser: {
+			Name:     DefaultUser,
+			Enabled:  true,
+			NoPass:   true,
+			AllKeys:  true,
+			Commands: []string{"+@" + CategoryAll},
+		},
+	}
+}
+
+// SetUser creates the user or modifies it with rules, rules are applied in order.
+// The user is left unchanged if one of the rules is invalid.
+func (a *ACL) SetUser(name string, rules []string) error {
+	if name == "" || strings.ContainsAny(name, " \t\r\n") {
+		return fmt.Errorf("invalid username: %q", name)
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	user, ok := a.users[name]
+	if ok {
+		user = user.clone()
+	} else {
+		user = newUser(name)
+	}
+	for _, rule := range rules {
+		if err := user.apply(rule); err != nil {
+			return err
+		}
+	}
+	a.users[name] = user
+	return nil
+}
+
+// DelUser deletes users and returns how many of them existed
+func (a *ACL) DelUser(names []string) (int, error) {
+	for _, name := range names {
+		if name == DefaultUser {
+			return 0, fmt.Errorf("The 'default' user cannot be removed")
+		}
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	deleted := 0
+	for _, name := range names {
+		if _, ok := a.users[name]; ok {
+			delete(a.users, name)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// User returns the user called name
+func (a *ACL) User(name string) (*User, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	user, ok := a.users[name]
+	return user, ok
+}
+
+// Authenticate returns the user if it exists, is enabled and password is one of its passwords
+func (a *ACL) Authenticate(name, password string) (*User, error) {
+	user, ok := a.User(name)
+	if !ok || !user.Enabled || !user.CheckPassword(password) {
+		return nil, fmt.Errorf("WRONGPASS invalid username-password pair or user is disabled.")
+	}
+	return user, nil
+}
+
+// NoAuthUser returns the default user if connections are authenticated as it without AUTH
+func (a *ACL) NoAuthUser() (*User, bool) {
+	user, ok := a.User(DefaultUser)
+	if !ok || !user.Enabled || !user.NoPass {
+		return nil, false
+	}
+	return user, true
+}
+
+// Custom tells if users were added or the default user was changed, connections are then not all the default
+// user with every permission
+func (a *ACL) Custom() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if len(a.users) > 1 {
+		return true
+	}
+	user, ok := a.users[DefaultUser]
+	return !ok || user.String() != New().users[DefaultUser].String()
+}
+
+// List describes every user, sorted by name, in the format read by Load
+func (a *ACL) List() []string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	names := make([]string, 0, len(a.users))
+	for name := range a.users {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	list := make([]string, 0, len(names))
+	for _, name := range names {
+		list = append(list, a.users[name].String())
+	}
+	return list
+}
+
+// Load replaces every user with the ones described by list, as returned by List.
+// An empty list restores the default user only.
+func (a *ACL) Load(list []string) error {
+	loaded := New()
+	for _, line := range list {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "user" {
+			return fmt.Errorf("invalid ACL user line: %q", line)
+		}
+		if err := loaded.SetUser(fields[1], append([]string{"reset"}, fields[2:]...)); err != nil {
+			return err
+		}
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.users = loaded.users
+	return nil
+}
+
+// ValidateRules checks that rules can be applied to a user called name, without changing any user
+func ValidateRules(name string, rules []string) error {
+	return New().SetUser(name, rules)
+}
+
+// HashPasswords rewrites the >password and <password rules to the #hash and !hash rules they stand for,
+// so the rules can be replicated and persisted without cleartext passwords
+func HashPasswords(rules []string) []string {
+	hashed := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		switch {
+		case strings.HasPrefix(rule, ">"):
+			rule = "#" + hashPassword(rule[1:])
+		case strings.HasPrefix(rule, "<"):
+			rule = "!" + hashPassword(rule[1:])
+		}
+		hashed = append(hashed, rule)
+	}
+	return hashed
+}
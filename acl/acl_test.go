@@ -0,0 +1,105 @@
+package acl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultUser(t *testing.T) {
+	a := New()
+	user, ok := a.NoAuthUser()
+	require.True(t, ok)
+	require.True(t, user.CanRun("SET", "", []string{CategoryWrite}))
+	require.True(t, user.CanAccessKey("any"))
+
+	require.NoError(t, a.SetUser(DefaultUser, []string{"resetpass", ">secret"}))
+	_, ok = a.NoAuthUser()
+	require.False(t, ok)
+	_, err := a.Authenticate(DefaultUser, "wrong")
+	require.Error(t, err)
+	_, err = a.Authenticate(DefaultUser, "secret")
+	require.NoError(t, err)
+
+	_, err = a.DelUser([]string{DefaultUser})
+	require.Error(t, err)
+}
+
+func TestCommandRules(t *testing.T) {
+	a := New()
+	require.NoError(t, a.SetUser("reader", []string{"on", ">pw", "+@read", "-keys", "+acl|whoami", "~user:"}))
+	user, ok := a.User("reader")
+	require.True(t, ok)
+
+	require.True(t, user.CanRun("GET", "", []string{CategoryRead}))
+	require.False(t, user.CanRun("KEYS", "", []string{CategoryRead}))
+	require.False(t, user.CanRun("SET", "", []string{CategoryWrite}))
+	require.True(t, user.CanRun("ACL", "WHOAMI", []string{CategoryAdmin}))
+	require.False(t, user.CanRun("ACL", "SETUSER", []string{CategoryAdmin}))
+
+	require.True(t, user.CanAccessKey("user:1"))
+	require.False(t, user.CanAccessKey("order:1"))
+	require.True(t, user.CanAccessPrefix("user:1"))
+	require.False(t, user.CanAccessPrefix("us"))
+
+	// The last matching rule wins
+	require.NoError(t, a.SetUser("reader", []string{"+keys"}))
+	user, _ = a.User("reader")
+	require.True(t, user.CanRun("KEYS", "", []string{CategoryRead}))
+
+	// -@all resets every command rule
+	require.NoError(t, a.SetUser("reader", []string{"nocommands"}))
+	user, _ = a.User("reader")
+	require.False(t, user.CanRun("GET", "", []string{CategoryRead}))
+}
+
+func TestInvalidRulesLeaveUserUnchanged(t *testing.T) {
+	a := New()
+	require.NoError(t, a.SetUser("alice", []string{"on", "nopass"}))
+	require.Error(t, a.SetUser("alice", []string{"off", "bogus"}))
+	user, _ := a.User("alice")
+	require.True(t, user.Enabled)
+
+	require.Error(t, ValidateRules("bob", []string{"#nothex"}))
+	_, ok := a.User("bob")
+	require.False(t, ok)
+}
+
+func TestListLoad(t *testing.T) {
+	a := New()
+	require.NoError(t, a.SetUser("alice", []string{"on", ">pw", "~user:", "~order:", "+@read", "-keys"}))
+	require.NoError(t, a.SetUser("bob", []string{"off", "nopass", "allkeys", "allcommands"}))
+
+	loaded := New()
+	require.NoError(t, loaded.Load(a.List()))
+	require.Equal(t, a.List(), loaded.List())
+
+	_, err := loaded.Authenticate("alice", "pw")
+	require.NoError(t, err)
+	_, err = loaded.Authenticate("bob", "")
+	require.Error(t, err)
+
+	require.NoError(t, loaded.Load(nil))
+	require.Len(t, loaded.List(), 1)
+}
+
+func TestHashPasswords(t *testing.T) {
+	rules := HashPasswords([]string{"on", ">pw", "<old", "~user:"})
+	require.Equal(t, []string{"on", "#" + hashPassword("pw"), "!" + hashPassword("old"), "~user:"}, rules)
+
+	a := New()
+	require.NoError(t, a.SetUser("alice", rules))
+	_, err := a.Authenticate("alice", "pw")
+	require.NoError(t, err)
+}
+
+func TestCustom(t *testing.T) {
+	a := New()
+	require.False(t, a.Custom())
+	require.NoError(t, a.SetUser(DefaultUser, []string{">pw"}))
+	require.True(t, a.Custom())
+	require.NoError(t, a.SetUser(DefaultUser, []string{"nopass"}))
+	require.False(t, a.Custom())
+	require.NoError(t, a.SetUser("alice", nil))
+	require.True(t, a.Custom())
+}
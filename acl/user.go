@@ -0,0 +1,258 @@
+package acl
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Command categories used by the command rules, +@category and -@category
+const (
+	CategoryAll         = "all"
+	CategoryRead        = "read"
+	CategoryWrite       = "write"
+	CategoryAdmin       = "admin"
+	CategoryPubSub      = "pubsub"
+	CategoryTransaction = "transaction"
+	CategoryConnection  = "connection"
+)
+
+// User is an ACL user. Users stored in an ACL are never modified, SetUser replaces them,
+// so a *User can be read without holding the ACL lock.
+type User struct {
+	Name    string
+	Enabled bool
+	NoPass  bool
+	// Passwords holds the hex encoded SHA-256 of every password of the user
+	Passwords []string
+	// Commands holds the command rules in the order they were given, the last matching rule wins
+	Commands []string
+	// AllKeys allows every key, otherwise only keys starting with one of KeyPrefixes are allowed
+	AllKeys     bool
+	KeyPrefixes []string
+}
+
+func newUser(name string) *User {
+	return &User{Name: name}
+}
+
+func (u *User) clone() *User {
+	c := *u
+	c.Passwords = slices.Clone(u.Passwords)
+	c.Commands = slices.Clone(u.Commands)
+	c.KeyPrefixes = slices.Clone(u.KeyPrefixes)
+	return &c
+}
+
+func hashPassword(password string) string {
+	sum := sha256.Sum256([]byte(password))
+	return hex.EncodeToString(sum[:])
+}
+
+// apply applies a single SETUSER rule to the user
+func (u *User) apply(rule string) error {
+	if rule == "" || strings.ContainsAny(rule, " \t\r\n") {
+		return fmt.Errorf("Error in ACL SETUSER modifier '%s': Syntax error", rule)
+	}
+	switch strings.ToLower(rule) {
+	case "on":
+		u.Enabled = true
+		return nil
+	case "off":
+		u.Enabled = false
+		return nil
+	case "nopass":
+		u.NoPass = true
+		u.Passwords = nil
+		return nil
+	case "resetpass":
+		u.NoPass = false
+		u.Passwords = nil
+		return nil
+	case "allkeys":
+		return u.apply("~*")
+	case "resetkeys":
+		u.AllKeys = false
+		u.KeyPrefixes = nil
+		return nil
+	case "allcommands":
+		return u.apply("+@all")
+	case "nocommands":
+		return u.apply("-@all")
+	case "reset":
+		*u = User{Name: u.Name}
+		return nil
+	}
+
+	switch rule[0] {
+	case '>':
+		u.addPassword(hashPassword(rule[1:]))
+	case '<':
+		u.Passwords = slices.DeleteFunc(u.Passwords, func(p string) bool { return p == hashPassword(rule[1:]) })
+	case '#', '!':
+		hash := strings.ToLower(rule[1:])
+		if decoded, err := hex.DecodeString(hash); err != nil || len(decoded) != sha256.Size {
+			return fmt.Errorf("Error in ACL SETUSER modifier '%s': The password hash must be exactly 64 characters and contain only lowercase hexadecimal characters", rule)
+		}
+		if rule[0] == '#' {
+			u.addPassword(hash)
+		} else {
+			u.Passwords = slices.DeleteFunc(u.Passwords, func(p string) bool { return p == hash })
+		}
+	case '~':
+		prefix := rule[1:]
+		if prefix == "*" {
+			u.AllKeys = true
+			u.KeyPrefixes = nil
+		} else if !u.AllKeys && !slices.Contains(u.KeyPrefixes, prefix) {
+			u.KeyPrefixes = append(u.KeyPrefixes, prefix)
+		}
+	case '+', '-':
+		if len(rule) == 1 || rule == "+@" || rule == "-@" {
+			return fmt.Errorf("Error in ACL SETUSER modifier '%s': Syntax error", rule)
+		}
+		normalized := strings.ToLower(rule)
+		if normalized[1:] == "@"+CategoryAll {
+			// +@all and -@all override every previous rule
+			u.Commands = []string{normalized}
+		} else {
+			u.Commands = append(u.Commands, normalized)
+		}
+	default:
+		return fmt.Errorf("Error in ACL SETUSER modifier '%s': Syntax error", rule)
+	}
+	return nil
+}
+
+func (u *User) addPassword(hash string) {
+	u.NoPass = false
+	if !slices.Contains(u.Passwords, hash) {
+		u.Passwords = append(u.Passwords, hash)
+	}
+}
+
+// CheckPassword tells if password is one of the passwords of the user
+func (u *User) CheckPassword(password string) bool {
+	if u.NoPass {
+		return true
+	}
+	hash := hashPassword(password)
+	for _, p := range u.Passwords {
+		if subtle.ConstantTimeCompare([]byte(p), []byte(hash)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// CanRun tells if the user may run command, subcommand is empty for commands without subcommands.
+// categories are the categories command belongs to.
+func (u *User) CanRun(command, subcommand string, categories []string) bool {
+	command = strings.ToLower(command)
+	full := command
+	if subcommand != "" {
+		full = command + "|" + strings.ToLower(subcommand)
+	}
+	allowed := false
+	for _, rule := range u.Commands {
+		target := rule[1:]
+		matches := false
+		if category, ok := strings.CutPrefix(target, "@"); ok {
+			matches = category == CategoryAll || slices.Contains(categories, category)
+		} else {
+			matches = target == command || target == full
+		}
+		if matches {
+			allowed = rule[0] == '+'
+		}
+	}
+	return allowed
+}
+
+// CanAccessKey tells if the user may access key
+func (u *User) CanAccessKey(key string) bool {
+	if u.AllKeys {
+		return true
+	}
+	for _, prefix := range u.KeyPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAccessPrefix tells if every key starting with prefix is accessible to the user
+func (u *User) CanAccessPrefix(prefix string) bool {
+	return u.CanAccessKey(prefix)
+}
+
+// Flags returns the flags of the user as shown by ACL GETUSER
+func (u *User) Flags() []string {
+	flags := []string{"off"}
+	if u.Enabled {
+		flags[0] = "on"
+	}
+	if u.NoPass {
+		flags = append(flags, "nopass")
+	}
+	if u.AllKeys {
+		flags = append(flags, "allkeys")
+	}
+	if len(u.Commands) == 1 && u.Commands[0] == "+@"+CategoryAll {
+		flags = append(flags, "allcommands")
+	}
+	return flags
+}
+
+// CommandRules returns the command rules of the user as a single string
+func (u *User) CommandRules() string {
+	if len(u.Commands) == 0 {
+		return "-@" + CategoryAll
+	}
+	return strings.Join(u.Commands, " ")
+}
+
+// KeyRules returns the key rules of the user as a single string
+func (u *User) KeyRules() string {
+	if u.AllKeys {
+		return "~*"
+	}
+	rules := make([]string, 0, len(u.KeyPrefixes))
+	for _, prefix := range u.KeyPrefixes {
+		rules = append(rules, "~"+prefix)
+	}
+	return strings.Join(rules, " ")
+}
+
+// Rules returns the SETUSER rules recreating the user
+func (u *User) Rules() []string {
+	rules := []string{"reset", "off"}
+	if u.Enabled {
+		rules[1] = "on"
+	}
+	if u.NoPass {
+		rules = append(rules, "nopass")
+	}
+	for _, p := range u.Passwords {
+		rules = append(rules, "#"+p)
+	}
+	if u.AllKeys {
+		rules = append(rules, "~*")
+	}
+	for _, prefix := range u.KeyPrefixes {
+		rules = append(rules, "~"+prefix)
+	}
+	if len(u.Commands) == 0 {
+		rules = append(rules, "-@"+CategoryAll)
+	}
+	return append(rules, u.Commands...)
+}
+
+// String describes the user the way ACL LIST does
+func (u *User) String() string {
+	return "user " + u.Name + " " + strings.Join(u.Rules()[1:], " ")
+}
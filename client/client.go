@@ -0,0 +1,269 @@
+// Package client is the Go client of Treds. It pools connections to the nodes of a cluster, pipelines commands,
+// iterates cursor scans and decodes the replies of the sorted map, collection and vector commands into typed
+// results. Writes can be sent to the Raft leader directly instead of being forwarded to it by a follower.
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"treds/resp"
+)
+
+var (
+	// ErrNil is returned when the key, member or field does not exist
+	ErrNil = errors.New("treds: nil")
+	// ErrClosed is returned by the calls made after Close
+	ErrClosed = errors.New("treds: client is closed")
+)
+
+// Options configure a Client
+type Options struct {
+	// Addrs are the client addresses of the nodes, host:port. Reads go to the first node reachable.
+	Addrs []string
+	// Username and Password authenticate the connections with AUTH when Password is set
+	Username string
+	Password string
+	// TLSConfig enables TLS when set
+	TLSConfig *tls.Config
+	// DialTimeout bounds opening a connection, 5 seconds by default
+	DialTimeout time.Duration
+	// PoolSize is the number of idle connections kept per node, 10 by default
+	PoolSize int
+	// RouteWritesToLeader sends writes to the Raft leader, found with HELLO. Without it writes go to the
+	// same node as reads, which forwards them to the leader.
+	RouteWritesToLeader bool
+}
+
+// Client is a Treds client, it is safe for concurrent use
+type Client struct {
+	opts Options
+
+	mu     sync.Mutex
+	pools  map[string]*pool
+	closed bool
+	// leader is the client address of the leader, empty until it is looked up
+	leader string
+	// writes are the commands flagged write by COMMAND, loaded when routing writes to the leader. It is empty
+	// when COMMAND failed, every command is then a read.
+	writes map[string]bool
+}
+
+// New returns a client of the nodes in opts.Addrs, connections are opened when first needed
+func New(opts Options) (*Client, error) {
+	if len(opts.Addrs) == 0 {
+		return nil, fmt.Errorf("treds: at least one address is required")
+	}
+	if opts.DialTimeout <= 0 {
+		opts.DialTimeout = 5 * time.Second
+	}
+	if opts.PoolSize <= 0 {
+		opts.PoolSize = 10
+	}
+	return &Client{opts: opts, pools: make(map[string]*pool)}, nil
+}
+
+// Close closes the idle connections, connections in use are closed when they are released
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	for _, p := range c.pools {
+		p.close()
+	}
+	return nil
+}
+
+// Do runs a command and returns its reply decoded like resp.ParseReply does.
+// An error reply is returned as a resp.ReplyError error.
+func (c *Client) Do(ctx context.Context, args ...string) (interface{}, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("treds: empty command")
+	}
+	replies, err := c.run(ctx, c.isWrite(ctx, args[0]), [][]string{args})
+	if err != nil {
+		return nil, err
+	}
+	if replyErr, ok := replies[0].(resp.ReplyError); ok {
+		return nil, replyErr
+	}
+	return replies[0], nil
+}
+
+// run sends cmds in a single round trip to the leader when write is set, to the first reachable node otherwise
+func (c *Client) run(ctx context.Context, write bool, cmds [][]string) ([]interface{}, error) {
+	addr := ""
+	if write && c.opts.RouteWritesToLeader {
+		addr = c.leaderAddr(ctx)
+	}
+	var cn *conn
+	var p *pool
+	var err error
+	if addr != "" {
+		p, err = c.pool(addr)
+		if err == nil {
+			cn, err = p.get(ctx)
+		}
+		if err != nil && !errors.Is(err, ErrClosed) {
+			// The leader may have changed, it is looked up again by the next write
+			c.forgetLeader(addr)
+			addr = ""
+		}
+	}
+	if addr == "" {
+		p, cn, err = c.anyNode(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	replies, err := cn.roundTrip(ctx, cmds)
+	p.put(cn)
+	if err != nil && write {
+		c.forgetLeader(p.addr)
+	}
+	return replies, err
+}
+
+// anyNode returns a connection to the first node reachable, in the order of opts.Addrs
+func (c *Client) anyNode(ctx context.Context) (*pool, *conn, error) {
+	var lastErr error
+	for _, addr := range c.opts.Addrs {
+		p, err := c.pool(addr)
+		if err != nil {
+			return nil, nil, err
+		}
+		cn, err := p.get(ctx)
+		if err == nil {
+			return p, cn, nil
+		}
+		lastErr = err
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, nil, lastErr
+}
+
+func (c *Client) pool(addr string) (*pool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil, ErrClosed
+	}
+	p, ok := c.pools[addr]
+	if !ok {
+		p = newPool(addr, &c.opts)
+		c.pools[addr] = p
+	}
+	return p, nil
+}
+
+// leaderAddr returns the client address of the leader, looking it up with HELLO when it is not known.
+// It is empty when the leader can not be found, writes then go to any node.
+func (c *Client) leaderAddr(ctx context.Context) string {
+	c.mu.Lock()
+	leader := c.leader
+	c.mu.Unlock()
+	if leader != "" {
+		return leader
+	}
+	p, cn, err := c.anyNode(ctx)
+	if err != nil {
+		return ""
+	}
+	// HELLO without a version keeps the protocol of the connection
+	replies, err := cn.roundTrip(ctx, [][]string{{"HELLO"}})
+	p.put(cn)
+	if err != nil {
+		return ""
+	}
+	hello, _ := replies[0].(map[string]interface{})
+	leader, _ = hello["leader"].(string)
+	c.mu.Lock()
+	c.leader = leader
+	c.mu.Unlock()
+	return leader
+}
+
+func (c *Client) forgetLeader(addr string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.leader == addr {
+		c.leader = ""
+	}
+}
+
+// isWrite tells if command writes, from the flags COMMAND reports. It is only needed to route writes
+// to the leader, every command is considered a read otherwise or when COMMAND fails. COMMAND is sent once.
+func (c *Client) isWrite(ctx context.Context, command string) bool {
+	if !c.opts.RouteWritesToLeader {
+		return false
+	}
+	c.mu.Lock()
+	writes := c.writes
+	c.mu.Unlock()
+	if writes == nil {
+		writes = c.loadWrites(ctx)
+	}
+	return writes[strings.ToLower(command)]
+}
+
+func (c *Client) loadWrites(ctx context.Context) map[string]bool {
+	// A failed COMMAND is kept as no write, so it is not sent again before every command
+	writes := make(map[string]bool)
+	var commands []interface{}
+	if replies, err := c.run(ctx, false, [][]string{{"COMMAND"}}); err == nil {
+		commands, _ = replies[0].([]interface{})
+	}
+	for _, command := range commands {
+		info, ok := command.([]interface{})
+		if !ok || len(info) < 3 {
+			continue
+		}
+		name, _ := info[0].(string)
+		flags, _ := info[2].([]interface{})
+		for _, flag := range flags {
+			if flag == "write" {
+				writes[name] = true
+			}
+		}
+	}
+	c.mu.Lock()
+	c.writes = writes
+	c.mu.Unlock()
+	return writes
+}
+
+// Stream sends a command which replies more than once, like SUBSCRIBE or MONITOR, on a connection of its own and
+// calls handle with every reply and pushed message until ctx is done or handle returns an error.
+func (c *Client) Stream(ctx context.Context, handle func(reply interface{}) error, args ...string) error {
+	p, cn, err := c.anyNode(ctx)
+	if err != nil {
+		return err
+	}
+	// The connection is left in a streaming state, it never goes back to the pool
+	cn.broken = true
+	defer p.put(cn)
+	stop := context.AfterFunc(ctx, func() { _ = cn.netConn.Close() })
+	defer stop()
+
+	if _, err = cn.wr.WriteString(resp.EncodeStringArray(args)); err == nil {
+		err = cn.wr.Flush()
+	}
+	for err == nil {
+		var reply interface{}
+		reply, err = resp.ParseReply(cn.rd)
+		if err == nil {
+			err = handle(reply)
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
@@ -0,0 +1,285 @@
+package client
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"treds/commands"
+	"treds/resp"
+	"treds/store"
+)
+
+// fakeNode serves the store commands of a registry over RESP3 without Raft, and answers HELLO with leader.
+// COMMAND fails when noCommand is set.
+type fakeNode struct {
+	listener  net.Listener
+	registry  commands.CommandRegistry
+	store     *store.TredsStore
+	leader    string
+	noCommand bool
+
+	mu       sync.Mutex
+	received []string
+}
+
+func newFakeNode(t *testing.T) *fakeNode {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	registry := commands.NewRegistry()
+	commands.RegisterCommands(registry)
+	node := &fakeNode{listener: listener, registry: registry, store: store.NewTredsStore()}
+	t.Cleanup(func() { _ = listener.Close() })
+	go node.serve()
+	return node
+}
+
+func (n *fakeNode) addr() string {
+	return n.listener.Addr().String()
+}
+
+func (n *fakeNode) commands() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]string{}, n.received...)
+}
+
+func (n *fakeNode) serve() {
+	for {
+		conn, err := n.listener.Accept()
+		if err != nil {
+			return
+		}
+		go n.handle(conn)
+	}
+}
+
+func (n *fakeNode) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := resp.NewReader()
+	buf := make([]byte, 4096)
+	for {
+		read, err := conn.Read(buf)
+		if err != nil {
+			return
+		}
+		if err = reader.Feed(buf[:read]); err != nil {
+			return
+		}
+		for {
+			_, args, ok, err := reader.Next()
+			if err != nil {
+				return
+			}
+			if !ok {
+				break
+			}
+			if _, err = conn.Write([]byte(n.reply(strings.ToUpper(args[0]), args[1:]))); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (n *fakeNode) reply(command string, args []string) string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.received = append(n.received, command)
+	switch command {
+	case "HELLO":
+		return resp.EncodeValueMap([]interface{}{"proto", 3, "leader", n.leader})
+	case "COMMAND":
+		if n.noCommand {
+			return resp.EncodeError("NOPERM this user has no permissions to run the 'command' command")
+		}
+		info := make([]interface{}, 0)
+		for _, reg := range n.registry.All() {
+			info = append(info, []interface{}{strings.ToLower(reg.Name), reg.Spec.Arity(), []interface{}{reg.Spec.Flags[0]}})
+		}
+		return resp.EncodeArray(info)
+	}
+	reg, err := n.registry.Retrieve(command)
+	if err != nil {
+		return resp.EncodeError(err.Error())
+	}
+	if err = reg.Validate(args); err != nil {
+		return resp.EncodeError(err.Error())
+	}
+	if reg.ExecuteRESP3 != nil {
+		return reg.ExecuteRESP3(args, n.store)
+	}
+	return reg.Execute(args, n.store)
+}
+
+func newTestClient(t *testing.T, opts Options) *Client {
+	c, err := New(opts)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func TestKeyValue(t *testing.T) {
+	node := newFakeNode(t)
+	c := newTestClient(t, Options{Addrs: []string{node.addr()}})
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "user:1", "alice"))
+	value, err := c.Get(ctx, "user:1")
+	require.NoError(t, err)
+	require.Equal(t, "alice", value)
+	_, err = c.Get(ctx, "missing")
+	require.ErrorIs(t, err, ErrNil)
+
+	require.NoError(t, c.MSet(ctx, KV{"user:2", "bob"}, KV{"users", "2"}))
+	values, err := c.MGet(ctx, "user:2", "missing")
+	require.NoError(t, err)
+	require.Equal(t, "bob", *values[0])
+	require.Nil(t, values[1])
+
+	kv, err := c.LongestPrefix(ctx, "user:1:settings")
+	require.NoError(t, err)
+	require.Equal(t, KV{"user:1", "alice"}, kv)
+
+	count, err := c.IncrBy(ctx, "users", 3)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), count)
+	ratio, err := c.IncrByFloat(ctx, "ratio", 0.5)
+	require.NoError(t, err)
+	require.Equal(t, 0.5, ratio)
+
+	_, err = c.Do(ctx, "ZSCORE", "user:1")
+	var replyErr resp.ReplyError
+	require.ErrorAs(t, err, &replyErr)
+	require.Equal(t, []string{"HELLO", "SET", "GET", "GET", "MSET", "MGET", "LNGPREFIX", "INCRBY", "INCRBYFLOAT", "ZSCORE"}, node.commands())
+}
+
+func TestScanIterator(t *testing.T) {
+	node := newFakeNode(t)
+	c := newTestClient(t, Options{Addrs: []string{node.addr()}})
+	ctx := context.Background()
+	for _, key := range []string{"a", "user:1", "user:2", "user:3", "user:4", "user:5"} {
+		require.NoError(t, c.Set(ctx, key, "v"+key))
+	}
+
+	var keys []string
+	it := c.ScanKeys("user:", 2)
+	for it.Next(ctx) {
+		keys = append(keys, it.Key())
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []string{"user:1", "user:2", "user:3", "user:4", "user:5"}, keys)
+
+	// KVS replies with a map over RESP3, pages keep the key order
+	var kvs []KV
+	it = c.KVS("^user:[12]", 10)
+	for it.Next(ctx) {
+		kvs = append(kvs, KV{it.Key(), it.Value()})
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []KV{{"user:1", "vuser:1"}, {"user:2", "vuser:2"}}, kvs)
+
+	it = c.Keys("(", 10)
+	require.False(t, it.Next(ctx))
+	require.Error(t, it.Err())
+}
+
+func TestSortedMapCollectionVector(t *testing.T) {
+	node := newFakeNode(t)
+	c := newTestClient(t, Options{Addrs: []string{node.addr()}})
+	ctx := context.Background()
+
+	require.NoError(t, c.ZAdd(ctx, "scores", ScoredKV{1.5, "alice", "a"}, ScoredKV{3, "bob", "b"}, ScoredKV{2, "carol", "c"}))
+	members, err := c.ZRangeByScore(ctx, "scores", 1, 2.5, 0, 10)
+	require.NoError(t, err)
+	require.Equal(t, []ScoredKV{{1.5, "alice", "a"}, {2, "carol", "c"}}, members)
+	members, err = c.ZRangeByLex(ctx, "scores", 0, 10, "b", "d")
+	require.NoError(t, err)
+	require.Equal(t, []ScoredKV{{3, "bob", "b"}, {2, "carol", "c"}}, members)
+	score, err := c.ZScore(ctx, "scores", "bob")
+	require.NoError(t, err)
+	require.Equal(t, 3.0, score)
+	_, err = c.ZScore(ctx, "scores", "dave")
+	require.ErrorIs(t, err, ErrNil)
+
+	require.NoError(t, c.DCreate(ctx, "users", "", ""))
+	_, err = c.DInsert(ctx, "users", map[string]interface{}{"name": "alice", "age": 30})
+	require.NoError(t, err)
+	documents, err := c.DQuery(ctx, "users", map[string]interface{}{"filters": []interface{}{}})
+	require.NoError(t, err)
+	require.Len(t, documents, 1)
+	var user struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+	require.NoError(t, documents[0].Decode(&user))
+	require.Equal(t, "alice", user.Name)
+	require.Equal(t, 30, user.Age)
+
+	require.NoError(t, c.VCreate(ctx, "points", VectorOptions{}))
+	id, err := c.VInsert(ctx, "points", []float64{1, 2})
+	require.NoError(t, err)
+	_, err = c.VInsert(ctx, "points", []float64{10, 20})
+	require.NoError(t, err)
+	matches, err := c.VSearch(ctx, "points", []float64{1.1, 2}, 1)
+	require.NoError(t, err)
+	require.Equal(t, []VectorMatch{{ID: id, Vector: []float64{1, 2}}}, matches)
+	deleted, err := c.VDelete(ctx, "points", id)
+	require.NoError(t, err)
+	require.True(t, deleted)
+}
+
+func TestPipeline(t *testing.T) {
+	node := newFakeNode(t)
+	c := newTestClient(t, Options{Addrs: []string{node.addr()}})
+	ctx := context.Background()
+
+	p := c.Pipeline()
+	p.Queue("SET", "k", "v")
+	p.Queue("GET", "k")
+	p.Queue("GET")
+	require.Equal(t, 3, p.Len())
+	replies, err := p.Exec(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "OK", replies[0])
+	require.Equal(t, "v", replies[1])
+	require.IsType(t, resp.ReplyError(""), replies[2])
+	require.Equal(t, 0, p.Len())
+}
+
+func TestRouteWritesToLeader(t *testing.T) {
+	follower, leader := newFakeNode(t), newFakeNode(t)
+	follower.leader = leader.addr()
+	c := newTestClient(t, Options{
+		// The first address is unreachable, reads go to the next node
+		Addrs:               []string{"127.0.0.1:1", follower.addr(), leader.addr()},
+		RouteWritesToLeader: true,
+	})
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "k", "v"))
+	_, err := c.Get(ctx, "k")
+	require.ErrorIs(t, err, ErrNil)
+	p := c.Pipeline()
+	p.Queue("GET", "k")
+	p.Queue("DEL", "k")
+	_, err = p.Exec(ctx)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"HELLO", "COMMAND", "HELLO", "GET"}, follower.commands())
+	require.Equal(t, []string{"HELLO", "SET", "GET", "DEL"}, leader.commands())
+}
+
+func TestRouteWritesWithoutCommand(t *testing.T) {
+	node := newFakeNode(t)
+	node.noCommand = true
+	c := newTestClient(t, Options{Addrs: []string{node.addr()}, RouteWritesToLeader: true})
+	ctx := context.Background()
+
+	// Every command is a read when COMMAND fails, it is not sent again
+	require.NoError(t, c.Set(ctx, "k", "v"))
+	require.NoError(t, c.Set(ctx, "k", "w"))
+	require.Equal(t, []string{"HELLO", "COMMAND", "SET", "SET"}, node.commands())
+}
@@ -0,0 +1,327 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+)
+
+// Set sets the value of key
+func (c *Client) Set(ctx context.Context, key string, value string) error {
+	_, err := c.Do(ctx, "SET", key, value)
+	return err
+}
+
+// Get returns the value of key, ErrNil when it does not exist
+func (c *Client) Get(ctx context.Context, key string) (string, error) {
+	reply, err := c.Do(ctx, "GET", key)
+	if err != nil {
+		return "", err
+	}
+	return toString(reply)
+}
+
+// MSet sets the values of several keys
+func (c *Client) MSet(ctx context.Context, kvs ...KV) error {
+	args := []string{"MSET"}
+	for _, kv := range kvs {
+		args = append(args, kv.Key, kv.Value)
+	}
+	_, err := c.Do(ctx, args...)
+	return err
+}
+
+// MGet returns the values of keys, nil for the keys which do not exist
+func (c *Client) MGet(ctx context.Context, keys ...string) ([]*string, error) {
+	reply, err := c.Do(ctx, append([]string{"MGET"}, keys...)...)
+	if err != nil {
+		return nil, err
+	}
+	elements, _ := reply.([]interface{})
+	values := make([]*string, 0, len(elements))
+	for _, element := range elements {
+		value, err := toString(element)
+		if err == ErrNil {
+			values = append(values, nil)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, &value)
+	}
+	return values, nil
+}
+
+// IncrBy adds delta to the integer value of key, a missing key counts as 0, and returns the new value
+func (c *Client) IncrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	reply, err := c.Do(ctx, "INCRBY", key, strconv.FormatInt(delta, 10))
+	if err != nil {
+		return 0, err
+	}
+	return toInt(reply)
+}
+
+// IncrByFloat adds delta to the float value of key, a missing key counts as 0, and returns the new value
+func (c *Client) IncrByFloat(ctx context.Context, key string, delta float64) (float64, error) {
+	reply, err := c.Do(ctx, "INCRBYFLOAT", key, formatFloat(delta))
+	if err != nil {
+		return 0, err
+	}
+	return toFloat(reply)
+}
+
+// Del deletes key
+func (c *Client) Del(ctx context.Context, key string) error {
+	_, err := c.Do(ctx, "DEL", key)
+	return err
+}
+
+// DelPrefix deletes the keys having prefix and returns how many were deleted
+func (c *Client) DelPrefix(ctx context.Context, prefix string) (int64, error) {
+	reply, err := c.Do(ctx, "DELPREFIX", prefix)
+	if err != nil {
+		return 0, err
+	}
+	return toInt(reply)
+}
+
+// LongestPrefix returns the key which is the longest prefix of s and its value, ErrNil when there is none
+func (c *Client) LongestPrefix(ctx context.Context, s string) (KV, error) {
+	reply, err := c.Do(ctx, "LNGPREFIX", s)
+	if err != nil {
+		return KV{}, err
+	}
+	strs, err := toStrings(reply)
+	if err != nil {
+		return KV{}, err
+	}
+	if len(strs) == 0 {
+		return KV{}, ErrNil
+	}
+	kvs, err := toKVs(strs)
+	if err != nil {
+		return KV{}, err
+	}
+	return kvs[0], nil
+}
+
+// Expire expires key after seconds
+func (c *Client) Expire(ctx context.Context, key string, seconds int) error {
+	_, err := c.Do(ctx, "EXPIRE", key, strconv.Itoa(seconds))
+	return err
+}
+
+// TTL returns the seconds before key expires, -1 when it does not expire and -2 when it does not exist
+func (c *Client) TTL(ctx context.Context, key string) (int64, error) {
+	reply, err := c.Do(ctx, "TTL", key)
+	if err != nil {
+		return 0, err
+	}
+	return toInt(reply)
+}
+
+// ZAdd adds members to the sorted map key, members already in it are updated
+func (c *Client) ZAdd(ctx context.Context, key string, members ...ScoredKV) error {
+	args := []string{"ZADD", key}
+	for _, member := range members {
+		args = append(args, formatFloat(member.Score), member.Key, member.Value)
+	}
+	_, err := c.Do(ctx, args...)
+	return err
+}
+
+// ZRem removes members from the sorted map key
+func (c *Client) ZRem(ctx context.Context, key string, members ...string) error {
+	_, err := c.Do(ctx, append([]string{"ZREM", key}, members...)...)
+	return err
+}
+
+// ZScore returns the score of member in the sorted map key, ErrNil when it is not a member
+func (c *Client) ZScore(ctx context.Context, key string, member string) (float64, error) {
+	reply, err := c.Do(ctx, "ZSCORE", key, member)
+	if err != nil {
+		return 0, err
+	}
+	return toFloat(reply)
+}
+
+// ZIncrBy adds delta to the score of member in the sorted map key and returns the new score,
+// a missing member is added with an empty value
+func (c *Client) ZIncrBy(ctx context.Context, key string, delta float64, member string) (float64, error) {
+	reply, err := c.Do(ctx, "ZINCRBY", key, formatFloat(delta), member)
+	if err != nil {
+		return 0, err
+	}
+	return toFloat(reply)
+}
+
+// ZCard returns the number of members of the sorted map key
+func (c *Client) ZCard(ctx context.Context, key string) (int64, error) {
+	reply, err := c.Do(ctx, "ZCARD", key)
+	if err != nil {
+		return 0, err
+	}
+	return toInt(reply)
+}
+
+// ZRange returns the members of the sorted map key from index start to end, excluded, in key order
+func (c *Client) ZRange(ctx context.Context, key string, start int, end int) ([]ScoredKV, error) {
+	return c.zrange(ctx, "ZRANGE", key, strconv.Itoa(start), strconv.Itoa(end), "true")
+}
+
+// ZRangeByScore returns up to count members of the sorted map key with a score between min and max,
+// lowest score first, skipping the first offset members
+func (c *Client) ZRangeByScore(ctx context.Context, key string, min, max float64, offset, count int) ([]ScoredKV, error) {
+	return c.zrange(ctx, "ZRANGESCOREKVS", key, formatFloat(min), formatFloat(max), strconv.Itoa(offset), strconv.Itoa(count), "true")
+}
+
+// ZRevRangeByScore is ZRangeByScore with the highest score first
+func (c *Client) ZRevRangeByScore(ctx context.Context, key string, min, max float64, offset, count int) ([]ScoredKV, error) {
+	return c.zrange(ctx, "ZREVRANGESCOREKVS", key, formatFloat(min), formatFloat(max), strconv.Itoa(offset), strconv.Itoa(count), "true")
+}
+
+// ZRangeByLex returns up to count members of the sorted map key with a key between min and max, in key order,
+// skipping the first offset members. Both bounds are inclusive.
+func (c *Client) ZRangeByLex(ctx context.Context, key string, offset, count int, min, max string) ([]ScoredKV, error) {
+	return c.zrange(ctx, "ZRANGELEXKVS", key, strconv.Itoa(offset), strconv.Itoa(count), "true", min, max)
+}
+
+// ZRevRangeByLex is ZRangeByLex in reverse key order
+func (c *Client) ZRevRangeByLex(ctx context.Context, key string, offset, count int, min, max string) ([]ScoredKV, error) {
+	return c.zrange(ctx, "ZREVRANGELEXKVS", key, strconv.Itoa(offset), strconv.Itoa(count), "true", min, max)
+}
+
+func (c *Client) zrange(ctx context.Context, args ...string) ([]ScoredKV, error) {
+	reply, err := c.Do(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	return toScoredKVs(reply)
+}
+
+// DCreate creates the collection name. schema and indexes are JSON, empty to leave them out.
+func (c *Client) DCreate(ctx context.Context, name string, schema string, indexes string) error {
+	args := []string{"DCREATE", name}
+	if schema != "" || indexes != "" {
+		args = append(args, schema)
+	}
+	if indexes != "" {
+		args = append(args, indexes)
+	}
+	_, err := c.Do(ctx, args...)
+	return err
+}
+
+// DDrop drops the collection name
+func (c *Client) DDrop(ctx context.Context, name string) error {
+	_, err := c.Do(ctx, "DDROP", name)
+	return err
+}
+
+// DInsert inserts document in the collection name and returns its id.
+// A string or Document is sent as is, any other value is marshalled to JSON.
+func (c *Client) DInsert(ctx context.Context, name string, document interface{}) (string, error) {
+	doc, err := marshalJSON(document)
+	if err != nil {
+		return "", err
+	}
+	reply, err := c.Do(ctx, "DINSERT", name, doc)
+	if err != nil {
+		return "", err
+	}
+	return toString(reply)
+}
+
+// DQuery returns the documents of the collection name matching query, which is marshalled like a DInsert document
+func (c *Client) DQuery(ctx context.Context, name string, query interface{}) ([]Document, error) {
+	q, err := marshalJSON(query)
+	if err != nil {
+		return nil, err
+	}
+	reply, err := c.Do(ctx, "DQUERY", name, q)
+	if err != nil {
+		return nil, err
+	}
+	strs, err := toStrings(reply)
+	if err != nil {
+		return nil, err
+	}
+	documents := make([]Document, 0, len(strs))
+	for _, s := range strs {
+		documents = append(documents, Document(s))
+	}
+	return documents, nil
+}
+
+// DExplain returns the plan of query on the collection name
+func (c *Client) DExplain(ctx context.Context, name string, query interface{}) (string, error) {
+	q, err := marshalJSON(query)
+	if err != nil {
+		return "", err
+	}
+	reply, err := c.Do(ctx, "DEXPLAIN", name, q)
+	if err != nil {
+		return "", err
+	}
+	return toString(reply)
+}
+
+// VCreate creates the vector index name
+func (c *Client) VCreate(ctx context.Context, name string, opts VectorOptions) error {
+	args := []string{"VCREATE", name}
+	if opts != (VectorOptions{}) {
+		args = append(args, strconv.Itoa(opts.MaxNeighbors), formatFloat(opts.LevelFactor), strconv.Itoa(opts.EfSearch))
+	}
+	_, err := c.Do(ctx, args...)
+	return err
+}
+
+// VInsert inserts vector in the index name and returns its id
+func (c *Client) VInsert(ctx context.Context, name string, vector []float64) (string, error) {
+	args := []string{"VINSERT", name}
+	for _, component := range vector {
+		args = append(args, formatFloat(component))
+	}
+	reply, err := c.Do(ctx, args...)
+	if err != nil {
+		return "", err
+	}
+	return toString(reply)
+}
+
+// VSearch returns the k vectors of the index name nearest to vector, nearest first
+func (c *Client) VSearch(ctx context.Context, name string, vector []float64, k int) ([]VectorMatch, error) {
+	args := []string{"VSEARCH", name}
+	for _, component := range vector {
+		args = append(args, formatFloat(component))
+	}
+	reply, err := c.Do(ctx, append(args, strconv.Itoa(k))...)
+	if err != nil {
+		return nil, err
+	}
+	return toVectorMatches(reply)
+}
+
+// VDelete deletes the vector id from the index name and tells if it was found
+func (c *Client) VDelete(ctx context.Context, name string, id string) (bool, error) {
+	reply, err := c.Do(ctx, "VDELETE", name, id)
+	if err != nil {
+		return false, err
+	}
+	return reply == "OK", nil
+}
+
+func marshalJSON(v interface{}) (string, error) {
+	switch doc := v.(type) {
+	case string:
+		return doc, nil
+	case Document:
+		return string(doc), nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
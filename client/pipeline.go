@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+)
+
+// Pipeline queues commands and sends them in a single round trip. The commands are not run as a transaction,
+// use MULTI and EXEC for that. A pipeline is not safe for concurrent use.
+type Pipeline struct {
+	c    *Client
+	cmds [][]string
+}
+
+// Pipeline returns an empty pipeline
+func (c *Client) Pipeline() *Pipeline {
+	return &Pipeline{c: c}
+}
+
+// Queue adds a command to the pipeline
+func (p *Pipeline) Queue(args ...string) {
+	p.cmds = append(p.cmds, args)
+}
+
+// Len is the number of commands queued
+func (p *Pipeline) Len() int {
+	return len(p.cmds)
+}
+
+// Exec sends the queued commands and returns their replies in order, the pipeline is then empty.
+// Error replies are returned as resp.ReplyError values, err is only set when the commands could not be sent.
+// A pipeline holding a write goes to the leader when writes are routed to it.
+func (p *Pipeline) Exec(ctx context.Context) ([]interface{}, error) {
+	cmds := p.cmds
+	p.cmds = nil
+	if len(cmds) == 0 {
+		return nil, nil
+	}
+	write := false
+	for _, cmd := range cmds {
+		if len(cmd) > 0 && p.c.isWrite(ctx, cmd[0]) {
+			write = true
+			break
+		}
+	}
+	return p.c.run(ctx, write, cmds)
+}
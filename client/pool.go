@@ -0,0 +1,149 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"treds/resp"
+)
+
+// conn is a connection to a node, speaking RESP3
+type conn struct {
+	netConn net.Conn
+	rd      *bufio.Reader
+	wr      *bufio.Writer
+	// broken connections are closed instead of going back to the pool
+	broken bool
+}
+
+// pool keeps idle connections to a node
+type pool struct {
+	addr string
+	opts *Options
+
+	mu     sync.Mutex
+	idle   []*conn
+	closed bool
+}
+
+func newPool(addr string, opts *Options) *pool {
+	return &pool{addr: addr, opts: opts}
+}
+
+// get returns an idle connection or dials a new one
+func (p *pool) get(ctx context.Context) (*conn, error) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil, ErrClosed
+	}
+	if n := len(p.idle); n > 0 {
+		cn := p.idle[n-1]
+		p.idle = p.idle[:n-1]
+		p.mu.Unlock()
+		return cn, nil
+	}
+	p.mu.Unlock()
+	return p.dial(ctx)
+}
+
+// put returns cn to the pool, it is closed when broken or when enough connections are idle
+func (p *pool) put(cn *conn) {
+	p.mu.Lock()
+	if !cn.broken && !p.closed && len(p.idle) < p.opts.PoolSize {
+		p.idle = append(p.idle, cn)
+		p.mu.Unlock()
+		return
+	}
+	p.mu.Unlock()
+	_ = cn.netConn.Close()
+}
+
+func (p *pool) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	for _, cn := range p.idle {
+		_ = cn.netConn.Close()
+	}
+	p.idle = nil
+}
+
+// dial opens a connection, authenticates it and switches it to RESP3
+func (p *pool) dial(ctx context.Context) (*conn, error) {
+	dialer := &net.Dialer{Timeout: p.opts.DialTimeout}
+	var netConn net.Conn
+	var err error
+	if p.opts.TLSConfig != nil {
+		netConn, err = (&tls.Dialer{NetDialer: dialer, Config: p.opts.TLSConfig}).DialContext(ctx, "tcp", p.addr)
+	} else {
+		netConn, err = dialer.DialContext(ctx, "tcp", p.addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	cn := &conn{netConn: netConn, rd: bufio.NewReader(netConn), wr: bufio.NewWriter(netConn)}
+
+	handshake := make([][]string, 0, 2)
+	if p.opts.Password != "" {
+		auth := []string{"AUTH", p.opts.Password}
+		if p.opts.Username != "" {
+			auth = []string{"AUTH", p.opts.Username, p.opts.Password}
+		}
+		handshake = append(handshake, auth)
+	}
+	handshake = append(handshake, []string{"HELLO", "3"})
+	replies, err := cn.roundTrip(ctx, handshake)
+	if err == nil {
+		for _, reply := range replies {
+			if replyErr, ok := reply.(resp.ReplyError); ok {
+				err = fmt.Errorf("handshake with %s failed: %w", p.addr, replyErr)
+				break
+			}
+		}
+	}
+	if err != nil {
+		_ = netConn.Close()
+		return nil, err
+	}
+	return cn, nil
+}
+
+// roundTrip sends the commands in a single write and reads their replies in order.
+// Error replies are returned as resp.ReplyError values, err is only set when the connection failed.
+func (cn *conn) roundTrip(ctx context.Context, cmds [][]string) ([]interface{}, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Time{}
+	}
+	if err := cn.netConn.SetDeadline(deadline); err != nil {
+		cn.broken = true
+		return nil, err
+	}
+	for _, cmd := range cmds {
+		if _, err := cn.wr.WriteString(resp.EncodeStringArray(cmd)); err != nil {
+			cn.broken = true
+			return nil, err
+		}
+	}
+	if err := cn.wr.Flush(); err != nil {
+		cn.broken = true
+		return nil, err
+	}
+	replies := make([]interface{}, 0, len(cmds))
+	for range cmds {
+		reply, err := resp.ParseReply(cn.rd)
+		if err != nil {
+			// The stream can not be resynchronised once a reply is partially read
+			cn.broken = true
+			return nil, err
+		}
+		replies = append(replies, reply)
+	}
+	return replies, nil
+}
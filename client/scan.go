@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// ScanIterator iterates the keys, or the key value pairs, of a cursor scan page by page.
+// Treds scans end every page with the cursor of the next one, which is 0 once the scan is done.
+//
+//	it := c.ScanKVS("user:", 100)
+//	for it.Next(ctx) {
+//		fmt.Println(it.Key(), it.Value())
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type ScanIterator struct {
+	c       *Client
+	command string
+	// match is the prefix or the regex of the scan
+	match  string
+	count  int
+	values bool
+
+	cursor  string
+	started bool
+	page    []KV
+	current KV
+	err     error
+}
+
+// ScanKeys iterates the keys having prefix with SCANKEYS, fetching count keys per page
+func (c *Client) ScanKeys(prefix string, count int) *ScanIterator {
+	return c.scan("SCANKEYS", prefix, count, false)
+}
+
+// ScanKVS iterates the key value pairs having prefix with SCANKVS, fetching count pairs per page
+func (c *Client) ScanKVS(prefix string, count int) *ScanIterator {
+	return c.scan("SCANKVS", prefix, count, true)
+}
+
+// Keys iterates the keys matching regex with KEYS, fetching count keys per page
+func (c *Client) Keys(regex string, count int) *ScanIterator {
+	return c.scan("KEYS", regex, count, false)
+}
+
+// KVS iterates the key value pairs whose key matches regex with KVS, fetching count pairs per page
+func (c *Client) KVS(regex string, count int) *ScanIterator {
+	return c.scan("KVS", regex, count, true)
+}
+
+func (c *Client) scan(command string, match string, count int, values bool) *ScanIterator {
+	return &ScanIterator{c: c, command: command, match: match, count: count, values: values, cursor: "0"}
+}
+
+// Next moves to the next key, fetching the next page when needed. It returns false at the end of the scan or on error.
+func (it *ScanIterator) Next(ctx context.Context) bool {
+	for len(it.page) == 0 {
+		if it.err != nil || (it.started && it.cursor == "0") {
+			return false
+		}
+		it.fetch(ctx)
+	}
+	it.current, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Key is the current key
+func (it *ScanIterator) Key() string {
+	return it.current.Key
+}
+
+// Value is the value of the current key, empty when only keys are scanned
+func (it *ScanIterator) Value() string {
+	return it.current.Value
+}
+
+// Err returns the error which ended the scan
+func (it *ScanIterator) Err() error {
+	return it.err
+}
+
+func (it *ScanIterator) fetch(ctx context.Context) {
+	it.started = true
+	reply, err := it.c.Do(ctx, it.command, it.cursor, it.match, strconv.Itoa(it.count))
+	if err != nil {
+		it.err = err
+		return
+	}
+	it.page, it.cursor, it.err = parseScanPage(reply, it.values)
+}
+
+// parseScanPage parses a page and the cursor ending it. Over RESP3 KVS replies with a map of the page and
+// the cursor, the map is sorted back into the key order of the scan.
+func parseScanPage(reply interface{}, values bool) ([]KV, string, error) {
+	elements, ok := reply.([]interface{})
+	if !ok || len(elements) == 0 {
+		return nil, "", fmt.Errorf("treds: unexpected scan reply %v", reply)
+	}
+	cursor, err := toString(elements[len(elements)-1])
+	if err != nil {
+		return nil, "", err
+	}
+	elements = elements[:len(elements)-1]
+	if m, isMap := pageMap(elements); isMap {
+		page := make([]KV, 0, len(m))
+		for key, value := range m {
+			page = append(page, KV{Key: key, Value: fmt.Sprint(value)})
+		}
+		sort.Slice(page, func(i, j int) bool { return page[i].Key < page[j].Key })
+		return page, cursor, nil
+	}
+
+	strs, err := toStrings(elements)
+	if err != nil {
+		return nil, "", err
+	}
+	if !values {
+		page := make([]KV, 0, len(strs))
+		for _, key := range strs {
+			page = append(page, KV{Key: key})
+		}
+		return page, cursor, nil
+	}
+	page, err := toKVs(strs)
+	return page, cursor, err
+}
+
+func pageMap(elements []interface{}) (map[string]interface{}, bool) {
+	if len(elements) != 1 {
+		return nil, false
+	}
+	m, ok := elements[0].(map[string]interface{})
+	return m, ok
+}
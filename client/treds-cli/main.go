@@ -0,0 +1,69 @@
+// Command treds-cli is the interactive client of Treds. It keeps a history, completes command names, hints
+// their arguments from COMMAND DOCS and renders scan cursors, DQUERY documents and VSEARCH results.
+// It can also bulk-load a file of commands with --pipe and follow pub/sub channels with --watch.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"treds/client"
+)
+
+func main() {
+	host := flag.String("host", "localhost", "Host of the Treds server")
+	port := flag.Int("p", 7997, "Port of the Treds server")
+	user := flag.String("user", "", "Username to authenticate with")
+	password := flag.String("a", "", "Password to authenticate with")
+	pipe := flag.String("pipe", "", "Run the commands of a file, one per line, - for stdin")
+	watch := flag.String("watch", "", "Print the messages of comma separated pub/sub channels, a channel ending with * is a prefix")
+	flag.Parse()
+
+	c, err := client.New(client.Options{
+		Addrs:    []string{net.JoinHostPort(*host, strconv.Itoa(*port))},
+		Username: *user,
+		Password: *password,
+	})
+	if err != nil {
+		exit(err)
+	}
+	defer c.Close()
+
+	switch {
+	case *pipe != "":
+		err = runPipe(context.Background(), c, *pipe, os.Stdout)
+	case *watch != "":
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		err = runWatch(ctx, c, strings.Split(*watch, ","), os.Stdout)
+	case flag.NArg() > 0:
+		// A command given as arguments is run once, like redis-cli does
+		err = runOnce(context.Background(), c, flag.Args())
+	default:
+		err = runREPL(c, net.JoinHostPort(*host, strconv.Itoa(*port)))
+	}
+	if err != nil {
+		exit(err)
+	}
+}
+
+func runOnce(ctx context.Context, c *client.Client, args []string) error {
+	reply, err := c.Do(ctx, args...)
+	if err != nil {
+		return err
+	}
+	fmt.Println(render(args[0], reply))
+	return nil
+}
+
+func exit(err error) {
+	fmt.Fprintln(os.Stderr, "treds-cli:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"treds/client"
+	"treds/resp"
+)
+
+const (
+	// pipeBatch is the number of commands sent per round trip by --pipe
+	pipeBatch = 1000
+	// maxPipeLine bounds the length of a command line read by --pipe
+	maxPipeLine = 64 * 1024 * 1024
+)
+
+// runPipe runs the commands of path, one inline command per line, in pipelined batches. Empty lines and
+// lines starting with # are skipped. Lines which can not be parsed and error replies are printed with
+// their line number and counted.
+func runPipe(ctx context.Context, c *client.Client, path string, out io.Writer) error {
+	in := os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	pipeline := c.Pipeline()
+	lines := make([]int, 0, pipeBatch)
+	replies, failed := 0, 0
+	flush := func() error {
+		results, err := pipeline.Exec(ctx)
+		if err != nil {
+			return err
+		}
+		for i, result := range results {
+			if replyErr, ok := result.(resp.ReplyError); ok {
+				failed++
+				fmt.Fprintf(out, "line %d: (error) %s\n", lines[i], string(replyErr))
+			}
+		}
+		replies += len(results)
+		lines = lines[:0]
+		return nil
+	}
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxPipeLine)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		args, err := resp.SplitArgs(line)
+		if err != nil {
+			failed++
+			fmt.Fprintf(out, "line %d: (error) %s\n", number, err)
+			continue
+		}
+		pipeline.Queue(args...)
+		lines = append(lines, number)
+		if pipeline.Len() == pipeBatch {
+			if err = flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "replies: %d, errors: %d\n", replies, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d commands failed", failed)
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"treds/resp"
+)
+
+// statusReplies are printed without quotes, the parsed reply does not tell simple strings from bulk strings
+var statusReplies = map[string]bool{"OK": true, "PONG": true, "QUEUED": true}
+
+// render formats reply like redis-cli does. The scans print their cursor apart, DQUERY prints the documents
+// indented and VSEARCH prints every match on a line.
+func render(command string, reply interface{}) string {
+	if err, ok := reply.(resp.ReplyError); ok {
+		return "(error) " + string(err)
+	}
+	elements, isArray := reply.([]interface{})
+	switch strings.ToUpper(command) {
+	case "SCANKEYS", "KEYS":
+		if isArray && len(elements) > 0 {
+			return renderScan(elements, false)
+		}
+	case "SCANKVS", "KVS":
+		if isArray && len(elements) > 0 {
+			return renderScan(elements, true)
+		}
+	case "DQUERY":
+		if isArray {
+			return renderDocuments(elements)
+		}
+	case "VSEARCH":
+		if isArray {
+			return renderMatches(elements)
+		}
+	}
+	if s, ok := reply.(string); ok && (statusReplies[s] || strings.Contains(s, "\n")) {
+		return strings.TrimRight(s, "\r\n")
+	}
+	return renderValue(reply, "")
+}
+
+// renderValue formats a reply, nested lines are indented by indent
+func renderValue(reply interface{}, indent string) string {
+	switch v := reply.(type) {
+	case nil:
+		return "(nil)"
+	case string:
+		return strconv.Quote(v)
+	case int64:
+		return "(integer) " + strconv.FormatInt(v, 10)
+	case float64:
+		return "(double) " + strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		if v {
+			return "(true)"
+		}
+		return "(false)"
+	case resp.ReplyError:
+		return "(error) " + string(v)
+	case []interface{}:
+		if len(v) == 0 {
+			return "(empty array)"
+		}
+		lines := make([]string, 0, len(v))
+		for i, element := range v {
+			lines = append(lines, numbered(i, len(v), indent, func(next string) string {
+				return renderValue(element, next)
+			}))
+		}
+		return strings.Join(lines, "\n")
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return "(empty hash)"
+		}
+		keys := sortedKeys(v)
+		lines := make([]string, 0, len(keys))
+		for i, key := range keys {
+			lines = append(lines, numbered(i, len(keys), indent, func(next string) string {
+				return strconv.Quote(key) + " => " + renderValue(v[key], next+strings.Repeat(" ", len(strconv.Quote(key))+4))
+			}))
+		}
+		return strings.Join(lines, "\n")
+	}
+	return fmt.Sprint(reply)
+}
+
+// numbered prefixes the i-th of n elements with its position, the first one is not indented since it
+// continues the line of its parent
+func numbered(i int, n int, indent string, format func(next string) string) string {
+	width := len(strconv.Itoa(n))
+	prefix := fmt.Sprintf("%*d) ", width, i+1)
+	line := prefix + format(indent+strings.Repeat(" ", len(prefix)))
+	if i > 0 {
+		line = indent + line
+	}
+	return line
+}
+
+// renderScan prints the page of a scan, key value pairs on one line for KVS, followed by the cursor
+func renderScan(elements []interface{}, values bool) string {
+	cursor := renderValue(elements[len(elements)-1], "")
+	if s, ok := elements[len(elements)-1].(string); ok {
+		cursor = s
+	}
+	page := elements[:len(elements)-1]
+	var lines []string
+	if len(page) == 1 {
+		if m, ok := page[0].(map[string]interface{}); ok {
+			// RESP3 replies the pairs as a map
+			for i, key := range sortedKeys(m) {
+				lines = append(lines, pairLine(i, len(m), key, m[key]))
+			}
+			return scanLines(lines, cursor)
+		}
+	}
+	if values {
+		for i := 0; i+1 < len(page); i += 2 {
+			key, _ := page[i].(string)
+			lines = append(lines, pairLine(i/2, len(page)/2, key, page[i+1]))
+		}
+		return scanLines(lines, cursor)
+	}
+	for i, key := range page {
+		lines = append(lines, numbered(i, len(page), "", func(string) string { return renderValue(key, "") }))
+	}
+	return scanLines(lines, cursor)
+}
+
+func pairLine(i int, n int, key string, value interface{}) string {
+	return numbered(i, n, "", func(string) string { return strconv.Quote(key) + " => " + renderValue(value, "") })
+}
+
+func scanLines(lines []string, cursor string) string {
+	if len(lines) == 0 {
+		lines = append(lines, "(empty page)")
+	}
+	return strings.Join(append(lines, "(cursor) "+cursor), "\n")
+}
+
+// renderDocuments prints the JSON documents of DQUERY indented, documents which are not JSON are quoted
+func renderDocuments(elements []interface{}) string {
+	if len(elements) == 0 {
+		return "(no documents)"
+	}
+	lines := make([]string, 0, len(elements))
+	for i, element := range elements {
+		lines = append(lines, numbered(i, len(elements), "", func(next string) string {
+			s, ok := element.(string)
+			if !ok {
+				return renderValue(element, next)
+			}
+			var indented bytes.Buffer
+			if err := json.Indent(&indented, []byte(s), next, "  "); err != nil {
+				return strconv.Quote(s)
+			}
+			return indented.String()
+		}))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderMatches prints the matches of VSEARCH in the order of the reply, each its id followed by its vector
+func renderMatches(rows []interface{}) string {
+	if len(rows) == 0 {
+		return "(no matches)"
+	}
+	lines := make([]string, 0, len(rows))
+	for i, row := range rows {
+		lines = append(lines, numbered(i, len(rows), "", func(next string) string {
+			fields, ok := row.([]interface{})
+			if !ok || len(fields) == 0 {
+				return renderValue(row, next)
+			}
+			components := make([]string, 0, len(fields)-1)
+			for _, component := range fields[1:] {
+				components = append(components, fmt.Sprint(component))
+			}
+			return fmt.Sprintf("%v [%s]", fields[0], strings.Join(components, ", "))
+		}))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
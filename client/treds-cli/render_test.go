@@ -0,0 +1,71 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"treds/commands"
+	"treds/resp"
+)
+
+func TestRender(t *testing.T) {
+	require.Equal(t, "OK", render("SET", "OK"))
+	require.Equal(t, `"v"`, render("GET", "v"))
+	require.Equal(t, "(nil)", render("GET", nil))
+	require.Equal(t, "(integer) 3", render("DBSIZE", int64(3)))
+	require.Equal(t, "(error) ERR wrong type", render("GET", resp.ReplyError("ERR wrong type")))
+	require.Equal(t, "1) \"a\"\n2) 1) (integer) 1\n   2) (double) 2.5", render("X", []interface{}{"a", []interface{}{int64(1), 2.5}}))
+	require.Equal(t, "1) \"a\" => (integer) 1\n2) \"b\" => \"x\"", render("X", map[string]interface{}{"b": "x", "a": int64(1)}))
+}
+
+func TestRenderScan(t *testing.T) {
+	require.Equal(t, "1) \"k1\"\n2) \"k2\"\n(cursor) 7", render("scankeys", []interface{}{"k1", "k2", "7"}))
+	require.Equal(t, "1) \"k1\" => \"v1\"\n(cursor) 0", render("SCANKVS", []interface{}{"k1", "v1", "0"}))
+	require.Equal(t, "1) \"a\" => \"1\"\n2) \"b\" => \"2\"\n(cursor) 0",
+		render("KVS", []interface{}{map[string]interface{}{"b": "2", "a": "1"}, "0"}))
+	require.Equal(t, "(empty page)\n(cursor) 0", render("KEYS", []interface{}{"0"}))
+}
+
+func TestRenderDocumentsAndMatches(t *testing.T) {
+	require.Equal(t, "1) {\n     \"age\": 30\n   }\n2) \"not json\"",
+		render("DQUERY", []interface{}{`{"age":30}`, "not json"}))
+	require.Equal(t, "(no documents)", render("DQUERY", []interface{}{}))
+	require.Equal(t, "1) id1 [1, 2]\n2) id2 [3, 4]",
+		render("VSEARCH", []interface{}{[]interface{}{"id1", "1", "2"}, []interface{}{"id2", "3", "4"}}))
+}
+
+func TestCompletionAndHints(t *testing.T) {
+	specs := map[string]commands.Spec{
+		"SET":      {Args: []commands.Arg{{Name: "key", Type: commands.ArgKey}, {Name: "value", Type: commands.ArgString}}},
+		"SCANKEYS": {Args: []commands.Arg{{Name: "cursor", Type: commands.ArgInteger}, {Name: "prefix", Type: commands.ArgString}, {Name: "count", Type: commands.ArgInteger, Optional: true}}},
+		"SCANKVS":  {},
+	}
+
+	completions, length := completer{specs: specs}.Do([]rune("sca"), 3)
+	require.Equal(t, 3, length)
+	require.Equal(t, [][]rune{[]rune("nkeys "), []rune("nkvs ")}, completions)
+	completions, _ = completer{specs: specs}.Do([]rune("SE"), 2)
+	require.Equal(t, [][]rune{[]rune("T ")}, completions)
+	completions, _ = completer{specs: specs}.Do([]rune("SET k"), 5)
+	require.Empty(t, completions)
+
+	h := hinter{specs: specs}
+	require.Equal(t, "key value", h.hint("set "))
+	require.Equal(t, "value", h.hint("SET k "))
+	require.Equal(t, "", h.hint("SET k"))
+	require.Equal(t, "[count]", h.hint("SCANKEYS 0 user: "))
+	require.Equal(t, "", h.hint("UNKNOWN "))
+}
+
+func TestDocsArgs(t *testing.T) {
+	args := docsArgs([]interface{}{
+		map[string]interface{}{"name": "key", "type": "key"},
+		map[string]interface{}{"name": "member", "type": "block", "flags": []interface{}{"multiple"}, "arguments": []interface{}{
+			map[string]interface{}{"name": "score", "type": "double"},
+		}},
+	})
+	require.Equal(t, []commands.Arg{
+		{Name: "key", Type: commands.ArgKey, Args: []commands.Arg{}},
+		{Name: "member", Type: commands.ArgBlock, Multiple: true, Args: []commands.Arg{{Name: "score", Type: commands.ArgDouble, Args: []commands.Arg{}}}},
+	}, args)
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/chzyer/readline"
+	"treds/client"
+	"treds/commands"
+	"treds/resp"
+)
+
+// streamingCommands keep replying until they are interrupted with Ctrl-C
+var streamingCommands = map[string]bool{"SUBSCRIBE": true, "PSUBSCRIBE": true, "MONITOR": true}
+
+func runREPL(c *client.Client, addr string) error {
+	ctx := context.Background()
+	specs := loadSpecs(ctx, c)
+	history := ""
+	if home, err := os.UserHomeDir(); err == nil {
+		history = filepath.Join(home, ".treds_cli_history")
+	}
+	rl, err := readline.NewEx(&readline.Config{
+		Prompt:            addr + "> ",
+		HistoryFile:       history,
+		HistorySearchFold: true,
+		AutoComplete:      completer{specs: specs},
+		Painter:           hinter{specs: specs},
+		InterruptPrompt:   "^C",
+		EOFPrompt:         "exit",
+	})
+	if err != nil {
+		return err
+	}
+	defer rl.Close()
+
+	for {
+		line, err := rl.Readline()
+		if errors.Is(err, readline.ErrInterrupt) {
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		args, err := resp.SplitArgs(line)
+		if err != nil {
+			fmt.Println("(error) Invalid argument(s)")
+			continue
+		}
+		if len(args) == 0 {
+			continue
+		}
+		name := strings.ToUpper(args[0])
+		switch {
+		case name == "EXIT" || name == "QUIT":
+			return nil
+		case name == "HELP":
+			fmt.Println(help(specs, args[1:]))
+		case streamingCommands[name]:
+			streamCtx, stop := signal.NotifyContext(ctx, os.Interrupt)
+			err = c.Stream(streamCtx, func(reply interface{}) error {
+				fmt.Println(formatMessage(reply))
+				return nil
+			}, args...)
+			stop()
+			if err != nil && !errors.Is(err, context.Canceled) {
+				fmt.Println("(error)", err)
+			}
+		default:
+			reply, err := c.Do(ctx, args...)
+			var replyErr resp.ReplyError
+			switch {
+			case errors.As(err, &replyErr):
+				reply = replyErr
+			case err != nil:
+				fmt.Printf("Could not connect to Treds at %s: %v\n", addr, err)
+				continue
+			}
+			fmt.Println(render(name, reply))
+		}
+	}
+}
+
+// loadSpecs returns the specs of the commands from COMMAND DOCS, falling back to the store commands
+// built in the cli when the server does not describe them
+func loadSpecs(ctx context.Context, c *client.Client) map[string]commands.Spec {
+	specs := make(map[string]commands.Spec)
+	if reply, err := c.Do(ctx, "COMMAND", "DOCS"); err == nil {
+		if docs, ok := reply.(map[string]interface{}); ok {
+			for name, doc := range docs {
+				fields, _ := doc.(map[string]interface{})
+				summary, _ := fields["summary"].(string)
+				group, _ := fields["group"].(string)
+				specs[strings.ToUpper(name)] = commands.Spec{Summary: summary, Group: group, Args: docsArgs(fields["arguments"])}
+			}
+		}
+	}
+	if len(specs) > 0 {
+		return specs
+	}
+	registry := commands.NewRegistry()
+	commands.RegisterCommands(registry)
+	for _, command := range registry.All() {
+		specs[strings.ToUpper(command.Name)] = command.Spec
+	}
+	return specs
+}
+
+// docsArgs converts the arguments of COMMAND DOCS back to the specs they were described from
+func docsArgs(v interface{}) []commands.Arg {
+	docs, _ := v.([]interface{})
+	args := make([]commands.Arg, 0, len(docs))
+	for _, doc := range docs {
+		fields, ok := doc.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := fields["name"].(string)
+		argType, _ := fields["type"].(string)
+		arg := commands.Arg{Name: name, Type: commands.ArgType(argType), Args: docsArgs(fields["arguments"])}
+		flags, _ := fields["flags"].([]interface{})
+		for _, flag := range flags {
+			switch flag {
+			case "optional":
+				arg.Optional = true
+			case "multiple":
+				arg.Multiple = true
+			}
+		}
+		args = append(args, arg)
+	}
+	return args
+}
+
+// help describes the named commands, or lists every command
+func help(specs map[string]commands.Spec, names []string) string {
+	if len(names) == 0 {
+		names = make([]string, 0, len(specs))
+		for name := range specs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.ToUpper(name)
+		spec, ok := specs[name]
+		if !ok {
+			lines = append(lines, fmt.Sprintf("%s: unknown command", name))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s %s\n  %s (%s)", name, spec.Syntax(), spec.Summary, spec.Group))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatMessage prints a published message, pushed as kind, pattern, channel and message, as channel: message.
+// Other replies like the subscription confirmations and the MONITOR lines are printed as they are.
+func formatMessage(reply interface{}) string {
+	fields, _ := reply.([]interface{})
+	if len(fields) == 4 {
+		switch fields[0] {
+		case "message":
+			return fmt.Sprintf("%v: %v", fields[2], fields[3])
+		case "pmessage":
+			return fmt.Sprintf("%v (%v): %v", fields[2], fields[1], fields[3])
+		}
+	}
+	if s, ok := reply.(string); ok {
+		return s
+	}
+	return renderValue(reply, "")
+}
+
+// completer completes the command names, in the case they are typed in
+type completer struct {
+	specs map[string]commands.Spec
+}
+
+func (c completer) Do(line []rune, pos int) ([][]rune, int) {
+	typed := string(line[:pos])
+	if strings.ContainsAny(typed, " \t") {
+		return nil, 0
+	}
+	upper := strings.ToUpper(typed)
+	lower := typed == strings.ToLower(typed) && typed != upper
+	var candidates []string
+	for name := range c.specs {
+		if strings.HasPrefix(name, upper) {
+			candidates = append(candidates, name)
+		}
+	}
+	sort.Strings(candidates)
+	completions := make([][]rune, 0, len(candidates))
+	for _, name := range candidates {
+		if lower {
+			name = strings.ToLower(name)
+		}
+		completions = append(completions, []rune(name[len(typed):]+" "))
+	}
+	return completions, utf8.RuneCountInString(typed)
+}
+
+// hinter paints the arguments left to type after the line, in grey, once a space follows the command name
+type hinter struct {
+	specs map[string]commands.Spec
+}
+
+func (h hinter) Paint(line []rune, pos int) []rune {
+	if pos != len(line) {
+		return line
+	}
+	hint := h.hint(string(line))
+	if hint == "" {
+		return line
+	}
+	painted := append([]rune{}, line...)
+	painted = append(painted, []rune("\033[90m"+hint+"\033[0m")...)
+	// Move the cursor back to the end of the line
+	return append(painted, []rune(strings.Repeat("\b", utf8.RuneCountInString(hint)))...)
+}
+
+// hint returns the syntax of the arguments left after the ones typed in line. Arguments are skipped while
+// they are required and single, past that the typed values can not be matched to an argument.
+func (h hinter) hint(line string) string {
+	if !strings.HasSuffix(line, " ") {
+		return ""
+	}
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return ""
+	}
+	spec, ok := h.specs[strings.ToUpper(fields[0])]
+	if !ok {
+		return ""
+	}
+	args := spec.Args
+	for typed := len(fields) - 1; typed > 0; typed-- {
+		if len(args) == 0 || args[0].Optional || args[0].Multiple || args[0].Type == commands.ArgBlock {
+			return ""
+		}
+		args = args[1:]
+	}
+	rest := commands.Spec{Args: args}
+	return rest.Syntax()
+}
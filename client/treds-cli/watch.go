@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"treds/client"
+)
+
+// runWatch prints the messages published to channels until ctx is done. Channels ending with * are
+// prefixes, subscribed to with PSUBSCRIBE.
+func runWatch(ctx context.Context, c *client.Client, channels []string, out io.Writer) error {
+	subscribe := []string{"SUBSCRIBE"}
+	psubscribe := []string{"PSUBSCRIBE"}
+	for _, channel := range channels {
+		channel = strings.TrimSpace(channel)
+		switch {
+		case channel == "":
+		case strings.HasSuffix(channel, "*"):
+			psubscribe = append(psubscribe, strings.TrimSuffix(channel, "*"))
+		default:
+			subscribe = append(subscribe, channel)
+		}
+	}
+
+	var mu sync.Mutex
+	handle := func(reply interface{}) error {
+		fields, _ := reply.([]interface{})
+		if len(fields) > 0 && (fields[0] == "subscribe" || fields[0] == "psubscribe") {
+			return nil
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		_, err := fmt.Fprintln(out, formatMessage(reply))
+		return err
+	}
+
+	errs := make(chan error, 2)
+	streams := 0
+	for _, args := range [][]string{subscribe, psubscribe} {
+		if len(args) == 1 {
+			continue
+		}
+		streams++
+		go func(args []string) {
+			errs <- c.Stream(ctx, handle, args...)
+		}(args)
+	}
+	if streams == 0 {
+		return fmt.Errorf("no channel to watch")
+	}
+	for ; streams > 0; streams-- {
+		if err := <-errs; err != nil && !errors.Is(err, context.Canceled) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// KV is a key and its value
+type KV struct {
+	Key   string
+	Value string
+}
+
+// ScoredKV is a member of a sorted map with its score and value
+type ScoredKV struct {
+	Score float64
+	Key   string
+	Value string
+}
+
+// VectorMatch is a vector returned by VSEARCH, nearest first
+type VectorMatch struct {
+	ID     string
+	Vector []float64
+}
+
+// VectorOptions configure the HNSW index created by VCREATE, zero values keep the server defaults
+type VectorOptions struct {
+	MaxNeighbors int
+	LevelFactor  float64
+	EfSearch     int
+}
+
+// Document is a JSON document of a collection
+type Document json.RawMessage
+
+// Decode unmarshals the document into v
+func (d Document) Decode(v interface{}) error {
+	return json.Unmarshal(d, v)
+}
+
+// MarshalJSON embeds the document as is
+func (d Document) MarshalJSON() ([]byte, error) {
+	return json.RawMessage(d).MarshalJSON()
+}
+
+// String returns the JSON of the document
+func (d Document) String() string {
+	return string(d)
+}
+
+func toString(v interface{}) (string, error) {
+	switch s := v.(type) {
+	case string:
+		return s, nil
+	case nil:
+		return "", ErrNil
+	case int64:
+		return strconv.FormatInt(s, 10), nil
+	case float64:
+		return strconv.FormatFloat(s, 'f', -1, 64), nil
+	}
+	return "", fmt.Errorf("treds: unexpected reply %v", v)
+}
+
+func toInt(v interface{}) (int64, error) {
+	switch n := v.(type) {
+	case int64:
+		return n, nil
+	case string:
+		return strconv.ParseInt(n, 10, 64)
+	case nil:
+		return 0, ErrNil
+	}
+	return 0, fmt.Errorf("treds: unexpected integer reply %v", v)
+}
+
+func toFloat(v interface{}) (float64, error) {
+	switch f := v.(type) {
+	case float64:
+		return f, nil
+	case int64:
+		return float64(f), nil
+	case string:
+		return strconv.ParseFloat(f, 64)
+	case nil:
+		return 0, ErrNil
+	}
+	return 0, fmt.Errorf("treds: unexpected double reply %v", v)
+}
+
+// toStrings converts an array reply, a nil reply is an empty array
+func toStrings(v interface{}) ([]string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	elements, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("treds: unexpected array reply %v", v)
+	}
+	strs := make([]string, 0, len(elements))
+	for _, element := range elements {
+		s, err := toString(element)
+		if err != nil {
+			return nil, err
+		}
+		strs = append(strs, s)
+	}
+	return strs, nil
+}
+
+// toKVs pairs alternating keys and values
+func toKVs(strs []string) ([]KV, error) {
+	if len(strs)%2 != 0 {
+		return nil, fmt.Errorf("treds: expected key value pairs, got %d elements", len(strs))
+	}
+	kvs := make([]KV, 0, len(strs)/2)
+	for i := 0; i < len(strs); i += 2 {
+		kvs = append(kvs, KV{Key: strs[i], Value: strs[i+1]})
+	}
+	return kvs, nil
+}
+
+// toScoredKVs converts a sorted map range of score, key and value triples
+func toScoredKVs(v interface{}) ([]ScoredKV, error) {
+	if v == nil {
+		return nil, nil
+	}
+	elements, ok := v.([]interface{})
+	if !ok || len(elements)%3 != 0 {
+		return nil, fmt.Errorf("treds: expected score, key and value triples, got %v", v)
+	}
+	members := make([]ScoredKV, 0, len(elements)/3)
+	for i := 0; i < len(elements); i += 3 {
+		score, err := toFloat(elements[i])
+		if err != nil {
+			return nil, err
+		}
+		key, err := toString(elements[i+1])
+		if err != nil {
+			return nil, err
+		}
+		value, err := toString(elements[i+2])
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, ScoredKV{Score: score, Key: key, Value: value})
+	}
+	return members, nil
+}
+
+// toVectorMatches converts the rows of VSEARCH, each an id followed by the components of the vector
+func toVectorMatches(v interface{}) ([]VectorMatch, error) {
+	if v == nil {
+		return nil, nil
+	}
+	rows, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("treds: unexpected VSEARCH reply %v", v)
+	}
+	matches := make([]VectorMatch, 0, len(rows))
+	for _, row := range rows {
+		strs, err := toStrings(row)
+		if err != nil {
+			return nil, err
+		}
+		if len(strs) == 0 {
+			return nil, fmt.Errorf("treds: empty VSEARCH row")
+		}
+		match := VectorMatch{ID: strs[0], Vector: make([]float64, 0, len(strs)-1)}
+		for _, component := range strs[1:] {
+			f, err := strconv.ParseFloat(component, 64)
+			if err != nil {
+				return nil, err
+			}
+			match.Vector = append(match.Vector, f)
+		}
+		matches = append(matches, match)
+	}
+	return matches, nil
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
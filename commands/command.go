@@ -12,6 +12,11 @@ func RegisterCommands(r CommandRegistry) {
 	RegisterKeysCommand(r)
 	RegisterKVSCommand(r)
 	RegisterMGetCommand(r)
+	RegisterIncrCommand(r)
+	RegisterDecrCommand(r)
+	RegisterIncrByCommand(r)
+	RegisterDecrByCommand(r)
+	RegisterIncrByFloatCommand(r)
 	RegisterDBSizeCommand(r)
 	RegisterZAddCommand(r)
 	RegisterZRangeCommand(r)
@@ -22,6 +27,7 @@ func RegisterCommands(r CommandRegistry) {
 	RegisterZRemCommand(r)
 	RegisterZScoreCommand(r)
 	RegisterZCardCommand(r)
+	RegisterZIncrByCommand(r)
 	RegisterZRevRangeScoreCommand(r)
 	RegisterZRevRangeScoreKVSCommand(r)
 	RegisterZRevRangeLexKeysCommand(r)
@@ -52,6 +58,8 @@ func RegisterCommands(r CommandRegistry) {
 	RegisterHExistsCommand(r)
 	RegisterHKeysCommand(r)
 	RegisterHValsCommand(r)
+	RegisterHIncrByCommand(r)
+	RegisterHIncrByFloatCommand(r)
 	RegisterExpireCommand(r)
 	RegisterTtlCommand(r)
 	RegisterLongestPrefixCommand(r)
@@ -9,18 +9,16 @@ const DBSize = "DBSIZE"
 
 func RegisterDBSizeCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     DBSize,
-		Validate: validateDBSize(),
-		Execute:  executeDBSize(),
+		Name: DBSize,
+		Spec: Spec{
+			Summary: "Returns the number of keys",
+			Group:   GroupServer,
+			Flags:   []string{FlagReadonly},
+		},
+		Execute: executeDBSize(),
 	})
 }
 
-func validateDBSize() ValidationHook {
-	return func(args []string) error {
-		return nil
-	}
-}
-
 func executeDBSize() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		res, err := store.Size()
@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,23 +9,21 @@ const DCreateCollection = "DCREATE"
 
 func RegisterDCreateCollection(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     DCreateCollection,
-		Validate: validateDCreateCollection(),
-		Execute:  executeDCreateCollection(),
-		IsWrite:  true,
+		Name: DCreateCollection,
+		Spec: Spec{
+			Summary: "Creates a collection with a JSON schema and indexes",
+			Group:   GroupCollection,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "collection", Type: ArgKey},
+				{Name: "schema", Type: ArgString, Optional: true},
+				{Name: "indexes", Type: ArgString, Optional: true},
+			},
+		},
+		Execute: executeDCreateCollection(),
 	})
 }
 
-func validateDCreateCollection() ValidationHook {
-	return func(args []string) error {
-		if len(args) < 1 {
-			return fmt.Errorf("expected minimum 1 argument, got %d", len(args))
-		}
-
-		return nil
-	}
-}
-
 func executeDCreateCollection() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		err := store.DCreateCollection(args)
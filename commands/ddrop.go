@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,23 +9,19 @@ const DDropCollection = "DDROP"
 
 func RegisterDDropCollection(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     DDropCollection,
-		Validate: validateDDropCollection(),
-		Execute:  executeDDropCollection(),
-		IsWrite:  true,
+		Name: DDropCollection,
+		Spec: Spec{
+			Summary: "Drops a collection",
+			Group:   GroupCollection,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "collection", Type: ArgKey},
+			},
+		},
+		Execute: executeDDropCollection(),
 	})
 }
 
-func validateDDropCollection() ValidationHook {
-	return func(args []string) error {
-		if len(args) < 1 {
-			return fmt.Errorf("expected minimum 1 argument, got %d", len(args))
-		}
-
-		return nil
-	}
-}
-
 func executeDDropCollection() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		err := store.DDropCollection(args)
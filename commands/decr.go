@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"treds/resp"
+	"treds/store"
+)
+
+const DecrCommand = "DECR"
+
+func RegisterDecrCommand(r CommandRegistry) {
+	r.Add(&CommandRegistration{
+		Name: DecrCommand,
+		Spec: Spec{
+			Summary: "Decrements the integer value of a key by one",
+			Group:   GroupString,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+			},
+		},
+		Execute: executeDecr(),
+	})
+}
+
+func executeDecr() ExecutionHook {
+	return func(args []string, store store.Store) string {
+		res, err := store.IncrBy(args[0], -1)
+		if err != nil {
+			return resp.EncodeError(err.Error())
+		}
+		return resp.EncodeInteger(int(res))
+	}
+}
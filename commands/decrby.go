@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"math"
+	"strconv"
+
+	"treds/resp"
+	"treds/store"
+)
+
+const DecrByCommand = "DECRBY"
+
+func RegisterDecrByCommand(r CommandRegistry) {
+	r.Add(&CommandRegistration{
+		Name: DecrByCommand,
+		Spec: Spec{
+			Summary: "Decrements the integer value of a key by a number",
+			Group:   GroupString,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "decrement", Type: ArgInteger},
+			},
+		},
+		Execute: executeDecrBy(),
+	})
+}
+
+func executeDecrBy() ExecutionHook {
+	return func(args []string, s store.Store) string {
+		decrement, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return resp.EncodeError(err.Error())
+		}
+		// The decrement can not be negated
+		if decrement == math.MinInt64 {
+			return resp.EncodeError(store.ErrOverflow.Error())
+		}
+		res, err := s.IncrBy(args[0], -decrement)
+		if err != nil {
+			return resp.EncodeError(err.Error())
+		}
+		return resp.EncodeInteger(int(res))
+	}
+}
@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,23 +9,19 @@ const DeleteCommand = "DEL"
 
 func RegisterDeleteCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     DeleteCommand,
-		Validate: validateDel(),
-		Execute:  executeDel(),
-		IsWrite:  true,
+		Name: DeleteCommand,
+		Spec: Spec{
+			Summary: "Deletes a key",
+			Group:   GroupGeneric,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+			},
+		},
+		Execute: executeDel(),
 	})
 }
 
-func validateDel() ValidationHook {
-	return func(args []string) error {
-		if len(args) != 1 {
-			return fmt.Errorf("expected 1 argument, got %d", len(args))
-		}
-
-		return nil
-	}
-}
-
 func executeDel() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		err := store.Delete(args[0])
@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,22 +9,19 @@ const DeletePrefixCommand = "DELPREFIX"
 
 func RegisterDeletePrefixCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     DeletePrefixCommand,
-		Validate: validateDeletePrefix(),
-		Execute:  executeDeletePrefix(),
-		IsWrite:  true,
+		Name: DeletePrefixCommand,
+		Spec: Spec{
+			Summary: "Deletes every key having a prefix and returns the number of keys deleted",
+			Group:   GroupGeneric,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "prefix", Type: ArgPrefix},
+			},
+		},
+		Execute: executeDeletePrefix(),
 	})
 }
 
-func validateDeletePrefix() ValidationHook {
-	return func(args []string) error {
-		if len(args) != 1 {
-			return fmt.Errorf("expected 1 argument, got %d", len(args))
-		}
-		return nil
-	}
-}
-
 func executeDeletePrefix() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		numDel, err := store.DeletePrefix(args[0])
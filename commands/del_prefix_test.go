@@ -14,7 +14,7 @@ func TestRegisterDeletePrefixCommand(t *testing.T) {
 	}
 }
 
-// TestValidateDeletePrefix tests the validateDeletePrefix function.
+// TestValidateDeletePrefix tests the validation generated from the spec.
 func TestValidateDeletePrefix(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -29,7 +29,7 @@ func TestValidateDeletePrefix(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			validationHook := validateDeletePrefix()
+			validationHook := registeredCommand(t, RegisterDeletePrefixCommand, DeletePrefixCommand).Validate
 			err := validationHook(tt.args)
 			if (err != nil) != tt.expectErr {
 				t.Errorf("expected error: %v, got: %v", tt.expectErr, err)
@@ -14,7 +14,7 @@ func TestRegisterDeleteCommand(t *testing.T) {
 	}
 }
 
-// TestValidateDel tests the validateDel function.
+// TestValidateDel tests the validation generated from the spec.
 func TestValidateDel(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -29,7 +29,7 @@ func TestValidateDel(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			validationHook := validateDel()
+			validationHook := registeredCommand(t, RegisterDeleteCommand, DeleteCommand).Validate
 			err := validationHook(tt.args)
 			if (err != nil) != tt.expectErr {
 				t.Errorf("expected error: %v, got: %v", tt.expectErr, err)
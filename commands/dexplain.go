@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,22 +9,20 @@ const DExplain = "DEXPLAIN"
 
 func RegisterDExplainCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     DExplain,
-		Validate: validateDExplainCommand(),
-		Execute:  executeDExplainCommand(),
+		Name: DExplain,
+		Spec: Spec{
+			Summary: "Returns the plan of a JSON query, the index it uses",
+			Group:   GroupCollection,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "collection", Type: ArgKey},
+				{Name: "query", Type: ArgString},
+			},
+		},
+		Execute: executeDExplainCommand(),
 	})
 }
 
-func validateDExplainCommand() ValidationHook {
-	return func(args []string) error {
-		if len(args) < 2 {
-			return fmt.Errorf("expected 2 argument, got %d", len(args))
-		}
-
-		return nil
-	}
-}
-
 func executeDExplainCommand() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		res, err := store.DExplain(args)
@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,23 +9,20 @@ const DInsert = "DINSERT"
 
 func RegisterDInsertCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     DInsert,
-		Validate: validateDInsertCommand(),
-		Execute:  executeDInsertCommand(),
-		IsWrite:  true,
+		Name: DInsert,
+		Spec: Spec{
+			Summary: "Inserts a JSON document in a collection",
+			Group:   GroupCollection,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "collection", Type: ArgKey},
+				{Name: "document", Type: ArgString},
+			},
+		},
+		Execute: executeDInsertCommand(),
 	})
 }
 
-func validateDInsertCommand() ValidationHook {
-	return func(args []string) error {
-		if len(args) < 2 {
-			return fmt.Errorf("expected 2 argument, got %d", len(args))
-		}
-
-		return nil
-	}
-}
-
 func executeDInsertCommand() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		res, err := store.DInsert(args)
@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,22 +9,20 @@ const DQuery = "DQUERY"
 
 func RegisterDQueryCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     DQuery,
-		Validate: validateDQueryCommand(),
-		Execute:  executeDQueryCommand(),
+		Name: DQuery,
+		Spec: Spec{
+			Summary: "Returns the documents of a collection matching a JSON query",
+			Group:   GroupCollection,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "collection", Type: ArgKey},
+				{Name: "query", Type: ArgString},
+			},
+		},
+		Execute: executeDQueryCommand(),
 	})
 }
 
-func validateDQueryCommand() ValidationHook {
-	return func(args []string) error {
-		if len(args) < 2 {
-			return fmt.Errorf("expected minimum 2 argument, got %d", len(args))
-		}
-
-		return nil
-	}
-}
-
 func executeDQueryCommand() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		res, err := store.DQuery(args)
@@ -1,7 +1,6 @@
 package commands
 
 import (
-	"fmt"
 	"strconv"
 	"time"
 
@@ -13,26 +12,20 @@ const ExpireCommand = "EXPIRE"
 
 func RegisterExpireCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     ExpireCommand,
-		Validate: validateExpireCommand(),
-		Execute:  executeExpireCommand(),
-		IsWrite:  true,
+		Name: ExpireCommand,
+		Spec: Spec{
+			Summary: "Expires a key after a number of seconds",
+			Group:   GroupGeneric,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "seconds", Type: ArgInteger},
+			},
+		},
+		Execute: executeExpireCommand(),
 	})
 }
 
-func validateExpireCommand() ValidationHook {
-	return func(args []string) error {
-		if len(args) != 2 {
-			_, err := strconv.Atoi(args[1])
-			if err != nil {
-				return err
-			}
-			return fmt.Errorf("expected 1 argument, got %d", len(args))
-		}
-		return nil
-	}
-}
-
 func executeExpireCommand() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		key := args[0]
@@ -9,10 +9,14 @@ const FlushAll = "FLUSHALL"
 
 func RegisterFlushAllCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     FlushAll,
-		Validate: validateDBSize(),
-		Execute:  executeFlushAll(),
-		IsWrite:  true,
+		Name: FlushAll,
+		Spec: Spec{
+			Summary: "Deletes every key",
+			Group:   GroupServer,
+			Flags:   []string{FlagWrite},
+			AllKeys: true,
+		},
+		Execute: executeFlushAll(),
 	})
 }
 
@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,28 +9,42 @@ const GetCommand = "GET"
 
 func RegisterGetCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     GetCommand,
-		Validate: validateGet(),
-		Execute:  executeGet(),
+		Name: GetCommand,
+		Spec: Spec{
+			Summary: "Returns the value of a key",
+			Group:   GroupString,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+			},
+		},
+		Execute:      executeGet(),
+		ExecuteRESP3: executeGetRESP3(),
 	})
 }
 
-func validateGet() ValidationHook {
-	return func(args []string) error {
-		if len(args) != 1 {
-			return fmt.Errorf("expected 1 argument, got %d", len(args))
+func executeGet() ExecutionHook {
+	return func(args []string, s store.Store) string {
+		res, found, err := s.Get(args[0])
+		if err != nil {
+			return resp.EncodeError(err.Error())
 		}
-
-		return nil
+		if !found {
+			return resp.EncodeBulkString(store.NilResp)
+		}
+		return resp.EncodeBulkString(res)
 	}
 }
 
-func executeGet() ExecutionHook {
-	return func(args []string, store store.Store) string {
-		res, err := store.Get(args[0])
+func executeGetRESP3() ExecutionHook {
+	return func(args []string, s store.Store) string {
+		res, found, err := s.Get(args[0])
 		if err != nil {
 			return resp.EncodeError(err.Error())
 		}
+		if !found {
+			return resp.EncodeNull()
+		}
 		return resp.EncodeBulkString(res)
 	}
 }
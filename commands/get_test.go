@@ -14,7 +14,7 @@ func TestRegisterGetCommand(t *testing.T) {
 	}
 }
 
-// TestValidateGet tests the validateGet function.
+// TestValidateGet tests the validation generated from the spec.
 func TestValidateGet(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -29,7 +29,7 @@ func TestValidateGet(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			validationHook := validateGet()
+			validationHook := registeredCommand(t, RegisterGetCommand, GetCommand).Validate
 			err := validationHook(tt.args)
 			if (err != nil) != tt.expectErr {
 				t.Errorf("expected error: %v, got: %v", tt.expectErr, err)
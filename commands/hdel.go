@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,23 +9,20 @@ const HDelCommand = "HDEL"
 
 func RegisterHDelCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     HDelCommand,
-		Validate: validateHDelCommand(),
-		Execute:  executeHDelCommand(),
-		IsWrite:  true,
+		Name: HDelCommand,
+		Spec: Spec{
+			Summary: "Deletes fields of a hash",
+			Group:   GroupHash,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "field", Type: ArgString, Multiple: true},
+			},
+		},
+		Execute: executeHDelCommand(),
 	})
 }
 
-func validateHDelCommand() ValidationHook {
-	return func(args []string) error {
-		if len(args) < 2 {
-			return fmt.Errorf("expected minimum 2 argument, got %d", len(args))
-		}
-
-		return nil
-	}
-}
-
 func executeHDelCommand() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		key := args[0]
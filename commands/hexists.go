@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,22 +9,21 @@ const HExistsCommand = "HEXISTS"
 
 func RegisterHExistsCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     HExistsCommand,
-		Validate: validateHExistsCommand(),
-		Execute:  executeHExistsCommand(),
+		Name: HExistsCommand,
+		Spec: Spec{
+			Summary: "Tells if a field is in a hash",
+			Group:   GroupHash,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "field", Type: ArgString},
+			},
+		},
+		Execute:      executeHExistsCommand(),
+		ExecuteRESP3: executeHExistsCommandRESP3(),
 	})
 }
 
-func validateHExistsCommand() ValidationHook {
-	return func(args []string) error {
-		if len(args) != 2 {
-			return fmt.Errorf("expected 2 argument, got %d", len(args))
-		}
-
-		return nil
-	}
-}
-
 func executeHExistsCommand() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		key := args[0]
@@ -41,3 +38,13 @@ func executeHExistsCommand() ExecutionHook {
 		}
 	}
 }
+
+func executeHExistsCommandRESP3() ExecutionHook {
+	return func(args []string, store store.Store) string {
+		found, err := store.HExists(args[0], args[1])
+		if err != nil {
+			return resp.EncodeError(err.Error())
+		}
+		return resp.EncodeBoolean(found)
+	}
+}
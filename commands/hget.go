@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,29 +9,44 @@ const HGetCommand = "HGET"
 
 func RegisterHGetCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     HGetCommand,
-		Validate: validateHGetCommand(),
-		Execute:  executeHGetCommand(),
+		Name: HGetCommand,
+		Spec: Spec{
+			Summary: "Returns the value of a field of a hash",
+			Group:   GroupHash,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "field", Type: ArgString},
+			},
+		},
+		Execute:      executeHGetCommand(),
+		ExecuteRESP3: executeHGetCommandRESP3(),
 	})
 }
 
-func validateHGetCommand() ValidationHook {
-	return func(args []string) error {
-		if len(args) != 2 {
-			return fmt.Errorf("expected 2 argument, got %d", len(args))
+func executeHGetCommand() ExecutionHook {
+	return func(args []string, s store.Store) string {
+		key := args[0]
+		res, found, err := s.HGet(key, args[1])
+		if err != nil {
+			return resp.EncodeError(err.Error())
 		}
-
-		return nil
+		if !found {
+			return resp.EncodeBulkString(store.NilResp)
+		}
+		return resp.EncodeBulkString(res)
 	}
 }
 
-func executeHGetCommand() ExecutionHook {
-	return func(args []string, store store.Store) string {
-		key := args[0]
-		res, err := store.HGet(key, args[1])
+func executeHGetCommandRESP3() ExecutionHook {
+	return func(args []string, s store.Store) string {
+		res, found, err := s.HGet(args[0], args[1])
 		if err != nil {
 			return resp.EncodeError(err.Error())
 		}
+		if !found {
+			return resp.EncodeNull()
+		}
 		return resp.EncodeBulkString(res)
 	}
 }
@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,22 +9,20 @@ const HGetAllCommand = "HGETALL"
 
 func RegisterHGetAllCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     HGetAllCommand,
-		Validate: validateHGetAllCommand(),
-		Execute:  executeHGetAllCommand(),
+		Name: HGetAllCommand,
+		Spec: Spec{
+			Summary: "Returns every field and value of a hash",
+			Group:   GroupHash,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+			},
+		},
+		Execute:      executeHGetAllCommand(),
+		ExecuteRESP3: executeHGetAllCommandRESP3(),
 	})
 }
 
-func validateHGetAllCommand() ValidationHook {
-	return func(args []string) error {
-		if len(args) != 1 {
-			return fmt.Errorf("expected 1 argument, got %d", len(args))
-		}
-
-		return nil
-	}
-}
-
 func executeHGetAllCommand() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		key := args[0]
@@ -37,3 +33,13 @@ func executeHGetAllCommand() ExecutionHook {
 		return resp.EncodeStringArray(res)
 	}
 }
+
+func executeHGetAllCommandRESP3() ExecutionHook {
+	return func(args []string, store store.Store) string {
+		res, err := store.HGetAll(args[0])
+		if err != nil {
+			return resp.EncodeError(err.Error())
+		}
+		return resp.EncodeStringMap(res)
+	}
+}
@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"strconv"
+
+	"treds/resp"
+	"treds/store"
+)
+
+const HIncrByCommand = "HINCRBY"
+
+func RegisterHIncrByCommand(r CommandRegistry) {
+	r.Add(&CommandRegistration{
+		Name: HIncrByCommand,
+		Spec: Spec{
+			Summary: "Increments the integer value of a field of a hash by a number",
+			Group:   GroupHash,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "field", Type: ArgString},
+				{Name: "increment", Type: ArgInteger},
+			},
+		},
+		Execute: executeHIncrByCommand(),
+	})
+}
+
+func executeHIncrByCommand() ExecutionHook {
+	return func(args []string, store store.Store) string {
+		increment, err := strconv.ParseInt(args[2], 10, 64)
+		if err != nil {
+			return resp.EncodeError(err.Error())
+		}
+		res, err := store.HIncrBy(args[0], args[1], increment)
+		if err != nil {
+			return resp.EncodeError(err.Error())
+		}
+		return resp.EncodeInteger(int(res))
+	}
+}
@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"testing"
+)
+
+// TestExecuteHIncrBy tests the execution hooks of HINCRBY and HINCRBYFLOAT.
+func TestExecuteHIncrBy(t *testing.T) {
+	tests := []struct {
+		name          string
+		hook          ExecutionHook
+		args          []string
+		hash          map[string]string
+		expectedMsg   string
+		expectedValue string
+	}{
+		{"hincrby missing field", executeHIncrByCommand(), []string{"h", "f", "5"}, nil, ":5\r\n", "5"},
+		{"hincrby", executeHIncrByCommand(), []string{"h", "f", "-15"}, map[string]string{"f": "10"}, ":-5\r\n", "-5"},
+		{"hincrby overflow", executeHIncrByCommand(), []string{"h", "f", "1"}, map[string]string{"f": "9223372036854775807"}, "-increment or decrement would overflow\r\n", "9223372036854775807"},
+		{"hincrby not an integer", executeHIncrByCommand(), []string{"h", "f", "1"}, map[string]string{"f": "abc"}, "-value is not an integer or out of range\r\n", "abc"},
+		{"hincrby invalid increment", executeHIncrByCommand(), []string{"h", "f", "1.5"}, map[string]string{"f": "1"}, "-strconv.ParseInt: parsing \"1.5\": invalid syntax\r\n", "1"},
+		{"hincrbyfloat missing field", executeHIncrByFloatCommand(), []string{"h", "f", "0.5"}, nil, "$3\r\n0.5\r\n", "0.5"},
+		{"hincrbyfloat", executeHIncrByFloatCommand(), []string{"h", "f", "0.25"}, map[string]string{"f": "1"}, "$4\r\n1.25\r\n", "1.25"},
+		{"hincrbyfloat infinite", executeHIncrByFloatCommand(), []string{"h", "f", "1e308"}, map[string]string{"f": "1e308"}, "-increment would produce NaN or Infinity\r\n", "1e308"},
+		{"hincrbyfloat not a float", executeHIncrByFloatCommand(), []string{"h", "f", "1"}, map[string]string{"f": "abc"}, "-value is not a valid float\r\n", "abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := &MockStore{data: map[string]string{}}
+			for field, value := range tt.hash {
+				if err := mockStore.HSet("h", []string{field, value}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			result := tt.hook(tt.args, mockStore)
+			if result != tt.expectedMsg {
+				t.Errorf("expected result: %q, got: %q", tt.expectedMsg, result)
+			}
+			if value, _, _ := mockStore.HGet("h", "f"); value != tt.expectedValue {
+				t.Errorf("expected value: %s, got: %s", tt.expectedValue, value)
+			}
+		})
+	}
+}
+
+// TestExecuteHIncrByWrongType tests HINCRBY refuses a key holding another type.
+func TestExecuteHIncrByWrongType(t *testing.T) {
+	mockStore := &MockStore{data: map[string]string{}}
+	if err := mockStore.ZAdd([]string{"z", "1", "m", "v"}); err != nil {
+		t.Fatal(err)
+	}
+	if result := executeHIncrByCommand()([]string{"z", "f", "1"}, mockStore); result != "-not hash store\r\n" {
+		t.Errorf("expected result: %q, got: %q", "-not hash store\r\n", result)
+	}
+}
@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"strconv"
+
+	"treds/resp"
+	"treds/store"
+)
+
+const HIncrByFloatCommand = "HINCRBYFLOAT"
+
+func RegisterHIncrByFloatCommand(r CommandRegistry) {
+	r.Add(&CommandRegistration{
+		Name: HIncrByFloatCommand,
+		Spec: Spec{
+			Summary: "Increments the float value of a field of a hash by a number",
+			Group:   GroupHash,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "field", Type: ArgString},
+				{Name: "increment", Type: ArgDouble},
+			},
+		},
+		Execute: executeHIncrByFloatCommand(),
+	})
+}
+
+func executeHIncrByFloatCommand() ExecutionHook {
+	return func(args []string, store store.Store) string {
+		increment, err := strconv.ParseFloat(args[2], 64)
+		if err != nil {
+			return resp.EncodeError(err.Error())
+		}
+		res, err := store.HIncrByFloat(args[0], args[1], increment)
+		if err != nil {
+			return resp.EncodeError(err.Error())
+		}
+		return resp.EncodeBulkString(strconv.FormatFloat(res, 'f', -1, 64))
+	}
+}
@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,22 +9,19 @@ const HKeysCommand = "HKEYS"
 
 func RegisterHKeysCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     HKeysCommand,
-		Validate: validateHKeysCommand(),
-		Execute:  executeHKeysCommand(),
+		Name: HKeysCommand,
+		Spec: Spec{
+			Summary: "Returns the fields of a hash",
+			Group:   GroupHash,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+			},
+		},
+		Execute: executeHKeysCommand(),
 	})
 }
 
-func validateHKeysCommand() ValidationHook {
-	return func(args []string) error {
-		if len(args) != 1 {
-			return fmt.Errorf("expected 1 argument, got %d", len(args))
-		}
-
-		return nil
-	}
-}
-
 func executeHKeysCommand() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		key := args[0]
@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,22 +9,19 @@ const HLenCommand = "HLEN"
 
 func RegisterHLenCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     HLenCommand,
-		Validate: validateHLenCommand(),
-		Execute:  executeHLenCommand(),
+		Name: HLenCommand,
+		Spec: Spec{
+			Summary: "Returns the number of fields of a hash",
+			Group:   GroupHash,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+			},
+		},
+		Execute: executeHLenCommand(),
 	})
 }
 
-func validateHLenCommand() ValidationHook {
-	return func(args []string) error {
-		if len(args) != 1 {
-			return fmt.Errorf("expected 1 argument, got %d", len(args))
-		}
-
-		return nil
-	}
-}
-
 func executeHLenCommand() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		key := args[0]
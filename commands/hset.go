@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,23 +9,23 @@ const HSetCommand = "HSET"
 
 func RegisterHSetCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     HSetCommand,
-		Validate: validateHSetCommand(),
-		Execute:  executeHSetCommand(),
-		IsWrite:  true,
+		Name: HSetCommand,
+		Spec: Spec{
+			Summary: "Sets fields of a hash",
+			Group:   GroupHash,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "data", Type: ArgBlock, Multiple: true, Args: []Arg{
+					{Name: "field", Type: ArgString},
+					{Name: "value", Type: ArgString},
+				}},
+			},
+		},
+		Execute: executeHSetCommand(),
 	})
 }
 
-func validateHSetCommand() ValidationHook {
-	return func(args []string) error {
-		if len(args) < 3 {
-			return fmt.Errorf("expected minimum 3 argument, got %d", len(args))
-		}
-
-		return nil
-	}
-}
-
 func executeHSetCommand() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		key := args[0]
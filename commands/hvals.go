@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,22 +9,19 @@ const HValsCommand = "HVALS"
 
 func RegisterHValsCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     HValsCommand,
-		Validate: validateHValsCommand(),
-		Execute:  executeHValsCommand(),
+		Name: HValsCommand,
+		Spec: Spec{
+			Summary: "Returns the values of a hash",
+			Group:   GroupHash,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+			},
+		},
+		Execute: executeHValsCommand(),
 	})
 }
 
-func validateHValsCommand() ValidationHook {
-	return func(args []string) error {
-		if len(args) != 1 {
-			return fmt.Errorf("expected 1 argument, got %d", len(args))
-		}
-
-		return nil
-	}
-}
-
 func executeHValsCommand() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		key := args[0]
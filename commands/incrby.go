@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"strconv"
+
+	"treds/resp"
+	"treds/store"
+)
+
+const IncrByCommand = "INCRBY"
+
+func RegisterIncrByCommand(r CommandRegistry) {
+	r.Add(&CommandRegistration{
+		Name: IncrByCommand,
+		Spec: Spec{
+			Summary: "Increments the integer value of a key by a number",
+			Group:   GroupString,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "increment", Type: ArgInteger},
+			},
+		},
+		Execute: executeIncrBy(),
+	})
+}
+
+func executeIncrBy() ExecutionHook {
+	return func(args []string, store store.Store) string {
+		increment, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return resp.EncodeError(err.Error())
+		}
+		res, err := store.IncrBy(args[0], increment)
+		if err != nil {
+			return resp.EncodeError(err.Error())
+		}
+		return resp.EncodeInteger(int(res))
+	}
+}
@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"testing"
+)
+
+// TestValidateIncrBy tests the validation generated from the spec.
+func TestValidateIncrBy(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		expectErr   bool
+		expectedMsg string
+	}{
+		{"valid args", []string{"counter", "5"}, false, ""},
+		{"negative increment", []string{"counter", "-5"}, false, ""},
+		{"float increment", []string{"counter", "1.5"}, true, "increment should be an integer, got '1.5'"},
+		{"too few args", []string{"counter"}, true, "expected 2 argument, got 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validationHook := registeredCommand(t, RegisterIncrByCommand, IncrByCommand).Validate
+			err := validationHook(tt.args)
+			if (err != nil) != tt.expectErr {
+				t.Errorf("expected error: %v, got: %v", tt.expectErr, err)
+			}
+			if err != nil && err.Error() != tt.expectedMsg {
+				t.Errorf("expected error message: %s, got: %s", tt.expectedMsg, err.Error())
+			}
+		})
+	}
+}
+
+// TestExecuteIncrements tests the execution hooks of INCR, DECR, INCRBY, DECRBY and INCRBYFLOAT.
+func TestExecuteIncrements(t *testing.T) {
+	tests := []struct {
+		name          string
+		hook          ExecutionHook
+		args          []string
+		data          map[string]string
+		expectedMsg   string
+		expectedValue string
+	}{
+		{"incr missing key", executeIncr(), []string{"counter"}, map[string]string{}, ":1\r\n", "1"},
+		{"decr", executeDecr(), []string{"counter"}, map[string]string{"counter": "10"}, ":9\r\n", "9"},
+		{"incrby", executeIncrBy(), []string{"counter", "5"}, map[string]string{"counter": "10"}, ":15\r\n", "15"},
+		{"decrby", executeDecrBy(), []string{"counter", "15"}, map[string]string{"counter": "10"}, ":-5\r\n", "-5"},
+		{"decrby min int64", executeDecrBy(), []string{"counter", "-9223372036854775808"}, map[string]string{"counter": "0"}, "-increment or decrement would overflow\r\n", "0"},
+		{"incrby overflow", executeIncrBy(), []string{"counter", "1"}, map[string]string{"counter": "9223372036854775807"}, "-increment or decrement would overflow\r\n", "9223372036854775807"},
+		{"incr not an integer", executeIncr(), []string{"counter"}, map[string]string{"counter": "abc"}, "-value is not an integer or out of range\r\n", "abc"},
+		{"incrbyfloat", executeIncrByFloat(), []string{"price", "0.25"}, map[string]string{"price": "1"}, "$4\r\n1.25\r\n", "1.25"},
+		{"incrbyfloat infinite", executeIncrByFloat(), []string{"price", "1e308"}, map[string]string{"price": "1e308"}, "-increment would produce NaN or Infinity\r\n", "1e308"},
+		{"incrbyfloat not a float", executeIncrByFloat(), []string{"price", "1"}, map[string]string{"price": "abc"}, "-value is not a valid float\r\n", "abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := &MockStore{data: tt.data}
+			result := tt.hook(tt.args, mockStore)
+			if result != tt.expectedMsg {
+				t.Errorf("expected result: %q, got: %q", tt.expectedMsg, result)
+			}
+			if value := mockStore.data[tt.args[0]]; value != tt.expectedValue {
+				t.Errorf("expected value: %s, got: %s", tt.expectedValue, value)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"strconv"
+
+	"treds/resp"
+	"treds/store"
+)
+
+const IncrByFloatCommand = "INCRBYFLOAT"
+
+func RegisterIncrByFloatCommand(r CommandRegistry) {
+	r.Add(&CommandRegistration{
+		Name: IncrByFloatCommand,
+		Spec: Spec{
+			Summary: "Increments the float value of a key by a number",
+			Group:   GroupString,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "increment", Type: ArgDouble},
+			},
+		},
+		Execute:    executeIncrByFloat(),
+		ReplyRESP3: doubleReply,
+	})
+}
+
+func executeIncrByFloat() ExecutionHook {
+	return func(args []string, store store.Store) string {
+		increment, err := strconv.ParseFloat(args[1], 64)
+		if err != nil {
+			return resp.EncodeError(err.Error())
+		}
+		res, err := store.IncrByFloat(args[0], increment)
+		if err != nil {
+			return resp.EncodeError(err.Error())
+		}
+		return resp.EncodeBulkString(strconv.FormatFloat(res, 'f', -1, 64))
+	}
+}
@@ -1,9 +1,7 @@
 package commands
 
 import (
-	"fmt"
 	"math"
-	"regexp"
 	"strconv"
 
 	"treds/resp"
@@ -14,31 +12,22 @@ const KeysCommand = "KEYS"
 
 func RegisterKeysCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     KeysCommand,
-		Validate: validateKeys(),
-		Execute:  executeKeys(),
+		Name: KeysCommand,
+		Spec: Spec{
+			Summary: "Returns the keys matching a regex in lex order starting at a cursor, the last element is the next cursor",
+			Group:   GroupString,
+			Flags:   []string{FlagReadonly},
+			AllKeys: true,
+			Args: []Arg{
+				{Name: "cursor", Type: ArgString},
+				{Name: "regex", Type: ArgPattern},
+				{Name: "count", Type: ArgInteger, Optional: true},
+			},
+		},
+		Execute: executeKeys(),
 	})
 }
 
-func validateKeys() ValidationHook {
-	return func(args []string) error {
-		if len(args) < 2 {
-			return fmt.Errorf("expected minimum 2 argument, got %d", len(args))
-		}
-		if len(args) == 3 {
-			_, err := strconv.Atoi(args[2])
-			if err != nil {
-				return err
-			}
-		}
-		_, err := regexp.Compile(args[1])
-		if err != nil {
-			return err
-		}
-		return nil
-	}
-}
-
 func executeKeys() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		regex := ""
@@ -1,9 +1,7 @@
 package commands
 
 import (
-	"fmt"
 	"math"
-	"regexp"
 	"strconv"
 
 	"treds/resp"
@@ -14,32 +12,43 @@ const KVSCommand = "KVS"
 
 func RegisterKVSCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     KVSCommand,
-		Validate: validateKVS(),
-		Execute:  executeKVS(),
+		Name: KVSCommand,
+		Spec: Spec{
+			Summary: "Returns the key value pairs whose key matches a regex in lex order starting at a cursor, the last element is the next cursor",
+			Group:   GroupString,
+			Flags:   []string{FlagReadonly},
+			AllKeys: true,
+			Args: []Arg{
+				{Name: "cursor", Type: ArgString},
+				{Name: "regex", Type: ArgPattern},
+				{Name: "count", Type: ArgInteger, Optional: true},
+			},
+		},
+		Execute:      executeKVS(),
+		ExecuteRESP3: executeKVSRESP3(),
 	})
 }
 
-func validateKVS() ValidationHook {
-	return func(args []string) error {
-		if len(args) < 2 {
-			return fmt.Errorf("expected minimum 2 argument, got %d", len(args))
+func executeKVS() ExecutionHook {
+	return func(args []string, store store.Store) string {
+		regex := ""
+		count := math.MaxInt64
+		if len(args) >= 2 {
+			regex = args[1]
 		}
 		if len(args) == 3 {
-			_, err := strconv.Atoi(args[2])
-			if err != nil {
-				return err
-			}
+			count, _ = strconv.Atoi(args[2])
 		}
-		_, err := regexp.Compile(args[1])
+		v, err := store.KVS(args[0], regex, count)
 		if err != nil {
-			return err
+			return resp.EncodeError(err.Error())
 		}
-		return nil
+		return resp.EncodeStringArray(v)
 	}
 }
 
-func executeKVS() ExecutionHook {
+// executeKVSRESP3 replies with a map of the matching pairs followed by the next cursor
+func executeKVSRESP3() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		regex := ""
 		count := math.MaxInt64
@@ -53,6 +62,7 @@ func executeKVS() ExecutionHook {
 		if err != nil {
 			return resp.EncodeError(err.Error())
 		}
-		return resp.EncodeStringArray(v)
+		cursor := v[len(v)-1]
+		return "*2\r\n" + resp.EncodeStringMap(v[:len(v)-1]) + resp.EncodeBulkString(cursor)
 	}
 }
@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,22 +9,20 @@ const LIndexCommand = "LINDEX"
 
 func RegisterLIndexCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     LIndexCommand,
-		Validate: validateLIndexCommand(),
-		Execute:  executeLIndexCommand(),
+		Name: LIndexCommand,
+		Spec: Spec{
+			Summary: "Returns the element at an index of a list",
+			Group:   GroupList,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "index", Type: ArgInteger},
+			},
+		},
+		Execute: executeLIndexCommand(),
 	})
 }
 
-func validateLIndexCommand() ValidationHook {
-	return func(args []string) error {
-		if len(args) != 2 {
-			return fmt.Errorf("expected 2 argument, got %d", len(args))
-		}
-
-		return nil
-	}
-}
-
 func executeLIndexCommand() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		res, err := store.LIndex(args)
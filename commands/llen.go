@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,22 +9,19 @@ const LLenCommand = "LLEN"
 
 func RegisterLLenCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     LLenCommand,
-		Validate: validateLLenCommand(),
-		Execute:  executeLLenCommand(),
+		Name: LLenCommand,
+		Spec: Spec{
+			Summary: "Returns the length of a list",
+			Group:   GroupList,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+			},
+		},
+		Execute: executeLLenCommand(),
 	})
 }
 
-func validateLLenCommand() ValidationHook {
-	return func(args []string) error {
-		if len(args) != 1 {
-			return fmt.Errorf("expected 1 argument, got %d", len(args))
-		}
-
-		return nil
-	}
-}
-
 func executeLLenCommand() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		key := args[0]
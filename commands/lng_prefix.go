@@ -9,9 +9,17 @@ const LongestPrefixCommand = "LNGPREFIX"
 
 func RegisterLongestPrefixCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     LongestPrefixCommand,
-		Validate: validateDeletePrefix(),
-		Execute:  executeLongestPrefixCommand(),
+		Name: LongestPrefixCommand,
+		Spec: Spec{
+			Summary: "Returns the key value pair whose key is the longest prefix of a string",
+			Group:   GroupString,
+			Flags:   []string{FlagReadonly},
+			AllKeys: true,
+			Args: []Arg{
+				{Name: "string", Type: ArgString},
+			},
+		},
+		Execute: executeLongestPrefixCommand(),
 	})
 }
 
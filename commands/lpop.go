@@ -1,7 +1,6 @@
 package commands
 
 import (
-	"fmt"
 	"strconv"
 
 	"treds/resp"
@@ -12,23 +11,20 @@ const LPopCommand = "LPOP"
 
 func RegisterLPopCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     LPopCommand,
-		Validate: validateLPopCommand(),
-		Execute:  executeLPopCommand(),
-		IsWrite:  true,
+		Name: LPopCommand,
+		Spec: Spec{
+			Summary: "Removes and returns elements from the left of a list",
+			Group:   GroupList,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "count", Type: ArgInteger},
+			},
+		},
+		Execute: executeLPopCommand(),
 	})
 }
 
-func validateLPopCommand() ValidationHook {
-	return func(args []string) error {
-		if len(args) != 2 {
-			return fmt.Errorf("expected 2 argument, got %d", len(args))
-		}
-
-		return nil
-	}
-}
-
 func executeLPopCommand() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		key := args[0]
@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,23 +9,20 @@ const LPushCommand = "LPUSH"
 
 func RegisterLPushCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     LPushCommand,
-		Validate: validateLPushCommand(),
-		Execute:  executeLPushCommand(),
-		IsWrite:  true,
+		Name: LPushCommand,
+		Spec: Spec{
+			Summary: "Adds elements to the left of a list",
+			Group:   GroupList,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "element", Type: ArgString, Multiple: true},
+			},
+		},
+		Execute: executeLPushCommand(),
 	})
 }
 
-func validateLPushCommand() ValidationHook {
-	return func(args []string) error {
-		if len(args) < 2 {
-			return fmt.Errorf("expected minimum 2 argument, got %d", len(args))
-		}
-
-		return nil
-	}
-}
-
 func executeLPushCommand() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		err := store.LPush(args)
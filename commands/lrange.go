@@ -1,7 +1,6 @@
 package commands
 
 import (
-	"fmt"
 	"strconv"
 
 	"treds/resp"
@@ -12,22 +11,21 @@ const LRangeCommand = "LRANGE"
 
 func RegisterLRangeCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     LRangeCommand,
-		Validate: validateLRangeCommand(),
-		Execute:  executeLRangeCommand(),
+		Name: LRangeCommand,
+		Spec: Spec{
+			Summary: "Returns the elements of a list from start to stop",
+			Group:   GroupList,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "start", Type: ArgInteger},
+				{Name: "stop", Type: ArgInteger},
+			},
+		},
+		Execute: executeLRangeCommand(),
 	})
 }
 
-func validateLRangeCommand() ValidationHook {
-	return func(args []string) error {
-		if len(args) != 3 {
-			return fmt.Errorf("expected 3 argument, got %d", len(args))
-		}
-
-		return nil
-	}
-}
-
 func executeLRangeCommand() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		key := args[0]
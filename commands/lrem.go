@@ -1,7 +1,6 @@
 package commands
 
 import (
-	"fmt"
 	"strconv"
 
 	"treds/resp"
@@ -12,23 +11,20 @@ const LRemCommand = "LREM"
 
 func RegisterLRemCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     LRemCommand,
-		Validate: validateLRemCommand(),
-		Execute:  executeLRemCommand(),
-		IsWrite:  true,
+		Name: LRemCommand,
+		Spec: Spec{
+			Summary: "Removes the element at an index of a list",
+			Group:   GroupList,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "index", Type: ArgInteger},
+			},
+		},
+		Execute: executeLRemCommand(),
 	})
 }
 
-func validateLRemCommand() ValidationHook {
-	return func(args []string) error {
-		if len(args) != 2 {
-			return fmt.Errorf("expected 2 argument, got %d", len(args))
-		}
-
-		return nil
-	}
-}
-
 func executeLRemCommand() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		key := args[0]
@@ -1,9 +1,7 @@
 package commands
 
 import (
-	"fmt"
 	"strconv"
-	"strings"
 
 	"treds/resp"
 	"treds/store"
@@ -13,22 +11,21 @@ const LSetCommand = "LSET"
 
 func RegisterLSetCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     LSetCommand,
-		Validate: validateLSetCommand(),
-		Execute:  executeLSetCommand(),
-		IsWrite:  true,
+		Name: LSetCommand,
+		Spec: Spec{
+			Summary: "Sets the element at an index of a list",
+			Group:   GroupList,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "index", Type: ArgInteger},
+				{Name: "element", Type: ArgString},
+			},
+		},
+		Execute: executeLSetCommand(),
 	})
 }
 
-func validateLSetCommand() ValidationHook {
-	return func(args []string) error {
-		if len(args) < 3 {
-			return fmt.Errorf("expected 3 argument, got %d", len(args))
-		}
-		return nil
-	}
-}
-
 func executeLSetCommand() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		key := args[0]
@@ -36,7 +33,7 @@ func executeLSetCommand() ExecutionHook {
 		if err != nil {
 			return resp.EncodeError(err.Error())
 		}
-		err = store.LSet(key, index, strings.Join(args[2:], " "))
+		err = store.LSet(key, index, args[2])
 		if err != nil {
 			return resp.EncodeError(err.Error())
 		}
@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,27 +9,49 @@ const MGetCommand = "MGET"
 
 func RegisterMGetCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     MGetCommand,
-		Validate: validateMGet(),
-		Execute:  executeMGet(),
+		Name: MGetCommand,
+		Spec: Spec{
+			Summary: "Returns the values of multiple keys",
+			Group:   GroupString,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey, Multiple: true},
+			},
+		},
+		Execute:      executeMGet(),
+		ExecuteRESP3: executeMGetRESP3(),
 	})
 }
 
-func validateMGet() ValidationHook {
-	return func(args []string) error {
-		if len(args) == 0 {
-			return fmt.Errorf("expected atlest 1 argument, got %d", len(args))
+func executeMGet() ExecutionHook {
+	return func(args []string, s store.Store) string {
+		res, found, err := s.MGet(args)
+		if err != nil {
+			return resp.EncodeError(err.Error())
+		}
+		for i := range res {
+			if !found[i] {
+				res[i] = store.NilResp
+			}
 		}
-		return nil
+		return resp.EncodeStringArray(res)
 	}
 }
 
-func executeMGet() ExecutionHook {
-	return func(args []string, store store.Store) string {
-		res, err := store.MGet(args)
+func executeMGetRESP3() ExecutionHook {
+	return func(args []string, s store.Store) string {
+		res, found, err := s.MGet(args)
 		if err != nil {
 			return resp.EncodeError(err.Error())
 		}
-		return resp.EncodeStringArray(res)
+		elements := make([]interface{}, 0, len(res))
+		for i, value := range res {
+			if !found[i] {
+				elements = append(elements, nil)
+				continue
+			}
+			elements = append(elements, value)
+		}
+		return resp.EncodeArray(elements)
 	}
 }
@@ -14,7 +14,7 @@ func TestRegisterMGetCommand(t *testing.T) {
 	}
 }
 
-// TestValidateMGet tests the validateMGet function.
+// TestValidateMGet tests the validation generated from the spec.
 func TestValidateMGet(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -23,12 +23,12 @@ func TestValidateMGet(t *testing.T) {
 		expectedMsg string
 	}{
 		{"valid args", []string{"key1"}, false, ""},
-		{"no args", []string{}, true, "expected atlest 1 argument, got 0"},
+		{"no args", []string{}, true, "expected minimum 1 argument, got 0"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			validationHook := validateMGet()
+			validationHook := registeredCommand(t, RegisterMGetCommand, MGetCommand).Validate
 			err := validationHook(tt.args)
 			if (err != nil) != tt.expectErr {
 				t.Errorf("expected error: %v, got: %v", tt.expectErr, err)
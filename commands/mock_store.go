@@ -2,33 +2,45 @@ package commands
 
 import (
 	"errors"
+	"fmt"
+	"math"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
+
+	"treds/store"
 )
 
 // MockStore is a mock implementation of the store interface for testing.
+// The increments, hashes and sorted maps return the errors of the store.
 type MockStore struct {
-	data map[string]string
+	data       map[string]string
+	hashes     map[string]map[string]string
+	sortedMaps map[string]map[string]mockMember
+}
+
+// mockMember is a member of a sorted map with its score and value
+type mockMember struct {
+	score float64
+	value string
 }
 
-func (m *MockStore) Get(key string) (string, error) {
+func (m *MockStore) Get(key string) (string, bool, error) {
 	val, exists := m.data[key]
-	if !exists {
-		return "(nil)", errors.New("key does not exist")
-	}
-	return val, nil
+	return val, exists, nil
 }
 
-func (m *MockStore) MGet(keys []string) ([]string, error) {
+func (m *MockStore) MGet(keys []string) ([]string, []bool, error) {
 	res := make([]string, 0)
+	found := make([]bool, 0)
 	for _, key := range keys {
-		val, _ := m.Get(key)
+		val, exists, _ := m.Get(key)
 		res = append(res, val)
+		found = append(found, exists)
 	}
-	return res, nil
+	return res, found, nil
 }
 
 func (m *MockStore) MSet(keys []string) error {
@@ -40,6 +52,55 @@ func (m *MockStore) Set(key, value string) error {
 	return nil
 }
 
+func (m *MockStore) IncrBy(key string, delta int64) (int64, error) {
+	result, err := mockIncrementInt(m.valueOrZero(key), delta)
+	if err != nil {
+		return 0, err
+	}
+	m.data[key] = strconv.FormatInt(result, 10)
+	return result, nil
+}
+
+func (m *MockStore) IncrByFloat(key string, delta float64) (float64, error) {
+	result, err := mockIncrementFloat(m.valueOrZero(key), delta)
+	if err != nil {
+		return 0, err
+	}
+	m.data[key] = strconv.FormatFloat(result, 'f', -1, 64)
+	return result, nil
+}
+
+// mockIncrementInt and mockIncrementFloat fail like the increments of the store
+func mockIncrementInt(value string, delta int64) (int64, error) {
+	current, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, store.ErrNotInteger
+	}
+	if (delta > 0 && current > math.MaxInt64-delta) || (delta < 0 && current < math.MinInt64-delta) {
+		return 0, store.ErrOverflow
+	}
+	return current + delta, nil
+}
+
+func mockIncrementFloat(value string, delta float64) (float64, error) {
+	current, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(current) || math.IsInf(current, 0) {
+		return 0, store.ErrNotFloat
+	}
+	result := current + delta
+	if math.IsNaN(result) || math.IsInf(result, 0) {
+		return 0, store.ErrNotFinite
+	}
+	return result, nil
+}
+
+func (m *MockStore) valueOrZero(key string) string {
+	if val, exists := m.data[key]; exists {
+		return val
+	}
+	return "0"
+}
+
 func (m *MockStore) Delete(key string) error {
 	if _, exists := m.data[key]; !exists {
 		return errors.New("key does not exist")
@@ -198,7 +259,28 @@ func (m *MockStore) Size() (int, error) {
 	return 0, nil
 }
 
-func (m *MockStore) ZAdd([]string) error {
+func (m *MockStore) ZAdd(args []string) error {
+	if _, ok := m.hashes[args[0]]; ok {
+		return fmt.Errorf("not sorted map store")
+	}
+	if (len(args)-1)%3 != 0 {
+		return fmt.Errorf("expected score, key and value triples")
+	}
+	if m.sortedMaps == nil {
+		m.sortedMaps = make(map[string]map[string]mockMember)
+	}
+	members, ok := m.sortedMaps[args[0]]
+	if !ok {
+		members = make(map[string]mockMember)
+		m.sortedMaps[args[0]] = members
+	}
+	for i := 1; i+2 < len(args); i += 3 {
+		score, err := strconv.ParseFloat(args[i], 64)
+		if err != nil {
+			return err
+		}
+		members[args[i+1]] = mockMember{score: score, value: args[i+2]}
+	}
 	return nil
 }
 
@@ -206,8 +288,36 @@ func (m *MockStore) ZRem([]string) error {
 	return nil
 }
 
-func (m *MockStore) ZRange(string, int, int, bool) ([]string, error) {
-	return nil, nil
+// ZRange returns the members in score order, members with the same score in lexicographical order
+func (m *MockStore) ZRange(key string, startIndex int, endIndex int, withScore bool) ([]string, error) {
+	if _, ok := m.hashes[key]; ok {
+		return nil, fmt.Errorf("not sorted map store")
+	}
+	members, ok := m.sortedMaps[key]
+	if !ok {
+		return nil, nil
+	}
+	names := make([]string, 0, len(members))
+	for name := range members {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if members[names[i]].score != members[names[j]].score {
+			return members[names[i]].score < members[names[j]].score
+		}
+		return names[i] < names[j]
+	})
+	if startIndex < 0 || startIndex >= len(names) {
+		return nil, fmt.Errorf("invalid input")
+	}
+	result := make([]string, 0)
+	for _, name := range names[startIndex:min(max(endIndex, startIndex), len(names))] {
+		if withScore {
+			result = append(result, strconv.FormatFloat(members[name].score, 'f', -1, 64))
+		}
+		result = append(result, name, members[name].value)
+	}
+	return result, nil
 }
 
 func (m *MockStore) ZRangeByLexKVS(string, string, string, string, string, bool) ([]string, error) {
@@ -224,10 +334,31 @@ func (m *MockStore) ZRangeByScoreKeys(string, string, string, string, string, bo
 func (m *MockStore) ZRangeByScoreKVS(string, string, string, string, string, bool) ([]string, error) {
 	return nil, nil
 }
-func (m *MockStore) ZScore([]string) (string, error) {
+func (m *MockStore) ZScore(args []string) (string, error) {
+	if _, ok := m.hashes[args[0]]; ok {
+		return "", fmt.Errorf("not sorted map store")
+	}
+	if member, ok := m.sortedMaps[args[0]][args[1]]; ok {
+		return strconv.FormatFloat(member.score, 'f', -1, 64), nil
+	}
 	return "", nil
 }
 
+func (m *MockStore) ZIncrBy(key string, delta float64, member string) (float64, error) {
+	if _, ok := m.hashes[key]; ok {
+		return 0, fmt.Errorf("not sorted map store")
+	}
+	stored := m.sortedMaps[key][member]
+	score := stored.score + delta
+	if math.IsNaN(score) || math.IsInf(score, 0) {
+		return 0, store.ErrNotFinite
+	}
+	if err := m.ZAdd([]string{key, strconv.FormatFloat(score, 'f', -1, 64), member, stored.value}); err != nil {
+		return 0, err
+	}
+	return score, nil
+}
+
 func (m *MockStore) ZCard(string) (int, error) {
 	return 0, nil
 }
@@ -320,11 +451,71 @@ func (rs *MockStore) SDiff(keys []string) ([]string, error) {
 }
 
 func (rs *MockStore) HSet(key string, args []string) error {
+	if _, ok := rs.sortedMaps[key]; ok {
+		return fmt.Errorf("not hash store")
+	}
+	if len(args)%2 != 0 {
+		return fmt.Errorf("expected field value pairs")
+	}
+	for i := 0; i < len(args); i += 2 {
+		rs.hashPut(key, args[i], args[i+1])
+	}
 	return nil
 }
 
-func (rs *MockStore) HGet(key string, field string) (string, error) {
-	return "", nil
+func (rs *MockStore) HIncrBy(key string, field string, delta int64) (int64, error) {
+	current, err := rs.hashField(key, field)
+	if err != nil {
+		return 0, err
+	}
+	result, err := mockIncrementInt(current, delta)
+	if err != nil {
+		return 0, err
+	}
+	rs.hashPut(key, field, strconv.FormatInt(result, 10))
+	return result, nil
+}
+
+func (rs *MockStore) HIncrByFloat(key string, field string, delta float64) (float64, error) {
+	current, err := rs.hashField(key, field)
+	if err != nil {
+		return 0, err
+	}
+	result, err := mockIncrementFloat(current, delta)
+	if err != nil {
+		return 0, err
+	}
+	rs.hashPut(key, field, strconv.FormatFloat(result, 'f', -1, 64))
+	return result, nil
+}
+
+// hashField returns the value of field in the hash key, "0" when it is missing
+func (rs *MockStore) hashField(key string, field string) (string, error) {
+	if _, ok := rs.sortedMaps[key]; ok {
+		return "", fmt.Errorf("not hash store")
+	}
+	if value, ok := rs.hashes[key][field]; ok {
+		return value, nil
+	}
+	return "0", nil
+}
+
+func (rs *MockStore) hashPut(key string, field string, value string) {
+	if rs.hashes == nil {
+		rs.hashes = make(map[string]map[string]string)
+	}
+	if rs.hashes[key] == nil {
+		rs.hashes[key] = make(map[string]string)
+	}
+	rs.hashes[key][field] = value
+}
+
+func (rs *MockStore) HGet(key string, field string) (string, bool, error) {
+	if _, ok := rs.sortedMaps[key]; ok {
+		return "", false, fmt.Errorf("not hash store")
+	}
+	value, ok := rs.hashes[key][field]
+	return value, ok, nil
 }
 
 func (rs *MockStore) HGetAll(key string) ([]string, error) {
@@ -353,6 +544,10 @@ func (rs *MockStore) HVals(key string) ([]string, error) {
 func (rs *MockStore) CleanUpExpiredKeys() {
 }
 
+func (rs *MockStore) Keyspace() store.KeyspaceStats {
+	return store.KeyspaceStats{}
+}
+
 func (rs *MockStore) Expire(key string, expiration time.Time) error {
 	return nil
 }
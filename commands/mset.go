@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,22 +9,22 @@ const MSETCommand = "MSET"
 
 func RegisterMSetCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     MSETCommand,
-		Validate: validateMSet(),
-		Execute:  executeMSet(),
-		IsWrite:  true,
+		Name: MSETCommand,
+		Spec: Spec{
+			Summary: "Sets the values of multiple keys",
+			Group:   GroupString,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "data", Type: ArgBlock, Multiple: true, Args: []Arg{
+					{Name: "key", Type: ArgKey},
+					{Name: "value", Type: ArgString},
+				}},
+			},
+		},
+		Execute: executeMSet(),
 	})
 }
 
-func validateMSet() ValidationHook {
-	return func(args []string) error {
-		if len(args) < 2 {
-			return fmt.Errorf("expected minimum 2 argument, got %d", len(args))
-		}
-		return nil
-	}
-}
-
 func executeMSet() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		err := store.MSet(args)
@@ -9,9 +9,16 @@ const PING = "PING"
 
 func RegisterPINGCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     PING,
-		Validate: validateDBSize(),
-		Execute:  executePINGCommand(),
+		Name: PING,
+		Spec: Spec{
+			Summary: "Replies with PONG",
+			Group:   GroupConnection,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "message", Type: ArgString, Optional: true},
+			},
+		},
+		Execute: executePINGCommand(),
 	})
 }
 
@@ -2,28 +2,55 @@ package commands
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
+	"treds/resp"
 	"treds/store"
 )
 
 type CommandRegistry interface {
 	Add(*CommandRegistration) error
 	Retrieve(string) (*CommandRegistration, error)
+	// All returns the registered commands sorted by name
+	All() []*CommandRegistration
 }
 
 type commandRegistry struct {
 	commands map[string]*CommandRegistration
 }
 
-type ValidationHook func(args []string) error
 type ExecutionHook func(args []string, store store.Store) string
 
 type CommandRegistration struct {
-	Name     string
-	Validate ValidationHook
-	Execute  ExecutionHook
-	IsWrite  bool
+	Name string
+	// Spec describes the arguments of the command, they are validated against it
+	Spec    Spec
+	Execute ExecutionHook
+	// ExecuteRESP3 replaces Execute for clients which negotiated RESP3 with HELLO.
+	// It is only set by commands whose reply uses a RESP3 type, like maps, doubles or booleans.
+	ExecuteRESP3 ExecutionHook
+	// ReplyRESP3 converts the reply of Execute for RESP3 clients. Writes set it instead of ExecuteRESP3 since they
+	// are executed when applied through Raft, whatever the protocol of the client.
+	ReplyRESP3 func(reply string) string
+}
+
+// Validate checks args against the spec of the command
+func (reg *CommandRegistration) Validate(args []string) error {
+	return reg.Spec.Validate(args)
+}
+
+// Reply returns reply, produced by Execute, for a client speaking protocol
+func (reg *CommandRegistration) Reply(reply string, protocol int) string {
+	if reg.ReplyRESP3 == nil || protocol != resp.RESP3 {
+		return reply
+	}
+	return reg.ReplyRESP3(reply)
+}
+
+// IsWrite tells if the command changes the store, writes are applied through Raft
+func (reg *CommandRegistration) IsWrite() bool {
+	return reg.Spec.HasFlag(FlagWrite)
 }
 
 func NewRegistry() CommandRegistry {
@@ -48,3 +75,12 @@ func (c *commandRegistry) Retrieve(name string) (*CommandRegistration, error) {
 
 	return c.commands[strings.ToUpper(name)], nil
 }
+
+func (c *commandRegistry) All() []*CommandRegistration {
+	all := make([]*CommandRegistration, 0, len(c.commands))
+	for _, reg := range c.commands {
+		all = append(all, reg)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+	return all
+}
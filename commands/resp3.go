@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"bufio"
+	"strconv"
+	"strings"
+
+	"treds/resp"
+)
+
+// rangeEncoder encodes the flat result of a sorted map range,
+// withScore tells if every entry of the result starts with its score
+type rangeEncoder func(v []string, withScore bool) string
+
+// encodeRange encodes a range as a RESP2 array of bulk strings
+func encodeRange(v []string, _ bool) string {
+	return resp.EncodeStringArray(v)
+}
+
+// doubleReply converts a number sent as a bulk string to a RESP3 double, other replies like errors are kept
+func doubleReply(reply string) string {
+	value, err := resp.ParseReply(bufio.NewReader(strings.NewReader(reply)))
+	if err != nil {
+		return reply
+	}
+	s, ok := value.(string)
+	if !ok {
+		return reply
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return reply
+	}
+	return resp.EncodeDouble(f)
+}
+
+// encodeScoredRange encodes a range for RESP3 clients, scores are sent as doubles.
+// stride is the number of elements of each entry, including the score.
+func encodeScoredRange(stride int) rangeEncoder {
+	return func(v []string, withScore bool) string {
+		if !withScore {
+			return resp.EncodeStringArray(v)
+		}
+		elements := make([]interface{}, 0, len(v))
+		for i, s := range v {
+			if i%stride == 0 {
+				score, err := strconv.ParseFloat(s, 64)
+				if err == nil {
+					elements = append(elements, score)
+					continue
+				}
+			}
+			elements = append(elements, s)
+		}
+		return resp.EncodeArray(elements)
+	}
+}
@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"testing"
+
+	"treds/resp"
+)
+
+func TestEncodeScoredRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		v         []string
+		stride    int
+		withScore bool
+		expected  string
+	}{
+		{
+			name:      "score key value",
+			v:         []string{"1.5", "k1", "v1", "2", "k2", "v2"},
+			stride:    3,
+			withScore: true,
+			expected:  "*6\r\n,1.5\r\n$2\r\nk1\r\n$2\r\nv1\r\n,2\r\n$2\r\nk2\r\n$2\r\nv2\r\n",
+		},
+		{
+			name:      "score key",
+			v:         []string{"1", "k1", "3", "k2"},
+			stride:    2,
+			withScore: true,
+			expected:  "*4\r\n,1\r\n$2\r\nk1\r\n,3\r\n$2\r\nk2\r\n",
+		},
+		{
+			name:      "without score",
+			v:         []string{"1", "k1"},
+			stride:    2,
+			withScore: false,
+			expected:  "*2\r\n$1\r\n1\r\n$2\r\nk1\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := encodeScoredRange(tt.stride)(tt.v, tt.withScore)
+			if result != tt.expected {
+				t.Errorf("expected result: %q, got: %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestExecuteGetRESP3 tests a missing value is a null and a stored "(nil)" stays a bulk string.
+func TestExecuteGetRESP3(t *testing.T) {
+	mockStore := &MockStore{data: map[string]string{"k1": "(nil)"}}
+	if err := mockStore.HSet("h", []string{"f", "(nil)"}); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		hook     ExecutionHook
+		args     []string
+		expected string
+	}{
+		{"get stored nil", executeGetRESP3(), []string{"k1"}, "$5\r\n(nil)\r\n"},
+		{"get missing", executeGetRESP3(), []string{"k2"}, "_\r\n"},
+		{"hget stored nil", executeHGetCommandRESP3(), []string{"h", "f"}, "$5\r\n(nil)\r\n"},
+		{"hget missing", executeHGetCommandRESP3(), []string{"h", "g"}, "_\r\n"},
+		{"mget", executeMGetRESP3(), []string{"k1", "k2"}, "*2\r\n$5\r\n(nil)\r\n_\r\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.hook(tt.args, mockStore)
+			if result != tt.expected {
+				t.Errorf("expected result: %q, got: %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestIncrementReplyRESP3 tests the scores and values returned by increments are doubles for RESP3 clients.
+func TestIncrementReplyRESP3(t *testing.T) {
+	tests := []struct {
+		name     string
+		reg      *CommandRegistration
+		reply    string
+		protocol int
+		expected string
+	}{
+		{"incrbyfloat", registeredCommand(t, RegisterIncrByFloatCommand, IncrByFloatCommand), "$4\r\n1.25\r\n", resp.RESP3, ",1.25\r\n"},
+		{"incrbyfloat resp2", registeredCommand(t, RegisterIncrByFloatCommand, IncrByFloatCommand), "$4\r\n1.25\r\n", resp.RESP2, "$4\r\n1.25\r\n"},
+		{"zincrby", registeredCommand(t, RegisterZIncrByCommand, ZIncrByCommand), "$2\r\n-3\r\n", resp.RESP3, ",-3\r\n"},
+		{"zincrby error", registeredCommand(t, RegisterZIncrByCommand, ZIncrByCommand), "-not sorted map store\r\n", resp.RESP3, "-not sorted map store\r\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.reg.Reply(tt.reply, tt.protocol)
+			if result != tt.expected {
+				t.Errorf("expected result: %q, got: %q", tt.expected, result)
+			}
+		})
+	}
+}
@@ -11,10 +11,17 @@ const RPopCommand = "RPOP"
 
 func RegisterRPopCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     RPopCommand,
-		Validate: validateLPopCommand(),
-		Execute:  executeRPopCommand(),
-		IsWrite:  true,
+		Name: RPopCommand,
+		Spec: Spec{
+			Summary: "Removes and returns elements from the right of a list",
+			Group:   GroupList,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "count", Type: ArgInteger},
+			},
+		},
+		Execute: executeRPopCommand(),
 	})
 }
 
@@ -9,10 +9,17 @@ const RPushCommand = "RPUSH"
 
 func RegisterRPushCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     RPushCommand,
-		Validate: validateLPushCommand(),
-		Execute:  executeRPushCommand(),
-		IsWrite:  true,
+		Name: RPushCommand,
+		Spec: Spec{
+			Summary: "Adds elements to the right of a list",
+			Group:   GroupList,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "element", Type: ArgString, Multiple: true},
+			},
+		},
+		Execute: executeRPushCommand(),
 	})
 }
 
@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,23 +9,20 @@ const SAddCommand = "SADD"
 
 func RegisterSAddCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     SAddCommand,
-		Validate: validateSAddCommand(),
-		Execute:  executeSAddCommand(),
-		IsWrite:  true,
+		Name: SAddCommand,
+		Spec: Spec{
+			Summary: "Adds members to a set",
+			Group:   GroupSet,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "member", Type: ArgString, Multiple: true},
+			},
+		},
+		Execute: executeSAddCommand(),
 	})
 }
 
-func validateSAddCommand() ValidationHook {
-	return func(args []string) error {
-		if len(args) < 2 {
-			return fmt.Errorf("expected minimum 2 argument, got %d", len(args))
-		}
-
-		return nil
-	}
-}
-
 func executeSAddCommand() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		key := args[0]
@@ -12,7 +12,7 @@ func TestRegisterPrefixScanKeys(t *testing.T) {
 	}
 }
 
-// TestValidatePrefixScanKeys tests the validatePrefixScanKeys function.
+// TestValidatePrefixScanKeys tests the validation generated from the spec.
 func TestValidatePrefixScanKeys(t *testing.T) {
 	t.Skip()
 	tests := []struct {
@@ -30,7 +30,7 @@ func TestValidatePrefixScanKeys(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			validationHook := validatePrefixScanKeys()
+			validationHook := registeredCommand(t, RegisterScanKeysCommand, PrefixScanKeysCommand).Validate
 			err := validationHook(tt.args)
 			if (err != nil) != tt.expectErr {
 				t.Errorf("expected error: %v, got: %v", tt.expectErr, err)
@@ -1,7 +1,6 @@
 package commands
 
 import (
-	"fmt"
 	"math"
 	"strconv"
 
@@ -13,24 +12,21 @@ const PrefixScanCommand = "SCANKVS"
 
 func RegisterScanKVSCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     PrefixScanCommand,
-		Validate: validatePrefixScan(),
-		Execute:  executePrefixScan(),
+		Name: PrefixScanCommand,
+		Spec: Spec{
+			Summary: "Returns the key value pairs whose key matches a prefix in lex order starting at a cursor, the last element is the next cursor",
+			Group:   GroupString,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "cursor", Type: ArgString},
+				{Name: "prefix", Type: ArgPrefix},
+				{Name: "count", Type: ArgInteger, Optional: true},
+			},
+		},
+		Execute: executePrefixScan(),
 	})
 }
 
-func validatePrefixScan() ValidationHook {
-	return func(args []string) error {
-		if len(args) < 2 {
-			return fmt.Errorf("expected minimum 2 argument, got %d", len(args))
-		}
-		if len(args) > 3 {
-			return fmt.Errorf("expected maximum 3 argument, got %d", len(args))
-		}
-		return nil
-	}
-}
-
 func executePrefixScan() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		count := strconv.Itoa(math.MaxInt64)
@@ -14,7 +14,7 @@ func TestRegisterPrefixScan(t *testing.T) {
 	}
 }
 
-// TestValidatePrefixScan tests the validatePrefixScan function.
+// TestValidatePrefixScan tests the validation generated from the spec.
 func TestValidatePrefixScan(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -31,7 +31,7 @@ func TestValidatePrefixScan(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			validationHook := validatePrefixScan()
+			validationHook := registeredCommand(t, RegisterScanKVSCommand, PrefixScanCommand).Validate
 			err := validationHook(tt.args)
 			if (err != nil) != tt.expectErr {
 				t.Errorf("expected error: %v, got: %v", tt.expectErr, err)
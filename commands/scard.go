@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,21 +9,19 @@ const SCardCommand = "SCARD"
 
 func RegisterSCardCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     SCardCommand,
-		Validate: validateSCardCommand(),
-		Execute:  executeSCardCommand(),
+		Name: SCardCommand,
+		Spec: Spec{
+			Summary: "Returns the number of members of a set",
+			Group:   GroupSet,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+			},
+		},
+		Execute: executeSCardCommand(),
 	})
 }
 
-func validateSCardCommand() ValidationHook {
-	return func(args []string) error {
-		if len(args) != 1 {
-			return fmt.Errorf("expected 1 argument, got %d", len(args))
-		}
-		return nil
-	}
-}
-
 func executeSCardCommand() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		key := args[0]
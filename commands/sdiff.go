@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,22 +9,19 @@ const SDiffCommand = "SDIFF"
 
 func RegisterSDiffCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     SDiffCommand,
-		Validate: validateSDiffCommand(),
-		Execute:  executeSDiffCommand(),
+		Name: SDiffCommand,
+		Spec: Spec{
+			Summary: "Returns the members of the first set missing from the other sets",
+			Group:   GroupSet,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey, Multiple: true},
+			},
+		},
+		Execute: executeSDiffCommand(),
 	})
 }
 
-func validateSDiffCommand() ValidationHook {
-	return func(args []string) error {
-		if len(args) < 1 {
-			return fmt.Errorf("expected minimum 1 argument, got %d", len(args))
-		}
-
-		return nil
-	}
-}
-
 func executeSDiffCommand() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		res, err := store.SDiff(args)
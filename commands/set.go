@@ -1,9 +1,7 @@
 package commands
 
 import (
-	"fmt"
 	"log"
-	"strings"
 
 	"treds/resp"
 	"treds/store"
@@ -13,30 +11,26 @@ const SetCommand = "SET"
 
 func RegisterSetCommand(r CommandRegistry) {
 	err := r.Add(&CommandRegistration{
-		Name:     SetCommand,
-		Validate: validateSet(),
-		Execute:  executeSet(),
-		IsWrite:  true,
+		Name: SetCommand,
+		Spec: Spec{
+			Summary: "Sets the value of a key",
+			Group:   GroupString,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "value", Type: ArgString},
+			},
+		},
+		Execute: executeSet(),
 	})
 	if err != nil {
 		log.Fatal(err)
 	}
 }
 
-func validateSet() ValidationHook {
-	return func(args []string) error {
-		if len(args) < 2 {
-			return fmt.Errorf("expected 2 argument, got %d", len(args))
-		}
-
-		return nil
-	}
-}
-
 func executeSet() ExecutionHook {
 	return func(args []string, store store.Store) string {
-		value := strings.Join(args[1:], " ")
-		err := store.Set(args[0], value)
+		err := store.Set(args[0], args[1])
 		if err != nil {
 			return resp.EncodeError(err.Error())
 		}
@@ -14,7 +14,7 @@ func TestRegisterSetCommand(t *testing.T) {
 	}
 }
 
-// TestValidateSet tests the validateSet function.
+// TestValidateSet tests the validation generated from the spec.
 func TestValidateSet(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -28,7 +28,7 @@ func TestValidateSet(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			validationHook := validateSet()
+			validationHook := registeredCommand(t, RegisterSetCommand, SetCommand).Validate
 			err := validationHook(tt.args)
 			if (err != nil) != tt.expectErr {
 				t.Errorf("expected error: %v, got: %v", tt.expectErr, err)
@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,22 +9,19 @@ const SInterCommand = "SINTER"
 
 func RegisterSInterCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     SInterCommand,
-		Validate: validateSInterCommand(),
-		Execute:  executeSInterCommand(),
+		Name: SInterCommand,
+		Spec: Spec{
+			Summary: "Returns the intersection of sets",
+			Group:   GroupSet,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey, Multiple: true},
+			},
+		},
+		Execute: executeSInterCommand(),
 	})
 }
 
-func validateSInterCommand() ValidationHook {
-	return func(args []string) error {
-		if len(args) < 1 {
-			return fmt.Errorf("expected minimum 1 argument, got %d", len(args))
-		}
-
-		return nil
-	}
-}
-
 func executeSInterCommand() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		res, err := store.SInter(args)
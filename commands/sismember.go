@@ -1,9 +1,6 @@
 package commands
 
 import (
-	"fmt"
-	"strings"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -12,26 +9,25 @@ const SIsMember = "SISMEMBER"
 
 func RegisterSIsMemberCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     SIsMember,
-		Validate: validateSIsMemberCommand(),
-		Execute:  executeSIsMemberCommand(),
+		Name: SIsMember,
+		Spec: Spec{
+			Summary: "Tells if a member is in a set",
+			Group:   GroupSet,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "member", Type: ArgString},
+			},
+		},
+		Execute:      executeSIsMemberCommand(),
+		ExecuteRESP3: executeSIsMemberCommandRESP3(),
 	})
 }
 
-func validateSIsMemberCommand() ValidationHook {
-	return func(args []string) error {
-		if len(args) < 2 {
-			return fmt.Errorf("expected 2 argument, got %d", len(args))
-		}
-
-		return nil
-	}
-}
-
 func executeSIsMemberCommand() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		key := args[0]
-		res, err := store.SIsMember(key, strings.Join(args[1:], " "))
+		res, err := store.SIsMember(key, args[1])
 		if err != nil {
 			return resp.EncodeError(err.Error())
 		}
@@ -42,3 +38,13 @@ func executeSIsMemberCommand() ExecutionHook {
 		}
 	}
 }
+
+func executeSIsMemberCommandRESP3() ExecutionHook {
+	return func(args []string, store store.Store) string {
+		res, err := store.SIsMember(args[0], args[1])
+		if err != nil {
+			return resp.EncodeError(err.Error())
+		}
+		return resp.EncodeBoolean(res)
+	}
+}
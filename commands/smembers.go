@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,22 +9,19 @@ const SMembersCommand = "SMEMBERS"
 
 func RegisterSMembersCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     SMembersCommand,
-		Validate: validateSMembersCommand(),
-		Execute:  executeSMembersCommand(),
+		Name: SMembersCommand,
+		Spec: Spec{
+			Summary: "Returns the members of a set",
+			Group:   GroupSet,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+			},
+		},
+		Execute: executeSMembersCommand(),
 	})
 }
 
-func validateSMembersCommand() ValidationHook {
-	return func(args []string) error {
-		if len(args) != 1 {
-			return fmt.Errorf("expected 1 argument, got %d", len(args))
-		}
-
-		return nil
-	}
-}
-
 func executeSMembersCommand() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		key := args[0]
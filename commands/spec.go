@@ -0,0 +1,289 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ArgType is the type of a command argument, the validation generated from a Spec checks it
+type ArgType string
+
+const (
+	ArgKey     ArgType = "key"
+	ArgString  ArgType = "string"
+	ArgInteger ArgType = "integer"
+	ArgDouble  ArgType = "double"
+	ArgBoolean ArgType = "boolean"
+	// ArgPattern is a regular expression
+	ArgPattern ArgType = "pattern"
+	// ArgPrefix is a key prefix, ACL users need access to the prefix
+	ArgPrefix ArgType = "prefix"
+	// ArgBlock groups the arguments in Args, like the field and value pairs of HSET
+	ArgBlock ArgType = "block"
+)
+
+// Flags of commands, as reported by COMMAND
+const (
+	FlagWrite    = "write"
+	FlagReadonly = "readonly"
+	FlagPubSub   = "pubsub"
+	FlagAdmin    = "admin"
+)
+
+// Groups of commands, as reported by COMMAND DOCS
+const (
+	GroupGeneric      = "generic"
+	GroupString       = "string"
+	GroupSortedMap    = "sorted-map"
+	GroupList         = "list"
+	GroupSet          = "set"
+	GroupHash         = "hash"
+	GroupCollection   = "collection"
+	GroupVector       = "vector"
+	GroupPubSub       = "pubsub"
+	GroupTransactions = "transactions"
+	GroupConnection   = "connection"
+	GroupServer       = "server"
+)
+
+// Arg describes an argument of a command
+type Arg struct {
+	Name string
+	Type ArgType
+	// Optional arguments can be left out, Multiple arguments can be repeated
+	Optional bool
+	Multiple bool
+	// Args are the arguments of a block
+	Args []Arg
+}
+
+// Spec describes a command: its arguments, flags and summary. The arguments of a command are validated
+// against it, and COMMAND and COMMAND DOCS are served from it.
+type Spec struct {
+	Summary string
+	Group   string
+	Flags   []string
+	Args    []Arg
+	// AllKeys commands can work on keys their arguments do not name, like KEYS with a regex
+	AllKeys bool
+}
+
+// Arity is the number of arguments of the command, its name included, like Redis reports it.
+// It is negative when the command takes at least -Arity arguments.
+func (s *Spec) Arity() int {
+	minimum, maximum := minArgs(s.Args), maxArgs(s.Args)
+	if minimum != maximum {
+		return -(minimum + 1)
+	}
+	return minimum + 1
+}
+
+// HasFlag tells if the command has flag
+func (s *Spec) HasFlag(flag string) bool {
+	for _, f := range s.Flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyPositions returns the position of the first and last key, the command name being 0, and the step between
+// keys. last is -1 when keys go up to the last argument, and all of them are 0 when the command takes no key.
+func (s *Spec) KeyPositions() (first int, last int, step int) {
+	position := 1
+	for _, arg := range s.Args {
+		offset, width := -1, 1
+		if arg.Type == ArgKey {
+			offset = 0
+		} else if arg.Type == ArgBlock {
+			width = len(arg.Args)
+			for i, blockArg := range arg.Args {
+				if blockArg.Type == ArgKey {
+					offset = i
+					break
+				}
+			}
+		}
+		if offset >= 0 {
+			if arg.Multiple {
+				return position + offset, -1, width
+			}
+			return position + offset, position + offset, 1
+		}
+		if arg.Optional || arg.Multiple {
+			// The position of the following arguments depends on the call
+			return 0, 0, 0
+		}
+		position += width
+	}
+	return 0, 0, 0
+}
+
+// Syntax describes the arguments, like key value [key value ...]
+func (s *Spec) Syntax() string {
+	return syntax(s.Args)
+}
+
+// Validate checks that args, the command name excluded, match the spec
+func (s *Spec) Validate(args []string) error {
+	minimum, maximum := minArgs(s.Args), maxArgs(s.Args)
+	switch {
+	case minimum == maximum && len(args) != minimum:
+		return fmt.Errorf("expected %d argument, got %d", minimum, len(args))
+	case len(args) < minimum:
+		return fmt.Errorf("expected minimum %d argument, got %d", minimum, len(args))
+	case maximum >= 0 && len(args) > maximum:
+		return fmt.Errorf("expected maximum %d argument, got %d", maximum, len(args))
+	}
+	used, err := matchArgs(s.Args, args, checkArg)
+	if err != nil {
+		return err
+	}
+	if used != len(args) {
+		return fmt.Errorf("expected %s, got %d arguments", s.Syntax(), len(args))
+	}
+	return nil
+}
+
+// KeyArgs returns the keys and the key prefixes named by args, the command name excluded. The arguments are
+// matched against the spec the way Validate matches them.
+func (s *Spec) KeyArgs(args []string) (keys []string, prefixes []string) {
+	_, _ = matchArgs(s.Args, args, func(spec Arg, value string) error {
+		switch spec.Type {
+		case ArgKey:
+			keys = append(keys, value)
+		case ArgPrefix:
+			prefixes = append(prefixes, value)
+		}
+		return nil
+	})
+	return keys, prefixes
+}
+
+// matchArgs matches the leading args against specs and returns how many of them are used, visit is called with
+// every argument and the spec it matches. Every argument takes as many values as it can while leaving enough
+// values for the arguments after it.
+func matchArgs(specs []Arg, args []string, visit func(spec Arg, value string) error) (int, error) {
+	position := 0
+	for i, spec := range specs {
+		end := len(args) - minArgs(specs[i+1:])
+		for n := 0; n == 0 || spec.Multiple; n++ {
+			required := n == 0 && !spec.Optional
+			if !required && end-position < max(occurrenceArgs(spec), 1) {
+				break
+			}
+			used, err := matchArg(spec, args[position:max(end, position)], visit)
+			if err != nil {
+				return position, err
+			}
+			position += used
+			if used == 0 {
+				break
+			}
+		}
+	}
+	return position, nil
+}
+
+func matchArg(spec Arg, args []string, visit func(spec Arg, value string) error) (int, error) {
+	if spec.Type == ArgBlock {
+		return matchArgs(spec.Args, args, visit)
+	}
+	if len(args) == 0 {
+		return 0, fmt.Errorf("missing %s", spec.Name)
+	}
+	return 1, visit(spec, args[0])
+}
+
+// checkArg checks that value has the type of spec
+func checkArg(spec Arg, value string) error {
+	var err error
+	switch spec.Type {
+	case ArgInteger:
+		_, err = strconv.Atoi(value)
+	case ArgDouble:
+		_, err = strconv.ParseFloat(value, 64)
+	case ArgBoolean:
+		_, err = strconv.ParseBool(value)
+	case ArgPattern:
+		if _, errRegex := regexp.Compile(value); errRegex != nil {
+			return fmt.Errorf("invalid %s '%s': %v", spec.Name, value, errRegex)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%s should be %s, got '%s'", spec.Name, article(spec.Type), value)
+	}
+	return nil
+}
+
+func article(argType ArgType) string {
+	if argType == ArgInteger {
+		return "an integer"
+	}
+	return "a " + string(argType)
+}
+
+// minArgs is the least number of values specs take
+func minArgs(specs []Arg) int {
+	n := 0
+	for _, spec := range specs {
+		if spec.Optional {
+			continue
+		}
+		if spec.Type == ArgBlock {
+			n += minArgs(spec.Args)
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// occurrenceArgs is the least number of values one occurrence of spec takes
+func occurrenceArgs(spec Arg) int {
+	if spec.Type == ArgBlock {
+		return minArgs(spec.Args)
+	}
+	return 1
+}
+
+// maxArgs is the largest number of values specs take, -1 when it is unbounded
+func maxArgs(specs []Arg) int {
+	n := 0
+	for _, spec := range specs {
+		if spec.Multiple {
+			return -1
+		}
+		if spec.Type == ArgBlock {
+			blockMax := maxArgs(spec.Args)
+			if blockMax < 0 {
+				return -1
+			}
+			n += blockMax
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+func syntax(specs []Arg) string {
+	parts := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		part := spec.Name
+		if spec.Type == ArgBlock {
+			part = syntax(spec.Args)
+		}
+		if spec.Multiple {
+			part += " [" + part + " ...]"
+		}
+		if spec.Optional {
+			part = "[" + part + "]"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"slices"
+	"testing"
+)
+
+// registeredCommand registers a command with register and returns its registration
+func registeredCommand(t *testing.T, register func(CommandRegistry), name string) *CommandRegistration {
+	t.Helper()
+	registry := NewRegistry()
+	register(registry)
+	reg, err := registry.Retrieve(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reg
+}
+
+// TestSpecValidate tests the validation generated from specs with blocks, repeated and typed arguments.
+func TestSpecValidate(t *testing.T) {
+	registry := NewRegistry()
+	RegisterCommands(registry)
+	tests := []struct {
+		command     string
+		args        []string
+		expectedMsg string
+	}{
+		{MSETCommand, []string{"k1", "v1", "k2", "v2"}, ""},
+		{MSETCommand, []string{"k1", "v1", "k2"}, "expected key value [key value ...], got 3 arguments"},
+		{ZAddCommand, []string{"z", "1", "m", "v", "2.5", "n", "w"}, ""},
+		{ZAddCommand, []string{"z", "x", "m", "v"}, "score should be a double, got 'x'"},
+		{VSearch, []string{"vec", "1.5", "2.5", "2"}, ""},
+		{VSearch, []string{"vec", "1.5", "k"}, "k should be an integer, got 'k'"},
+		{ExpireCommand, []string{"k", "ten"}, "seconds should be an integer, got 'ten'"},
+		{ExpireCommand, []string{"k"}, "expected 2 argument, got 1"},
+		{KeysCommand, []string{"0", "user:("}, "invalid regex 'user:(': error parsing regexp: missing closing ): `user:(`"},
+		{ZRANGESCOREKEYS, []string{"z", "-inf", "inf", "0"}, ""},
+		{ZRANGESCOREKEYS, []string{"z", "0", "10", "0", "5", "yes"}, "withscore should be a boolean, got 'yes'"},
+		{VCreate, []string{"vec"}, ""},
+		{VCreate, []string{}, "expected minimum 1 argument, got 0"},
+		{FlushAll, []string{"now"}, "expected 0 argument, got 1"},
+	}
+	for _, tt := range tests {
+		reg, err := registry.Retrieve(tt.command)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = reg.Validate(tt.args)
+		if tt.expectedMsg == "" && err != nil {
+			t.Errorf("%s %v: unexpected error %v", tt.command, tt.args, err)
+		}
+		if tt.expectedMsg != "" && (err == nil || err.Error() != tt.expectedMsg) {
+			t.Errorf("%s %v: expected error %s, got %v", tt.command, tt.args, tt.expectedMsg, err)
+		}
+	}
+}
+
+// TestSpecArityAndKeys tests the arity and key positions reported by COMMAND.
+func TestSpecArityAndKeys(t *testing.T) {
+	registry := NewRegistry()
+	RegisterCommands(registry)
+	tests := []struct {
+		command                  string
+		arity, first, last, step int
+	}{
+		{GetCommand, 2, 1, 1, 1},
+		{MSETCommand, -3, 1, -1, 2},
+		{MGetCommand, -2, 1, -1, 1},
+		{ZAddCommand, -5, 1, 1, 1},
+		{KeysCommand, -3, 0, 0, 0},
+		{DBSize, 1, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		reg, err := registry.Retrieve(tt.command)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if arity := reg.Spec.Arity(); arity != tt.arity {
+			t.Errorf("%s: expected arity %d, got %d", tt.command, tt.arity, arity)
+		}
+		first, last, step := reg.Spec.KeyPositions()
+		if first != tt.first || last != tt.last || step != tt.step {
+			t.Errorf("%s: expected keys %d %d %d, got %d %d %d", tt.command, tt.first, tt.last, tt.step, first, last, step)
+		}
+	}
+}
+
+// TestSpecKeyArgs tests the keys and key prefixes ACL rules are checked against.
+func TestSpecKeyArgs(t *testing.T) {
+	registry := NewRegistry()
+	RegisterCommands(registry)
+	tests := []struct {
+		command  string
+		args     []string
+		keys     []string
+		prefixes []string
+		allKeys  bool
+	}{
+		{GetCommand, []string{"k"}, []string{"k"}, nil, false},
+		{MSETCommand, []string{"k1", "v1", "k2", "v2"}, []string{"k1", "k2"}, nil, false},
+		{MGetCommand, []string{"k1", "k2"}, []string{"k1", "k2"}, nil, false},
+		{ZAddCommand, []string{"z", "1", "m", "v"}, []string{"z"}, nil, false},
+		{PrefixScanCommand, []string{"0", "user:", "10"}, nil, []string{"user:"}, false},
+		{DeletePrefixCommand, []string{"user:"}, nil, []string{"user:"}, false},
+		{KeysCommand, []string{"0", ".*"}, nil, nil, true},
+		{FlushAll, nil, nil, nil, true},
+		{DBSize, nil, nil, nil, false},
+	}
+	for _, tt := range tests {
+		reg, err := registry.Retrieve(tt.command)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys, prefixes := reg.Spec.KeyArgs(tt.args)
+		if !slices.Equal(keys, tt.keys) || !slices.Equal(prefixes, tt.prefixes) {
+			t.Errorf("%s: expected keys %v and prefixes %v, got %v and %v", tt.command, tt.keys, tt.prefixes, keys, prefixes)
+		}
+		if reg.Spec.AllKeys != tt.allKeys {
+			t.Errorf("%s: expected all keys %v, got %v", tt.command, tt.allKeys, reg.Spec.AllKeys)
+		}
+	}
+}
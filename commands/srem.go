@@ -9,10 +9,17 @@ const SRemCommand = "SREM"
 
 func RegisterSRemCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     SRemCommand,
-		Validate: validateSAddCommand(),
-		Execute:  executeSRemCommand(),
-		IsWrite:  true,
+		Name: SRemCommand,
+		Spec: Spec{
+			Summary: "Removes members from a set",
+			Group:   GroupSet,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "member", Type: ArgString, Multiple: true},
+			},
+		},
+		Execute: executeSRemCommand(),
 	})
 }
 
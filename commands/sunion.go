@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,22 +9,19 @@ const SUnionCommand = "SUNION"
 
 func RegisterSUnionCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     SUnionCommand,
-		Validate: validateSUnionCommand(),
-		Execute:  executeSUnionCommand(),
+		Name: SUnionCommand,
+		Spec: Spec{
+			Summary: "Returns the union of sets",
+			Group:   GroupSet,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey, Multiple: true},
+			},
+		},
+		Execute: executeSUnionCommand(),
 	})
 }
 
-func validateSUnionCommand() ValidationHook {
-	return func(args []string) error {
-		if len(args) < 1 {
-			return fmt.Errorf("expected minimum 1 argument, got %d", len(args))
-		}
-
-		return nil
-	}
-}
-
 func executeSUnionCommand() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		res, err := store.SUnion(args)
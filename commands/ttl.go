@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,21 +9,19 @@ const TTLCommand = "TTL"
 
 func RegisterTtlCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     TTLCommand,
-		Validate: validateTtlCommand(),
-		Execute:  executeTtlCommand(),
+		Name: TTLCommand,
+		Spec: Spec{
+			Summary: "Returns the seconds left before a key expires, -1 without expiry and -2 when the key is missing",
+			Group:   GroupGeneric,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+			},
+		},
+		Execute: executeTtlCommand(),
 	})
 }
 
-func validateTtlCommand() ValidationHook {
-	return func(args []string) error {
-		if len(args) != 1 {
-			return fmt.Errorf("expected 1 argument, got %d", len(args))
-		}
-		return nil
-	}
-}
-
 func executeTtlCommand() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		key := args[0]
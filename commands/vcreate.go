@@ -9,19 +9,22 @@ const VCreate = "VCREATE"
 
 func RegisterVCreate(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     VCreate,
-		Validate: validateVCreate(),
-		Execute:  executeVCreate(),
-		IsWrite:  true,
+		Name: VCreate,
+		Spec: Spec{
+			Summary: "Creates a vector store",
+			Group:   GroupVector,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "vector", Type: ArgKey},
+				{Name: "maxNeighbor", Type: ArgInteger, Optional: true},
+				{Name: "levelFactor", Type: ArgDouble, Optional: true},
+				{Name: "efSearch", Type: ArgInteger, Optional: true},
+			},
+		},
+		Execute: executeVCreate(),
 	})
 }
 
-func validateVCreate() ValidationHook {
-	return func(args []string) error {
-		return nil
-	}
-}
-
 func executeVCreate() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		err := store.VCreate(args)
@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,22 +9,20 @@ const VDelete = "VDELETE"
 
 func RegisterVDelete(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     VDelete,
-		Validate: validateVDelete(),
-		Execute:  executeVDelete(),
-		IsWrite:  true,
+		Name: VDelete,
+		Spec: Spec{
+			Summary: "Deletes a vector by id from a vector store",
+			Group:   GroupVector,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "vector", Type: ArgKey},
+				{Name: "id", Type: ArgString},
+			},
+		},
+		Execute: executeVDelete(),
 	})
 }
 
-func validateVDelete() ValidationHook {
-	return func(args []string) error {
-		if len(args) < 2 {
-			return fmt.Errorf("expected minimum 2 argument, got %d", len(args))
-		}
-		return nil
-	}
-}
-
 func executeVDelete() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		deleted, err := store.VDelete(args)
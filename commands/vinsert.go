@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,22 +9,20 @@ const VInsert = "VINSERT"
 
 func RegisterVInsert(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     VInsert,
-		Validate: validateVInsert(),
-		Execute:  executeVInsert(),
-		IsWrite:  true,
+		Name: VInsert,
+		Spec: Spec{
+			Summary: "Inserts a vector in a vector store and returns its id",
+			Group:   GroupVector,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "vector", Type: ArgKey},
+				{Name: "value", Type: ArgDouble, Multiple: true},
+			},
+		},
+		Execute: executeVInsert(),
 	})
 }
 
-func validateVInsert() ValidationHook {
-	return func(args []string) error {
-		if len(args) < 2 {
-			return fmt.Errorf("expected minimum 2 argument, got %d", len(args))
-		}
-		return nil
-	}
-}
-
 func executeVInsert() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		id, err := store.VInsert(args)
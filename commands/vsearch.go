@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,22 +9,21 @@ const VSearch = "VSEARCH"
 
 func RegisterVSearch(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     VSearch,
-		Validate: validateVSearch(),
-		Execute:  executeVSearch(),
-		IsWrite:  true,
+		Name: VSearch,
+		Spec: Spec{
+			Summary: "Returns the k nearest neighbours of a vector",
+			Group:   GroupVector,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "vector", Type: ArgKey},
+				{Name: "value", Type: ArgDouble, Multiple: true},
+				{Name: "k", Type: ArgInteger},
+			},
+		},
+		Execute: executeVSearch(),
 	})
 }
 
-func validateVSearch() ValidationHook {
-	return func(args []string) error {
-		if len(args) < 2 {
-			return fmt.Errorf("expected minimum 2 argument, got %d", len(args))
-		}
-		return nil
-	}
-}
-
 func executeVSearch() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		result, err := store.VSearch(args)
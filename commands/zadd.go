@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,22 +9,24 @@ const ZAddCommand = "ZADD"
 
 func RegisterZAddCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     ZAddCommand,
-		Validate: validateZAddCommand(),
-		Execute:  executeZAddCommand(),
-		IsWrite:  true,
+		Name: ZAddCommand,
+		Spec: Spec{
+			Summary: "Adds members with their value and score to a sorted map",
+			Group:   GroupSortedMap,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "data", Type: ArgBlock, Multiple: true, Args: []Arg{
+					{Name: "score", Type: ArgDouble},
+					{Name: "member", Type: ArgString},
+					{Name: "value", Type: ArgString},
+				}},
+			},
+		},
+		Execute: executeZAddCommand(),
 	})
 }
 
-func validateZAddCommand() ValidationHook {
-	return func(args []string) error {
-		if len(args) < 3 {
-			return fmt.Errorf("expected 3 or multiple of 3 arguments, got %d", len(args))
-		}
-		return nil
-	}
-}
-
 func executeZAddCommand() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		err := store.ZAdd(args)
@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,21 +9,19 @@ const ZCardCommand = "ZCARD"
 
 func RegisterZCardCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     ZCardCommand,
-		Validate: validateZCard(),
-		Execute:  executeZCardCommand(),
+		Name: ZCardCommand,
+		Spec: Spec{
+			Summary: "Returns the number of members of a sorted map",
+			Group:   GroupSortedMap,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+			},
+		},
+		Execute: executeZCardCommand(),
 	})
 }
 
-func validateZCard() ValidationHook {
-	return func(args []string) error {
-		if len(args) == 0 {
-			return fmt.Errorf("expected 1 argument, got %d", len(args))
-		}
-		return nil
-	}
-}
-
 func executeZCardCommand() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		size, err := store.ZCard(args[0])
@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"strconv"
+
+	"treds/resp"
+	"treds/store"
+)
+
+const ZIncrByCommand = "ZINCRBY"
+
+func RegisterZIncrByCommand(r CommandRegistry) {
+	r.Add(&CommandRegistration{
+		Name: ZIncrByCommand,
+		Spec: Spec{
+			Summary: "Increments the score of a member of a sorted map",
+			Group:   GroupSortedMap,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "increment", Type: ArgDouble},
+				{Name: "member", Type: ArgString},
+			},
+		},
+		Execute:    executeZIncrByCommand(),
+		ReplyRESP3: doubleReply,
+	})
+}
+
+func executeZIncrByCommand() ExecutionHook {
+	return func(args []string, store store.Store) string {
+		increment, err := strconv.ParseFloat(args[1], 64)
+		if err != nil {
+			return resp.EncodeError(err.Error())
+		}
+		res, err := store.ZIncrBy(args[0], increment, args[2])
+		if err != nil {
+			return resp.EncodeError(err.Error())
+		}
+		return resp.EncodeBulkString(strconv.FormatFloat(res, 'f', -1, 64))
+	}
+}
@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// TestExecuteZIncrBy tests the execution hook of ZINCRBY.
+func TestExecuteZIncrBy(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		expectedMsg   string
+		expectedScore string
+	}{
+		{"missing member", []string{"z", "2.5", "new"}, "$3\r\n2.5\r\n", "2.5"},
+		{"existing member", []string{"z", "-0.5", "a"}, "$3\r\n0.5\r\n", "0.5"},
+		{"infinite", []string{"z", "1e308", "max"}, "-increment would produce NaN or Infinity\r\n", strconv.FormatFloat(1e308, 'f', -1, 64)},
+		{"invalid increment", []string{"z", "one", "a"}, "-strconv.ParseFloat: parsing \"one\": invalid syntax\r\n", "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := &MockStore{data: map[string]string{}}
+			if err := mockStore.ZAdd([]string{"z", "1", "a", "va", "1e308", "max", "vm"}); err != nil {
+				t.Fatal(err)
+			}
+			result := executeZIncrByCommand()(tt.args, mockStore)
+			if result != tt.expectedMsg {
+				t.Errorf("expected result: %q, got: %q", tt.expectedMsg, result)
+			}
+			if score, _ := mockStore.ZScore([]string{"z", tt.args[2]}); score != tt.expectedScore {
+				t.Errorf("expected score: %s, got: %s", tt.expectedScore, score)
+			}
+		})
+	}
+}
+
+// TestExecuteZIncrByMovesMember tests ZINCRBY moves a member to the bucket of its new score, keeping its value,
+// and ZRANGE returns it at its new position.
+func TestExecuteZIncrByMovesMember(t *testing.T) {
+	mockStore := &MockStore{data: map[string]string{}}
+	if err := mockStore.ZAdd([]string{"z", "1", "a", "va", "2", "b", "vb", "3", "c", "vc"}); err != nil {
+		t.Fatal(err)
+	}
+	zrange := executeZRangeCommand(encodeRange)
+
+	// a moves from the first bucket past c
+	if result := executeZIncrByCommand()([]string{"z", "3", "a"}, mockStore); result != "$1\r\n4\r\n" {
+		t.Fatalf("expected result: %q, got: %q", "$1\r\n4\r\n", result)
+	}
+	expected := encodeRange([]string{"2", "b", "vb", "3", "c", "vc", "4", "a", "va"}, true)
+	if result := zrange([]string{"z", "0", "3"}, mockStore); result != expected {
+		t.Errorf("expected result: %q, got: %q", expected, result)
+	}
+
+	// b joins the bucket of c, members with the same score are in lexicographical order
+	executeZIncrByCommand()([]string{"z", "1", "b"}, mockStore)
+	values, err := mockStore.ZRange("z", 0, 3, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(values, []string{"b", "vb", "c", "vc", "a", "va"}) {
+		t.Errorf("expected members b, c, a, got: %v", values)
+	}
+}
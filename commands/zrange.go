@@ -1,7 +1,6 @@
 package commands
 
 import (
-	"fmt"
 	"strconv"
 
 	"treds/resp"
@@ -12,31 +11,24 @@ const ZRangeCommand = "ZRANGE"
 
 func RegisterZRangeCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     ZRangeCommand,
-		Validate: validateZRange(),
-		Execute:  executeZRangeCommand(),
-		IsWrite:  false,
+		Name: ZRangeCommand,
+		Spec: Spec{
+			Summary: "Returns the members of a sorted map between two indexes, the end index is exclusive",
+			Group:   GroupSortedMap,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "start", Type: ArgInteger},
+				{Name: "end", Type: ArgInteger},
+				{Name: "withscore", Type: ArgBoolean, Optional: true},
+			},
+		},
+		Execute:      executeZRangeCommand(encodeRange),
+		ExecuteRESP3: executeZRangeCommand(encodeScoredRange(3)),
 	})
 }
 
-func validateZRange() ValidationHook {
-	return func(args []string) error {
-		if len(args) < 2 {
-			return fmt.Errorf("expected 3 or multiple of 2 arguments, got %d", len(args))
-		}
-		_, err := strconv.Atoi(args[1])
-		if err != nil {
-			return fmt.Errorf("invalid start index: %s", args[0])
-		}
-		_, err = strconv.Atoi(args[2])
-		if err != nil {
-			return fmt.Errorf("invalid end index: %s", args[1])
-		}
-		return nil
-	}
-}
-
-func executeZRangeCommand() ExecutionHook {
+func executeZRangeCommand(encode rangeEncoder) ExecutionHook {
 	return func(args []string, store store.Store) string {
 		startIndex, _ := strconv.Atoi(args[1])
 		endIndex, _ := strconv.Atoi(args[2])
@@ -52,6 +44,6 @@ func executeZRangeCommand() ExecutionHook {
 		if err != nil {
 			return resp.EncodeError(err.Error())
 		}
-		return resp.EncodeStringArray(v)
+		return encode(v, withScore)
 	}
 }
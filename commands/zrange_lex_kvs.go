@@ -1,7 +1,6 @@
 package commands
 
 import (
-	"fmt"
 	"math"
 	"strconv"
 
@@ -13,25 +12,26 @@ const ZRANGELEXKVS = "ZRANGELEXKVS"
 
 func RegisterZRangeLexCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     ZRANGELEXKVS,
-		Validate: validateZRangeLex(),
-		Execute:  executeZRangeLex(),
+		Name: ZRANGELEXKVS,
+		Spec: Spec{
+			Summary: "Returns the members of a sorted map with their values between min and max in lex order",
+			Group:   GroupSortedMap,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "offset", Type: ArgInteger},
+				{Name: "count", Type: ArgInteger, Optional: true},
+				{Name: "withscore", Type: ArgBoolean, Optional: true},
+				{Name: "min", Type: ArgString, Optional: true},
+				{Name: "max", Type: ArgString, Optional: true},
+			},
+		},
+		Execute:      executeZRangeLex(encodeRange),
+		ExecuteRESP3: executeZRangeLex(encodeScoredRange(3)),
 	})
 }
 
-func validateZRangeLex() ValidationHook {
-	return func(args []string) error {
-		if len(args) < 2 {
-			return fmt.Errorf("expected minimum 2 argument, got %d", len(args))
-		}
-		if len(args) > 6 {
-			return fmt.Errorf("expected maximum 3 argument, got %d", len(args))
-		}
-		return nil
-	}
-}
-
-func executeZRangeLex() ExecutionHook {
+func executeZRangeLex(encode rangeEncoder) ExecutionHook {
 	return func(args []string, store store.Store) string {
 		count := strconv.Itoa(math.MaxInt64)
 		if len(args) > 2 {
@@ -53,6 +53,6 @@ func executeZRangeLex() ExecutionHook {
 		if err != nil {
 			return resp.EncodeError(err.Error())
 		}
-		return resp.EncodeStringArray(v)
+		return encode(v, withScore)
 	}
 }
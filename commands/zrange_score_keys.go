@@ -1,7 +1,6 @@
 package commands
 
 import (
-	"fmt"
 	"math"
 	"strconv"
 
@@ -13,32 +12,33 @@ const ZRANGESCOREKEYS = "ZRANGESCOREKEYS"
 
 func RegisterZRangeScoreCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     ZRANGESCOREKEYS,
-		Validate: validateZRangeScore(),
-		Execute:  executeZRangeScoreKeys(),
+		Name: ZRANGESCOREKEYS,
+		Spec: Spec{
+			Summary: "Returns the members of a sorted map with a score between min and max in score order",
+			Group:   GroupSortedMap,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "min", Type: ArgDouble},
+				{Name: "max", Type: ArgDouble},
+				{Name: "offset", Type: ArgInteger, Optional: true},
+				{Name: "count", Type: ArgInteger, Optional: true},
+				{Name: "withscore", Type: ArgBoolean, Optional: true},
+			},
+		},
+		Execute:      executeZRangeScoreKeys(encodeRange),
+		ExecuteRESP3: executeZRangeScoreKeys(encodeScoredRange(2)),
 	})
 }
 
-func validateZRangeScore() ValidationHook {
-	return func(args []string) error {
-		if len(args) < 3 {
-			return fmt.Errorf("expected minimum 3 argument, got %d", len(args))
-		}
-		if len(args) > 6 {
-			return fmt.Errorf("expected maximum 6 argument, got %d", len(args))
-		}
-		return nil
-	}
-}
-
-func executeZRangeScoreKeys() ExecutionHook {
+func executeZRangeScoreKeys(encode rangeEncoder) ExecutionHook {
 	return func(args []string, store store.Store) string {
 		startIndex := strconv.Itoa(0)
-		if len(args) > 4 {
+		if len(args) > 3 {
 			startIndex = args[3]
 		}
 		count := strconv.Itoa(math.MaxInt64)
-		if len(args) > 5 {
+		if len(args) > 4 {
 			count = args[4]
 		}
 		withScore := true
@@ -53,6 +53,6 @@ func executeZRangeScoreKeys() ExecutionHook {
 		if err != nil {
 			return resp.EncodeError(err.Error())
 		}
-		return resp.EncodeStringArray(v)
+		return encode(v, withScore)
 	}
 }
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+// rangeStore records the offset, count and withscore arguments of the score range calls
+type rangeStore struct {
+	MockStore
+	offset, count string
+	withScore     bool
+}
+
+func (m *rangeStore) record(offset, count string, withScore bool) ([]string, error) {
+	m.offset, m.count, m.withScore = offset, count, withScore
+	return nil, nil
+}
+
+func (m *rangeStore) ZRangeByScoreKeys(_, _, _, offset, count string, withScore bool) ([]string, error) {
+	return m.record(offset, count, withScore)
+}
+
+func (m *rangeStore) ZRangeByScoreKVS(_, _, _, offset, count string, withScore bool) ([]string, error) {
+	return m.record(offset, count, withScore)
+}
+
+func (m *rangeStore) ZRevRangeByScoreKeys(_, _, _, offset, count string, withScore bool) ([]string, error) {
+	return m.record(offset, count, withScore)
+}
+
+// TestExecuteZRangeScoreOffsetCount tests the optional offset and count are read from the 4th and 5th arguments,
+// an offset given alone used to be ignored and the count was read from the withscore position.
+func TestExecuteZRangeScoreOffsetCount(t *testing.T) {
+	maxCount := strconv.Itoa(math.MaxInt64)
+	tests := []struct {
+		name          string
+		args          []string
+		wantOffset    string
+		wantCount     string
+		wantWithScore bool
+	}{
+		{"no offset", []string{"z", "0", "10"}, "0", maxCount, true},
+		{"offset only", []string{"z", "0", "10", "2"}, "2", maxCount, true},
+		{"offset and count", []string{"z", "0", "10", "2", "3"}, "2", "3", true},
+		{"withscore", []string{"z", "0", "10", "2", "3", "false"}, "2", "3", false},
+	}
+
+	for _, command := range []struct {
+		name     string
+		register func(CommandRegistry)
+	}{
+		{ZRANGESCOREKEYS, RegisterZRangeScoreCommand},
+		{ZRANGESCOREKVS, RegisterZRangeScoreKVSCommand},
+		{ZREVRANGESCOREKEYS, RegisterZRevRangeScoreCommand},
+	} {
+		for _, tt := range tests {
+			t.Run(command.name+" "+tt.name, func(t *testing.T) {
+				reg := registeredCommand(t, command.register, command.name)
+				if err := reg.Validate(tt.args); err != nil {
+					t.Fatalf("unexpected validation error: %v", err)
+				}
+				mockStore := &rangeStore{}
+				reg.Execute(tt.args, mockStore)
+				if mockStore.offset != tt.wantOffset || mockStore.count != tt.wantCount || mockStore.withScore != tt.wantWithScore {
+					t.Errorf("expected offset %s, count %s, withscore %v, got %s, %s, %v", tt.wantOffset, tt.wantCount,
+						tt.wantWithScore, mockStore.offset, mockStore.count, mockStore.withScore)
+				}
+			})
+		}
+	}
+}
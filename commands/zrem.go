@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"treds/resp"
 	"treds/store"
 )
@@ -11,22 +9,20 @@ const ZRemCommand = "ZREM"
 
 func RegisterZRemCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     ZRemCommand,
-		Validate: validateZRem(),
-		Execute:  executeZRemCommand(),
-		IsWrite:  true,
+		Name: ZRemCommand,
+		Spec: Spec{
+			Summary: "Removes members from a sorted map",
+			Group:   GroupSortedMap,
+			Flags:   []string{FlagWrite},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "member", Type: ArgString, Multiple: true},
+			},
+		},
+		Execute: executeZRemCommand(),
 	})
 }
 
-func validateZRem() ValidationHook {
-	return func(args []string) error {
-		if len(args) < 2 {
-			return fmt.Errorf("expected 3 or multiple of 2 arguments, got %d", len(args))
-		}
-		return nil
-	}
-}
-
 func executeZRemCommand() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		err := store.ZRem(args)
@@ -12,13 +12,26 @@ const ZREVRANGELEXKEYS = "ZREVRANGELEXKEYS"
 
 func RegisterZRevRangeLexKeysCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     ZREVRANGELEXKEYS,
-		Validate: validateZRangeLex(),
-		Execute:  executeZRevRangeLexKeys(),
+		Name: ZREVRANGELEXKEYS,
+		Spec: Spec{
+			Summary: "Returns the members of a sorted map between min and max in reverse lex order",
+			Group:   GroupSortedMap,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "offset", Type: ArgInteger},
+				{Name: "count", Type: ArgInteger, Optional: true},
+				{Name: "withscore", Type: ArgBoolean, Optional: true},
+				{Name: "min", Type: ArgString, Optional: true},
+				{Name: "max", Type: ArgString, Optional: true},
+			},
+		},
+		Execute:      executeZRevRangeLexKeys(encodeRange),
+		ExecuteRESP3: executeZRevRangeLexKeys(encodeScoredRange(2)),
 	})
 }
 
-func executeZRevRangeLexKeys() ExecutionHook {
+func executeZRevRangeLexKeys(encode rangeEncoder) ExecutionHook {
 	return func(args []string, store store.Store) string {
 		count := strconv.Itoa(math.MaxInt64)
 		if len(args) > 2 {
@@ -40,6 +53,6 @@ func executeZRevRangeLexKeys() ExecutionHook {
 		if err != nil {
 			return resp.EncodeError(err.Error())
 		}
-		return resp.EncodeStringArray(v)
+		return encode(v, withScore)
 	}
 }
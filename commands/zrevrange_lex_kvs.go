@@ -12,13 +12,26 @@ const ZREVRANGELEXKVS = "ZREVRANGELEXKVS"
 
 func RegisterZRevRangeLexKVSCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     ZREVRANGELEXKVS,
-		Validate: validateZRangeLex(),
-		Execute:  executeZRevRangeLexKVS(),
+		Name: ZREVRANGELEXKVS,
+		Spec: Spec{
+			Summary: "Returns the members of a sorted map with their values between min and max in reverse lex order",
+			Group:   GroupSortedMap,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "offset", Type: ArgInteger},
+				{Name: "count", Type: ArgInteger, Optional: true},
+				{Name: "withscore", Type: ArgBoolean, Optional: true},
+				{Name: "min", Type: ArgString, Optional: true},
+				{Name: "max", Type: ArgString, Optional: true},
+			},
+		},
+		Execute:      executeZRevRangeLexKVS(encodeRange),
+		ExecuteRESP3: executeZRevRangeLexKVS(encodeScoredRange(3)),
 	})
 }
 
-func executeZRevRangeLexKVS() ExecutionHook {
+func executeZRevRangeLexKVS(encode rangeEncoder) ExecutionHook {
 	return func(args []string, store store.Store) string {
 		count := strconv.Itoa(math.MaxInt64)
 		if len(args) > 2 {
@@ -40,6 +53,6 @@ func executeZRevRangeLexKVS() ExecutionHook {
 		if err != nil {
 			return resp.EncodeError(err.Error())
 		}
-		return resp.EncodeStringArray(v)
+		return encode(v, withScore)
 	}
 }
@@ -12,20 +12,33 @@ const ZREVRANGESCOREKEYS = "ZREVRANGESCOREKEYS"
 
 func RegisterZRevRangeScoreCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     ZREVRANGESCOREKEYS,
-		Validate: validateZRangeScore(),
-		Execute:  executeZRevRangeScoreKeys(),
+		Name: ZREVRANGESCOREKEYS,
+		Spec: Spec{
+			Summary: "Returns the members of a sorted map with a score between min and max in reverse score order",
+			Group:   GroupSortedMap,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "min", Type: ArgDouble},
+				{Name: "max", Type: ArgDouble},
+				{Name: "offset", Type: ArgInteger, Optional: true},
+				{Name: "count", Type: ArgInteger, Optional: true},
+				{Name: "withscore", Type: ArgBoolean, Optional: true},
+			},
+		},
+		Execute:      executeZRevRangeScoreKeys(encodeRange),
+		ExecuteRESP3: executeZRevRangeScoreKeys(encodeScoredRange(2)),
 	})
 }
 
-func executeZRevRangeScoreKeys() ExecutionHook {
+func executeZRevRangeScoreKeys(encode rangeEncoder) ExecutionHook {
 	return func(args []string, store store.Store) string {
 		startIndex := strconv.Itoa(0)
-		if len(args) > 4 {
+		if len(args) > 3 {
 			startIndex = args[3]
 		}
 		count := strconv.Itoa(math.MaxInt64)
-		if len(args) > 5 {
+		if len(args) > 4 {
 			count = args[4]
 		}
 		withScore := true
@@ -40,6 +53,6 @@ func executeZRevRangeScoreKeys() ExecutionHook {
 		if err != nil {
 			return resp.EncodeError(err.Error())
 		}
-		return resp.EncodeStringArray(v)
+		return encode(v, withScore)
 	}
 }
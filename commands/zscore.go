@@ -1,7 +1,7 @@
 package commands
 
 import (
-	"fmt"
+	"strconv"
 
 	"treds/resp"
 	"treds/store"
@@ -11,27 +11,44 @@ const ZScoreCommand = "ZSCORE"
 
 func RegisterZScoreCommand(r CommandRegistry) {
 	r.Add(&CommandRegistration{
-		Name:     ZScoreCommand,
-		Validate: validateZScore(),
-		Execute:  executeZScoreCommand(),
+		Name: ZScoreCommand,
+		Spec: Spec{
+			Summary: "Returns the score of a member of a sorted map",
+			Group:   GroupSortedMap,
+			Flags:   []string{FlagReadonly},
+			Args: []Arg{
+				{Name: "key", Type: ArgKey},
+				{Name: "member", Type: ArgString},
+			},
+		},
+		Execute:      executeZScoreCommand(),
+		ExecuteRESP3: executeZScoreCommandRESP3(),
 	})
 }
 
-func validateZScore() ValidationHook {
-	return func(args []string) error {
-		if len(args) != 2 {
-			return fmt.Errorf("expected  2 arguments, got %d", len(args))
+func executeZScoreCommand() ExecutionHook {
+	return func(args []string, store store.Store) string {
+		res, err := store.ZScore(args)
+		if err != nil {
+			return resp.EncodeError(err.Error())
 		}
-		return nil
+		return resp.EncodeBulkString(res)
 	}
 }
 
-func executeZScoreCommand() ExecutionHook {
+func executeZScoreCommandRESP3() ExecutionHook {
 	return func(args []string, store store.Store) string {
 		res, err := store.ZScore(args)
 		if err != nil {
 			return resp.EncodeError(err.Error())
 		}
-		return resp.EncodeBulkString(res)
+		if res == "" {
+			return resp.EncodeNull()
+		}
+		score, err := strconv.ParseFloat(res, 64)
+		if err != nil {
+			return resp.EncodeError(err.Error())
+		}
+		return resp.EncodeDouble(score)
 	}
 }
@@ -0,0 +1,328 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"gopkg.in/yaml.v3"
+	"treds/store"
+)
+
+// DefaultMaxClients is the number of connections a node accepts unless configured otherwise
+const DefaultMaxClients = 10000
+
+// Config holds every setting of a node. Each field is a parameter named by its yaml tag, which is the key
+// in the config file and the name used by CONFIG GET and CONFIG SET. Fields with a flag tag can be given
+// on the command line, fields tagged live can be changed with CONFIG SET while the node runs and the values of
+// fields tagged secret are not shown by CONFIG GET.
+type Config struct {
+	ID             string `yaml:"id" flag:"id" usage:"Server Id - must be a uuid, if not given a new one will be generated"`
+	Port           int    `yaml:"port" flag:"port" usage:"Port at which server will listen"`
+	Bind           string `yaml:"bind" flag:"bind" usage:"Bind Address"`
+	Advertise      string `yaml:"advertise" flag:"advertise" usage:"Advertise Address"`
+	Listen         string `yaml:"listen" flag:"listen" usage:"Comma-separated list of listen addresses, tcp://host:port or unix:///path/to/socket (default tcp://<bind>:<port>)"`
+	UnixSocketPerm string `yaml:"unix-socket-perm" flag:"unixSocketPerm" usage:"Permissions of the unix sockets, in octal"`
+	Servers        string `yaml:"servers" flag:"servers" usage:"Comma-separated list of servers in the format id:host:port (e.g., 'uuid1:127.0.0.1:8080,uuid2:192.168.1.1:9090')"`
+	EventLoops     int    `yaml:"event-loops" flag:"eventLoops" usage:"Number of event loops, the store is split in as many shards"`
+	ShardBy        string `yaml:"shard-by" flag:"shardBy" usage:"How keys are partitioned between shards, 'hash' of the key or 'prefix' for the top level prefix"`
+	ShardDelimiter string `yaml:"shard-delimiter" flag:"shardDelimiter" usage:"Delimiter ending the top level prefix when sharding by prefix"`
+
+	LogLevel  string `yaml:"log-level" flag:"logLevel" live:"true" usage:"Least level logged, trace, debug, info, warn or error"`
+	LogFormat string `yaml:"log-format" flag:"logFormat" usage:"Format of the log lines, logfmt or json"`
+
+	DataDir        string `yaml:"data-dir" flag:"dataDir" usage:"Directory holding the Raft WAL and snapshots"`
+	SegmentSize    int    `yaml:"segment-size" flag:"segmentSize" usage:"Segment size"`
+	SnapshotRetain int    `yaml:"snapshot-retain" flag:"snapshotRetain" usage:"Number of Raft snapshots kept"`
+
+	TLSCert        string `yaml:"tls-cert" flag:"tlsCert" usage:"TLS certificate file, enables TLS for the client port and Raft together with tlsKey"`
+	TLSKey         string `yaml:"tls-key" flag:"tlsKey" usage:"TLS private key file"`
+	TLSCA          string `yaml:"tls-ca" flag:"tlsCA" usage:"CA file used to verify peer certificates, Raft peers must present a certificate signed by it"`
+	TLSAuthClients bool   `yaml:"tls-auth-clients" flag:"tlsAuthClients" usage:"Require clients to present a certificate signed by tlsCA (mutual TLS)"`
+
+	MaxClients                    int    `yaml:"maxclients" flag:"maxClients" live:"true" usage:"Maximum number of connections accepted, 0 for no limit"`
+	ClientOutputBufferLimitNormal string `yaml:"client-output-buffer-limit-normal" flag:"clientOutputBufferLimitNormal" live:"true" usage:"Output buffer limit of normal clients, '<hard> <soft> <soft seconds>', 0 disables a threshold"`
+	ClientOutputBufferLimitPubSub string `yaml:"client-output-buffer-limit-pubsub" flag:"clientOutputBufferLimitPubSub" live:"true" usage:"Output buffer limit of clients subscribed to a channel, '<hard> <soft> <soft seconds>'"`
+
+	SlowlogLogSlowerThan int `yaml:"slowlog-log-slower-than" flag:"slowlogLogSlowerThan" live:"true" usage:"Commands running longer, in microseconds, are recorded in the slow log, 0 records every command and a negative value none"`
+	SlowlogMaxLen        int `yaml:"slowlog-max-len" flag:"slowlogMaxLen" live:"true" usage:"Number of commands the slow log keeps"`
+
+	HTTPAddr      string `yaml:"http-addr" flag:"httpAddr" usage:"Address of the HTTP/JSON gateway, host:port, disabled when empty"`
+	GRPCAddr      string `yaml:"grpc-addr" flag:"grpcAddr" usage:"Address of the gRPC service, host:port, disabled when empty"`
+	MetricsAddr   string `yaml:"metrics-addr" flag:"metricsAddr" usage:"Address serving the Prometheus metrics at /metrics, host:port, disabled when empty"`
+	ClusterSecret string `yaml:"cluster-secret" flag:"clusterSecret" secret:"true" usage:"Secret shared by the nodes, authenticates the requests they forward to the leader when ACL users are used"`
+
+	ConnPoolTimeout     time.Duration `yaml:"conn-pool-timeout" flag:"connPoolTimeout" usage:"Timeout of the connections forwarding requests to the leader"`
+	ExpirySweepInterval time.Duration `yaml:"expiry-sweep-interval" flag:"expirySweepInterval" live:"true" usage:"How often expired keys are removed"`
+	DrainTimeout        time.Duration `yaml:"drain-timeout" flag:"drainTimeout" usage:"How long a shutdown waits for in-flight commands before closing the connections"`
+
+	RaftPort               int           `yaml:"raft-port" flag:"raftPort" usage:"Port used by Raft for replication"`
+	RaftApplyTimeout       time.Duration `yaml:"raft-apply-timeout" flag:"raftApplyTimeout" live:"true" usage:"Raft Apply Timeout"`
+	RaftHeartbeatTimeout   time.Duration `yaml:"raft-heartbeat-timeout" live:"true"`
+	RaftElectionTimeout    time.Duration `yaml:"raft-election-timeout" live:"true"`
+	RaftCommitTimeout      time.Duration `yaml:"raft-commit-timeout"`
+	RaftLeaderLeaseTimeout time.Duration `yaml:"raft-leader-lease-timeout"`
+	RaftMaxAppendEntries   int           `yaml:"raft-max-append-entries"`
+	RaftSnapshotInterval   time.Duration `yaml:"raft-snapshot-interval" live:"true"`
+	RaftSnapshotThreshold  uint64        `yaml:"raft-snapshot-threshold" live:"true"`
+	RaftTrailingLogs       uint64        `yaml:"raft-trailing-logs" live:"true"`
+}
+
+// Default returns the settings used when neither the config file nor a flag sets them
+func Default() *Config {
+	raftConfig := raft.DefaultConfig()
+	return &Config{
+		Port:                          7997,
+		Bind:                          "localhost",
+		Advertise:                     "localhost",
+		UnixSocketPerm:                "700",
+		EventLoops:                    1,
+		ShardBy:                       "hash",
+		ShardDelimiter:                store.DefaultShardDelimiter,
+		LogLevel:                      "info",
+		LogFormat:                     "logfmt",
+		DataDir:                       "data",
+		SegmentSize:                   200,
+		SnapshotRetain:                3,
+		MaxClients:                    DefaultMaxClients,
+		ClientOutputBufferLimitNormal: "0 0 0",
+		ClientOutputBufferLimitPubSub: "32mb 8mb 60",
+		SlowlogLogSlowerThan:          10000,
+		SlowlogMaxLen:                 128,
+		ConnPoolTimeout:               5 * time.Second,
+		ExpirySweepInterval:           100 * time.Millisecond,
+		DrainTimeout:                  10 * time.Second,
+		RaftPort:                      8300,
+		RaftApplyTimeout:              time.Second,
+		RaftHeartbeatTimeout:          raftConfig.HeartbeatTimeout,
+		RaftElectionTimeout:           raftConfig.ElectionTimeout,
+		RaftCommitTimeout:             raftConfig.CommitTimeout,
+		RaftLeaderLeaseTimeout:        raftConfig.LeaderLeaseTimeout,
+		RaftMaxAppendEntries:          raftConfig.MaxAppendEntries,
+		RaftSnapshotInterval:          raftConfig.SnapshotInterval,
+		RaftSnapshotThreshold:         raftConfig.SnapshotThreshold,
+		RaftTrailingLogs:              raftConfig.TrailingLogs,
+	}
+}
+
+// Load reads the config file at path, parameters missing from the file keep their value in base
+func Load(path string, base *Config) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := *base
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	// Unknown keys are refused, a misspelled parameter would otherwise be silently ignored
+	decoder.KnownFields(true)
+	if err = decoder.Decode(&c); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+	return &c, nil
+}
+
+// BindFlags defines a flag on fs for every parameter with a flag tag, using the values of c as defaults
+func BindFlags(fs *flag.FlagSet, c *Config) {
+	v := reflect.ValueOf(c).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		name := field.Tag.Get("flag")
+		if name == "" {
+			continue
+		}
+		usage := field.Tag.Get("usage")
+		switch ptr := v.Field(i).Addr().Interface().(type) {
+		case *string:
+			fs.StringVar(ptr, name, *ptr, usage)
+		case *int:
+			fs.IntVar(ptr, name, *ptr, usage)
+		case *bool:
+			fs.BoolVar(ptr, name, *ptr, usage)
+		case *uint64:
+			fs.Uint64Var(ptr, name, *ptr, usage)
+		case *time.Duration:
+			fs.DurationVar(ptr, name, *ptr, usage)
+		}
+	}
+}
+
+// Names returns the names of every parameter, sorted
+func Names() []string {
+	t := reflect.TypeOf(Config{})
+	names := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		names = append(names, t.Field(i).Tag.Get("yaml"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Match returns the names of the parameters matching the glob pattern, sorted
+func Match(pattern string) []string {
+	var names []string
+	for _, name := range Names() {
+		if ok, _ := path.Match(strings.ToLower(pattern), name); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// IsLive tells if the parameter can be changed while the node runs
+func IsLive(name string) bool {
+	field, ok := fieldByName(strings.ToLower(name))
+	return ok && field.Tag.Get("live") == "true"
+}
+
+// IsSecret tells if the value of the parameter is kept from CONFIG GET
+func IsSecret(name string) bool {
+	field, ok := fieldByName(strings.ToLower(name))
+	return ok && field.Tag.Get("secret") == "true"
+}
+
+// Get returns the value of the parameter formatted as in the config file
+func (c *Config) Get(name string) (string, bool) {
+	field, ok := fieldByName(strings.ToLower(name))
+	if !ok {
+		return "", false
+	}
+	switch value := reflect.ValueOf(c).Elem().FieldByIndex(field.Index).Interface().(type) {
+	case time.Duration:
+		return value.String(), true
+	default:
+		return fmt.Sprint(value), true
+	}
+}
+
+// Set parses value into the parameter
+func (c *Config) Set(name, value string) error {
+	field, ok := fieldByName(strings.ToLower(name))
+	if !ok {
+		return fmt.Errorf("unknown parameter '%s'", name)
+	}
+	var err error
+	switch ptr := reflect.ValueOf(c).Elem().FieldByIndex(field.Index).Addr().Interface().(type) {
+	case *string:
+		*ptr = value
+	case *int:
+		*ptr, err = strconv.Atoi(value)
+	case *bool:
+		*ptr, err = parseBool(value)
+	case *uint64:
+		*ptr, err = strconv.ParseUint(value, 10, 64)
+	case *time.Duration:
+		*ptr, err = time.ParseDuration(value)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value '%s' for parameter '%s'", value, name)
+	}
+	return nil
+}
+
+// Rewrite writes c to the config file at path. Comments and the order of the parameters already in the
+// file are kept, parameters differing from their default are appended.
+func Rewrite(path string, c *Config) error {
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err = yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	mapping := doc.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return fmt.Errorf("invalid config file %s: not a mapping", path)
+	}
+
+	written := make(map[string]bool)
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		name := mapping.Content[i].Value
+		if err = setNode(mapping.Content[i+1], c, name); err != nil {
+			return err
+		}
+		written[name] = true
+	}
+	defaults := Default()
+	for _, name := range Names() {
+		value, _ := c.Get(name)
+		if defaultValue, _ := defaults.Get(name); written[name] || value == defaultValue {
+			continue
+		}
+		valueNode := &yaml.Node{}
+		if err = setNode(valueNode, c, name); err != nil {
+			return err
+		}
+		mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, valueNode)
+	}
+
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		return err
+	}
+	// The file is replaced at once, a crash while writing leaves the previous file in place
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(out); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// setNode sets node to the value of the parameter name of c
+func setNode(node *yaml.Node, c *Config, name string) error {
+	field, ok := fieldByName(name)
+	if !ok {
+		return fmt.Errorf("unknown parameter '%s'", name)
+	}
+	var encoded yaml.Node
+	if err := encoded.Encode(reflect.ValueOf(c).Elem().FieldByIndex(field.Index).Interface()); err != nil {
+		return err
+	}
+	// Comments of the parameter are kept, only its value changes
+	encoded.HeadComment, encoded.LineComment, encoded.FootComment = node.HeadComment, node.LineComment, node.FootComment
+	*node = encoded
+	return nil
+}
+
+func fieldByName(name string) (reflect.StructField, bool) {
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("yaml") == name {
+			return t.Field(i), true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// parseBool accepts yes and no like Redis besides the values of strconv.ParseBool
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "yes":
+		return true, nil
+	case "no":
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
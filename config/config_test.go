@@ -0,0 +1,80 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "treds.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("port: 8000\nraft-apply-timeout: 2s\nraft-trailing-logs: 100\n"), 0600))
+
+	c, err := Load(path, Default())
+	require.NoError(t, err)
+	require.Equal(t, 8000, c.Port)
+	require.Equal(t, 2*time.Second, c.RaftApplyTimeout)
+	require.Equal(t, uint64(100), c.RaftTrailingLogs)
+	require.Equal(t, "localhost", c.Bind)
+
+	require.NoError(t, os.WriteFile(path, []byte("prot: 8000\n"), 0600))
+	_, err = Load(path, Default())
+	require.Error(t, err)
+}
+
+func TestBindFlags(t *testing.T) {
+	c := Default()
+	fs := flag.NewFlagSet("treds", flag.ContinueOnError)
+	BindFlags(fs, c)
+	require.NoError(t, fs.Parse([]string{"-port", "9000", "-raftApplyTimeout", "3s", "-tlsAuthClients"}))
+	require.Equal(t, 9000, c.Port)
+	require.Equal(t, 3*time.Second, c.RaftApplyTimeout)
+	require.True(t, c.TLSAuthClients)
+}
+
+func TestGetSet(t *testing.T) {
+	c := Default()
+	require.Equal(t, []string{"maxclients"}, Match("maxclients"))
+	require.Contains(t, Match("client-output-*"), "client-output-buffer-limit-pubsub")
+	require.True(t, IsLive("MAXCLIENTS"))
+	require.False(t, IsLive("port"))
+	require.True(t, IsSecret("cluster-secret"))
+	require.False(t, IsSecret("maxclients"))
+
+	require.NoError(t, c.Set("maxclients", "10"))
+	require.NoError(t, c.Set("expiry-sweep-interval", "1s"))
+	require.NoError(t, c.Set("tls-auth-clients", "yes"))
+	value, ok := c.Get("expiry-sweep-interval")
+	require.True(t, ok)
+	require.Equal(t, "1s", value)
+	value, _ = c.Get("maxclients")
+	require.Equal(t, "10", value)
+
+	require.Error(t, c.Set("maxclients", "ten"))
+	require.Error(t, c.Set("unknown", "1"))
+	_, ok = c.Get("unknown")
+	require.False(t, ok)
+}
+
+func TestRewrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "treds.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("# Client port\nport: 8000 # public\n"), 0600))
+
+	c, err := Load(path, Default())
+	require.NoError(t, err)
+	c.Port = 8001
+	c.RaftApplyTimeout = 5 * time.Second
+	require.NoError(t, Rewrite(path, c))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "# Client port\nport: 8001 # public\nraft-apply-timeout: 5s\n", string(data))
+
+	loaded, err := Load(path, Default())
+	require.NoError(t, err)
+	require.Equal(t, c, loaded)
+}
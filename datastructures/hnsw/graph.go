@@ -11,8 +11,11 @@ import (
 	"github.com/absolutelightning/gods/utils"
 	"github.com/google/uuid"
 	"golang.org/x/exp/maps"
+	"treds/logging"
 )
 
+var logger = logging.Named("hnsw")
+
 type DistanceFunc func(a, b Vector) float64
 
 // HNSW represents the entire hierarchical graph.
@@ -249,7 +252,7 @@ func (h *HNSW) searchLayer(entryPoint *Node, target Vector, ef int, layer int) [
 
 		currentNode := h.Layers[layer].Nodes[current.NodeID]
 		if currentNode == nil {
-			fmt.Printf("Node %s not found in layer %d\n", current.NodeID, layer)
+			logger.Warn("Node not found in layer", "node", current.NodeID, "layer", layer)
 			continue
 		}
 
@@ -0,0 +1,227 @@
+package embed
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Set sets the value of key
+func (db *DB) Set(key string, value string) error {
+	_, err := db.Do("SET", key, value)
+	return err
+}
+
+// Get returns the value of key, ErrNil when it does not exist
+func (db *DB) Get(key string) (string, error) {
+	reply, err := db.Do("GET", key)
+	if err != nil {
+		return "", err
+	}
+	return toString(reply)
+}
+
+// IncrBy adds delta to the integer value of key, a missing key counts as 0, and returns the new value
+func (db *DB) IncrBy(key string, delta int64) (int64, error) {
+	reply, err := db.Do("INCRBY", key, strconv.FormatInt(delta, 10))
+	if err != nil {
+		return 0, err
+	}
+	return toInt(reply)
+}
+
+// IncrByFloat adds delta to the float value of key, a missing key counts as 0, and returns the new value
+func (db *DB) IncrByFloat(key string, delta float64) (float64, error) {
+	reply, err := db.Do("INCRBYFLOAT", key, formatFloat(delta))
+	if err != nil {
+		return 0, err
+	}
+	return toFloat(reply)
+}
+
+// Del deletes key
+func (db *DB) Del(key string) error {
+	_, err := db.Do("DEL", key)
+	return err
+}
+
+// Expire expires key after seconds
+func (db *DB) Expire(key string, seconds int) error {
+	_, err := db.Do("EXPIRE", key, strconv.Itoa(seconds))
+	return err
+}
+
+// PrefixScan returns up to count key value pairs having prefix in key order, starting at cursor, and the
+// cursor of the next page. The first page is at cursor "0" and the next cursor is "0" once the scan is done.
+func (db *DB) PrefixScan(prefix string, cursor string, count int) ([]KV, string, error) {
+	reply, err := db.Do("SCANKVS", cursor, prefix, strconv.Itoa(count))
+	if err != nil {
+		return nil, "", err
+	}
+	strs, err := toStrings(reply)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(strs) == 0 {
+		return nil, "", fmt.Errorf("treds: scan reply without cursor")
+	}
+	page, err := toKVs(strs[:len(strs)-1])
+	return page, strs[len(strs)-1], err
+}
+
+// LongestPrefix returns the key which is the longest prefix of s and its value, ErrNil when there is none
+func (db *DB) LongestPrefix(s string) (KV, error) {
+	reply, err := db.Do("LNGPREFIX", s)
+	if err != nil {
+		return KV{}, err
+	}
+	strs, err := toStrings(reply)
+	if err != nil {
+		return KV{}, err
+	}
+	if len(strs) == 0 {
+		return KV{}, ErrNil
+	}
+	kvs, err := toKVs(strs)
+	if err != nil {
+		return KV{}, err
+	}
+	return kvs[0], nil
+}
+
+// ZAdd adds members to the sorted map key, members already in it are updated
+func (db *DB) ZAdd(key string, members ...ScoredKV) error {
+	args := []string{"ZADD", key}
+	for _, member := range members {
+		args = append(args, formatFloat(member.Score), member.Key, member.Value)
+	}
+	_, err := db.Do(args...)
+	return err
+}
+
+// ZScore returns the score of member in the sorted map key, ErrNil when it is not a member
+func (db *DB) ZScore(key string, member string) (float64, error) {
+	reply, err := db.Do("ZSCORE", key, member)
+	if err != nil {
+		return 0, err
+	}
+	return toFloat(reply)
+}
+
+// ZIncrBy adds delta to the score of member in the sorted map key and returns the new score,
+// a missing member is added with an empty value
+func (db *DB) ZIncrBy(key string, delta float64, member string) (float64, error) {
+	reply, err := db.Do("ZINCRBY", key, formatFloat(delta), member)
+	if err != nil {
+		return 0, err
+	}
+	return toFloat(reply)
+}
+
+// ZRangeByScore returns up to count members of the sorted map key with a score between min and max,
+// lowest score first, skipping the first offset members
+func (db *DB) ZRangeByScore(key string, min, max float64, offset, count int) ([]ScoredKV, error) {
+	reply, err := db.Do("ZRANGESCOREKVS", key, formatFloat(min), formatFloat(max), strconv.Itoa(offset), strconv.Itoa(count), "true")
+	if err != nil {
+		return nil, err
+	}
+	return toScoredKVs(reply)
+}
+
+// DCreate creates the collection name. schema and indexes are JSON, empty to leave them out.
+func (db *DB) DCreate(name string, schema string, indexes string) error {
+	args := []string{"DCREATE", name}
+	if schema != "" || indexes != "" {
+		args = append(args, schema)
+	}
+	if indexes != "" {
+		args = append(args, indexes)
+	}
+	_, err := db.Do(args...)
+	return err
+}
+
+// DInsert inserts document in the collection name and returns its id.
+// A string or json.RawMessage is stored as is, any other value is marshalled to JSON.
+func (db *DB) DInsert(name string, document interface{}) (string, error) {
+	doc, err := marshalJSON(document)
+	if err != nil {
+		return "", err
+	}
+	reply, err := db.Do("DINSERT", name, doc)
+	if err != nil {
+		return "", err
+	}
+	return toString(reply)
+}
+
+// DQuery returns the documents of the collection name matching query, which is marshalled like a DInsert document
+func (db *DB) DQuery(name string, query interface{}) ([]json.RawMessage, error) {
+	q, err := marshalJSON(query)
+	if err != nil {
+		return nil, err
+	}
+	reply, err := db.Do("DQUERY", name, q)
+	if err != nil {
+		return nil, err
+	}
+	strs, err := toStrings(reply)
+	if err != nil {
+		return nil, err
+	}
+	documents := make([]json.RawMessage, 0, len(strs))
+	for _, s := range strs {
+		documents = append(documents, json.RawMessage(s))
+	}
+	return documents, nil
+}
+
+// VCreate creates the vector index name
+func (db *DB) VCreate(name string, opts VectorOptions) error {
+	args := []string{"VCREATE", name}
+	if opts != (VectorOptions{}) {
+		args = append(args, strconv.Itoa(opts.MaxNeighbors), formatFloat(opts.LevelFactor), strconv.Itoa(opts.EfSearch))
+	}
+	_, err := db.Do(args...)
+	return err
+}
+
+// VInsert inserts vector in the index name and returns its id
+func (db *DB) VInsert(name string, vector []float64) (string, error) {
+	args := []string{"VINSERT", name}
+	for _, component := range vector {
+		args = append(args, formatFloat(component))
+	}
+	reply, err := db.Do(args...)
+	if err != nil {
+		return "", err
+	}
+	return toString(reply)
+}
+
+// VSearch returns up to k vectors of the index name near vector
+func (db *DB) VSearch(name string, vector []float64, k int) ([]VectorMatch, error) {
+	args := []string{"VSEARCH", name}
+	for _, component := range vector {
+		args = append(args, formatFloat(component))
+	}
+	reply, err := db.Do(append(args, strconv.Itoa(k))...)
+	if err != nil {
+		return nil, err
+	}
+	return toVectorMatches(reply)
+}
+
+func marshalJSON(v interface{}) (string, error) {
+	switch doc := v.(type) {
+	case string:
+		return doc, nil
+	case json.RawMessage:
+		return string(doc), nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,250 @@
+// Package embed runs Treds in process, without gnet or Raft. A DB runs the store commands of the server on a
+// store.TredsStore through the same command registry, so arguments are validated and replies are built the
+// same way, and exposes them as a goroutine-safe typed API. The store can be persisted on disk in the snapshot
+// format of the server, which like on the server holds only the key value pairs, without their expiry.
+package embed
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"treds/commands"
+	"treds/logging"
+	"treds/resp"
+	"treds/store"
+)
+
+const (
+	// stateFile and metaFile are the files of a server snapshot folder, RESTORE reads them
+	stateFile = "state.bin"
+	metaFile  = "meta.json"
+)
+
+var logger = logging.Named("embed")
+
+var (
+	// ErrNil is returned when the key or member does not exist
+	ErrNil = errors.New("treds: nil")
+	// ErrClosed is returned by the calls made after Close
+	ErrClosed = errors.New("treds: db is closed")
+)
+
+// Options configure a DB
+type Options struct {
+	// Dir persists the store in a snapshot folder, it is restored by Open and written by Snapshot and Close.
+	// Like the snapshots of the server only the key value pairs are persisted, without their expiry, the other
+	// data types are kept in memory only and are empty after reopening. A snapshot folder of the server can be
+	// opened, and RESTORE accepts the folder written. Empty keeps the store in memory only.
+	Dir string
+	// SnapshotInterval snapshots the store periodically when Dir is set, 0 only snapshots on Snapshot and Close
+	SnapshotInterval time.Duration
+	// ExpirySweepInterval is how often expired keys are removed, 100 milliseconds by default like the server
+	ExpirySweepInterval time.Duration
+}
+
+// DB is an in process Treds store, it is safe for concurrent use
+type DB struct {
+	opts     Options
+	registry commands.CommandRegistry
+
+	// mu serializes the commands, reads too since they remove the keys found expired
+	mu     sync.Mutex
+	store  *store.TredsStore
+	closed bool
+
+	// snapshotMu serializes the snapshots, the periodic one can run with Snapshot and Close
+	snapshotMu sync.Mutex
+
+	stop chan struct{}
+	done sync.WaitGroup
+}
+
+// Open returns a DB, restoring the snapshot in opts.Dir when there is one
+func Open(opts Options) (*DB, error) {
+	if opts.ExpirySweepInterval <= 0 {
+		opts.ExpirySweepInterval = 100 * time.Millisecond
+	}
+	registry := commands.NewRegistry()
+	commands.RegisterCommands(registry)
+	db := &DB{opts: opts, registry: registry, store: store.NewTredsStore(), stop: make(chan struct{})}
+
+	if opts.Dir != "" {
+		if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(filepath.Join(opts.Dir, stateFile))
+		switch {
+		case err == nil:
+			if err = db.store.Restore(data); err != nil {
+				return nil, fmt.Errorf("restoring %s: %w", opts.Dir, err)
+			}
+			logger.Info("Restored snapshot", "dir", opts.Dir, "bytes", len(data))
+		case !errors.Is(err, os.ErrNotExist):
+			return nil, err
+		}
+	}
+
+	db.every(opts.ExpirySweepInterval, func() {
+		db.mu.Lock()
+		defer db.mu.Unlock()
+		db.store.CleanUpExpiredKeys()
+	})
+	if opts.Dir != "" && opts.SnapshotInterval > 0 {
+		db.every(opts.SnapshotInterval, func() {
+			if err := db.Snapshot(); err != nil {
+				logger.Error("Error occurred writing snapshot", "dir", opts.Dir, "error", err)
+			}
+		})
+	}
+	return db, nil
+}
+
+// every runs f every interval until the DB is closed
+func (db *DB) every(interval time.Duration, f func()) {
+	db.done.Add(1)
+	go func() {
+		defer db.done.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-db.stop:
+				return
+			case <-ticker.C:
+				f()
+			}
+		}
+	}()
+}
+
+// Close stops the background work and writes a last snapshot when Dir is set
+func (db *DB) Close() error {
+	db.mu.Lock()
+	if db.closed {
+		db.mu.Unlock()
+		return ErrClosed
+	}
+	db.closed = true
+	db.mu.Unlock()
+	close(db.stop)
+	db.done.Wait()
+	if db.opts.Dir == "" {
+		return nil
+	}
+	return db.snapshot()
+}
+
+// Snapshot writes the store to Dir, replacing the previous snapshot
+func (db *DB) Snapshot() error {
+	if db.opts.Dir == "" {
+		return fmt.Errorf("treds: no snapshot dir configured")
+	}
+	db.mu.Lock()
+	closed := db.closed
+	db.mu.Unlock()
+	if closed {
+		return ErrClosed
+	}
+	return db.snapshot()
+}
+
+func (db *DB) snapshot() error {
+	db.snapshotMu.Lock()
+	defer db.snapshotMu.Unlock()
+	db.mu.Lock()
+	data, err := db.store.Snapshot()
+	db.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	// The meta is the one of a Raft snapshot, so RESTORE accepts the folder
+	meta, err := json.Marshal(map[string]interface{}{
+		"Version": 1,
+		"ID":      fmt.Sprintf("embed-%d", time.Now().UnixMilli()),
+		"Size":    len(data),
+	})
+	if err != nil {
+		return err
+	}
+	if err = writeFile(db.opts.Dir, stateFile, data); err != nil {
+		return err
+	}
+	return writeFile(db.opts.Dir, metaFile, meta)
+}
+
+// writeFile replaces the file name of dir with data atomically, a crash leaves the previous file in place
+func writeFile(dir string, name string, data []byte) error {
+	tmp, err := os.CreateTemp(dir, name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), filepath.Join(dir, name)); err != nil {
+		return err
+	}
+	// The rename is durable once the folder is synced
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// Do runs a store command and returns its reply decoded like resp.ParseReply does, the replies of RESP3 are
+// used. An error reply is returned as a resp.ReplyError error. Server commands like MULTI or SUBSCRIBE are
+// not available.
+func (db *DB) Do(args ...string) (interface{}, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("treds: empty command")
+	}
+	commandReg, err := db.registry.Retrieve(strings.ToUpper(args[0]))
+	if err != nil {
+		return nil, resp.ReplyError(err.Error())
+	}
+	if err = commandReg.Validate(args[1:]); err != nil {
+		return nil, resp.ReplyError(err.Error())
+	}
+	execute := commandReg.Execute
+	if commandReg.ExecuteRESP3 != nil {
+		execute = commandReg.ExecuteRESP3
+	}
+
+	db.mu.Lock()
+	if db.closed {
+		db.mu.Unlock()
+		return nil, ErrClosed
+	}
+	res := commandReg.Reply(execute(args[1:], db.store), resp.RESP3)
+	db.mu.Unlock()
+
+	reply, err := resp.ParseReply(bufio.NewReader(strings.NewReader(res)))
+	if err != nil {
+		return nil, err
+	}
+	if replyErr, ok := reply.(resp.ReplyError); ok {
+		return nil, replyErr
+	}
+	return reply, nil
+}
@@ -0,0 +1,213 @@
+package embed
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/require"
+	"treds/resp"
+	"treds/store"
+)
+
+func openDB(t *testing.T, opts Options) *DB {
+	db, err := Open(opts)
+	require.NoError(t, err)
+	return db
+}
+
+func TestKeyValue(t *testing.T) {
+	db := openDB(t, Options{})
+	defer db.Close()
+
+	require.NoError(t, db.Set("user:1", "alice"))
+	require.NoError(t, db.Set("user:2", "bob"))
+	require.NoError(t, db.Set("user:3", "carol"))
+	require.NoError(t, db.Set("order:1", "book"))
+
+	value, err := db.Get("user:1")
+	require.NoError(t, err)
+	require.Equal(t, "alice", value)
+	_, err = db.Get("missing")
+	require.ErrorIs(t, err, ErrNil)
+
+	var replyErr resp.ReplyError
+	page, cursor, err := db.PrefixScan("user:", "0", 2)
+	require.NoError(t, err)
+	require.Equal(t, []KV{{"user:1", "alice"}, {"user:2", "bob"}}, page)
+	require.NotEqual(t, "0", cursor)
+	page, cursor, err = db.PrefixScan("user:", cursor, 2)
+	require.NoError(t, err)
+	require.Equal(t, []KV{{"user:3", "carol"}}, page)
+	require.Equal(t, "0", cursor)
+
+	kv, err := db.LongestPrefix("user:1:profile")
+	require.NoError(t, err)
+	require.Equal(t, KV{"user:1", "alice"}, kv)
+	_, err = db.LongestPrefix("nothing")
+	require.ErrorIs(t, err, ErrNil)
+
+	count, err := db.IncrBy("visits", 5)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), count)
+	price, err := db.IncrByFloat("price", 1.5)
+	require.NoError(t, err)
+	require.Equal(t, 1.5, price)
+	_, err = db.IncrBy("price", 1)
+	require.ErrorAs(t, err, &replyErr)
+
+	require.NoError(t, db.Del("user:1"))
+	_, err = db.Get("user:1")
+	require.ErrorIs(t, err, ErrNil)
+
+	// Arguments are validated like the server does
+	_, err = db.Do("SET", "only-key")
+	require.ErrorAs(t, err, &replyErr)
+	_, err = db.Do("NOPE")
+	require.ErrorAs(t, err, &replyErr)
+}
+
+func TestSortedMapCollectionVector(t *testing.T) {
+	db := openDB(t, Options{})
+	defer db.Close()
+
+	require.NoError(t, db.ZAdd("scores", ScoredKV{Score: 2, Key: "b", Value: "vb"}, ScoredKV{Score: 1, Key: "a", Value: "va"}))
+	score, err := db.ZScore("scores", "b")
+	require.NoError(t, err)
+	require.Equal(t, 2.0, score)
+	members, err := db.ZRangeByScore("scores", 0, 10, 0, 10)
+	require.NoError(t, err)
+	require.Equal(t, []ScoredKV{{1, "a", "va"}, {2, "b", "vb"}}, members)
+	score, err = db.ZIncrBy("scores", 2, "a")
+	require.NoError(t, err)
+	require.Equal(t, 3.0, score)
+	members, err = db.ZRangeByScore("scores", 0, 10, 0, 10)
+	require.NoError(t, err)
+	require.Equal(t, []ScoredKV{{2, "b", "vb"}, {3, "a", "va"}}, members)
+
+	require.NoError(t, db.DCreate("users", "", ""))
+	_, err = db.DInsert("users", map[string]interface{}{"name": "alice", "age": 30})
+	require.NoError(t, err)
+	documents, err := db.DQuery("users", map[string]interface{}{"filters": []interface{}{}})
+	require.NoError(t, err)
+	require.Len(t, documents, 1)
+	var user struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+	require.NoError(t, json.Unmarshal(documents[0], &user))
+	require.Equal(t, "alice", user.Name)
+	require.Equal(t, 30, user.Age)
+
+	require.NoError(t, db.VCreate("points", VectorOptions{}))
+	id, err := db.VInsert("points", []float64{1, 2})
+	require.NoError(t, err)
+	_, err = db.VInsert("points", []float64{10, 20})
+	require.NoError(t, err)
+	matches, err := db.VSearch("points", []float64{1.1, 2}, 1)
+	require.NoError(t, err)
+	require.Equal(t, []VectorMatch{{ID: id, Vector: []float64{1, 2}}}, matches)
+}
+
+func TestExpiry(t *testing.T) {
+	db := openDB(t, Options{ExpirySweepInterval: 10 * time.Millisecond})
+	defer db.Close()
+
+	require.NoError(t, db.Set("session", "token"))
+	require.NoError(t, db.Expire("session", 1))
+	require.Eventually(t, func() bool {
+		size, err := db.Do("DBSIZE")
+		return err == nil && size == int64(0)
+	}, 3*time.Second, 10*time.Millisecond)
+}
+
+func TestConcurrentUse(t *testing.T) {
+	db := openDB(t, Options{})
+	defer db.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				key := fmt.Sprintf("k:%d:%d", i, j)
+				require.NoError(t, db.Set(key, key))
+				_, err := db.Get(key)
+				require.NoError(t, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	size, err := db.Do("DBSIZE")
+	require.NoError(t, err)
+	require.Equal(t, int64(800), size)
+}
+
+func TestPersistence(t *testing.T) {
+	dir := t.TempDir()
+	db := openDB(t, Options{Dir: dir})
+	require.NoError(t, db.Set("a", "1"))
+	require.NoError(t, db.Set("b", "2"))
+	require.NoError(t, db.Expire("b", 1000))
+	require.NoError(t, db.ZAdd("scores", ScoredKV{Score: 1, Key: "a"}))
+	require.NoError(t, db.Close())
+	require.ErrorIs(t, db.Set("c", "3"), ErrClosed)
+
+	// The folder is a snapshot RESTORE accepts
+	data, err := os.ReadFile(filepath.Join(dir, metaFile))
+	require.NoError(t, err)
+	var meta raft.SnapshotMeta
+	require.NoError(t, json.Unmarshal(data, &meta))
+	require.Equal(t, raft.SnapshotVersion(1), meta.Version)
+	state, err := os.Stat(filepath.Join(dir, stateFile))
+	require.NoError(t, err)
+	require.Equal(t, state.Size(), meta.Size)
+
+	db = openDB(t, Options{Dir: dir, SnapshotInterval: 10 * time.Millisecond})
+	defer db.Close()
+	value, err := db.Get("b")
+	require.NoError(t, err)
+	require.Equal(t, "2", value)
+
+	// Only key value pairs are persisted, without their expiry
+	reply, err := db.Do("TTL", "b")
+	require.NoError(t, err)
+	require.Equal(t, int64(-1), reply)
+	_, err = db.ZScore("scores", "a")
+	require.ErrorIs(t, err, ErrNil)
+
+	// Snapshots running at the same time do not tear the files
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, db.Snapshot())
+		}()
+	}
+	wg.Wait()
+	temps, err := filepath.Glob(filepath.Join(dir, "*.tmp"))
+	require.NoError(t, err)
+	require.Empty(t, temps)
+
+	require.NoError(t, db.Set("c", "3"))
+	// The periodic snapshot picks up the write
+	require.Eventually(t, func() bool {
+		data, err := os.ReadFile(filepath.Join(dir, stateFile))
+		if err != nil {
+			return false
+		}
+		s := store.NewTredsStore()
+		if err = s.Restore(data); err != nil {
+			return false
+		}
+		value, _, err := s.Get("c")
+		return err == nil && value == "3"
+	}, 3*time.Second, 20*time.Millisecond)
+}
@@ -0,0 +1,40 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	gnetlogging "github.com/panjf2000/gnet/v2/pkg/logging"
+)
+
+// gnetLogger bridges the logs of the gnet event loops into the Treds logger
+type gnetLogger struct {
+	logger *slog.Logger
+}
+
+// Gnet returns a gnet logger named name writing through the Treds logger
+func Gnet(name string) gnetlogging.Logger {
+	return &gnetLogger{logger: Named(name)}
+}
+
+func (l *gnetLogger) Debugf(format string, args ...interface{}) {
+	l.logger.Debug(fmt.Sprintf(format, args...))
+}
+
+func (l *gnetLogger) Infof(format string, args ...interface{}) {
+	l.logger.Info(fmt.Sprintf(format, args...))
+}
+
+func (l *gnetLogger) Warnf(format string, args ...interface{}) {
+	l.logger.Warn(fmt.Sprintf(format, args...))
+}
+
+func (l *gnetLogger) Errorf(format string, args ...interface{}) {
+	l.logger.Error(fmt.Sprintf(format, args...))
+}
+
+func (l *gnetLogger) Fatalf(format string, args ...interface{}) {
+	l.logger.Error(fmt.Sprintf(format, args...))
+	os.Exit(1)
+}
@@ -0,0 +1,159 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"log/slog"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// hclogLogger bridges the hclog loggers of Raft into the Treds logger, its records are written in the
+// configured format at the configured level
+type hclogLogger struct {
+	name    string
+	implied []interface{}
+	logger  *slog.Logger
+}
+
+// Hclog returns an hclog.Logger named name writing through the Treds logger
+func Hclog(name string) hclog.Logger {
+	return &hclogLogger{name: name, logger: Named(name)}
+}
+
+func (l *hclogLogger) Log(level hclog.Level, msg string, args ...interface{}) {
+	if level == hclog.Off {
+		return
+	}
+	l.logger.Log(context.Background(), slogLevel(level), msg, args...)
+}
+
+func (l *hclogLogger) Trace(msg string, args ...interface{}) { l.Log(hclog.Trace, msg, args...) }
+func (l *hclogLogger) Debug(msg string, args ...interface{}) { l.Log(hclog.Debug, msg, args...) }
+func (l *hclogLogger) Info(msg string, args ...interface{})  { l.Log(hclog.Info, msg, args...) }
+func (l *hclogLogger) Warn(msg string, args ...interface{})  { l.Log(hclog.Warn, msg, args...) }
+func (l *hclogLogger) Error(msg string, args ...interface{}) { l.Log(hclog.Error, msg, args...) }
+
+func (l *hclogLogger) IsTrace() bool { return l.enabled(hclog.Trace) }
+func (l *hclogLogger) IsDebug() bool { return l.enabled(hclog.Debug) }
+func (l *hclogLogger) IsInfo() bool  { return l.enabled(hclog.Info) }
+func (l *hclogLogger) IsWarn() bool  { return l.enabled(hclog.Warn) }
+func (l *hclogLogger) IsError() bool { return l.enabled(hclog.Error) }
+
+func (l *hclogLogger) enabled(level hclog.Level) bool {
+	return l.logger.Enabled(context.Background(), slogLevel(level))
+}
+
+func (l *hclogLogger) ImpliedArgs() []interface{} {
+	return l.implied
+}
+
+func (l *hclogLogger) With(args ...interface{}) hclog.Logger {
+	implied := append(append([]interface{}{}, l.implied...), args...)
+	return &hclogLogger{name: l.name, implied: implied, logger: l.logger.With(args...)}
+}
+
+func (l *hclogLogger) Name() string {
+	return l.name
+}
+
+// Named appends name to the name of the logger, like hclog does
+func (l *hclogLogger) Named(name string) hclog.Logger {
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+	return l.ResetNamed(name)
+}
+
+func (l *hclogLogger) ResetNamed(name string) hclog.Logger {
+	return &hclogLogger{name: name, implied: l.implied, logger: Named(name).With(l.implied...)}
+}
+
+// SetLevel is ignored, the level is shared by every logger and set with SetLevel of this package
+func (l *hclogLogger) SetLevel(hclog.Level) {}
+
+func (l *hclogLogger) GetLevel() hclog.Level {
+	switch current := Level(); {
+	case current <= LevelTrace:
+		return hclog.Trace
+	case current <= slog.LevelDebug:
+		return hclog.Debug
+	case current <= slog.LevelInfo:
+		return hclog.Info
+	case current <= slog.LevelWarn:
+		return hclog.Warn
+	default:
+		return hclog.Error
+	}
+}
+
+func (l *hclogLogger) StandardLogger(opts *hclog.StandardLoggerOptions) *log.Logger {
+	return log.New(l.StandardWriter(opts), "", 0)
+}
+
+func (l *hclogLogger) StandardWriter(opts *hclog.StandardLoggerOptions) io.Writer {
+	if opts == nil {
+		opts = &hclog.StandardLoggerOptions{}
+	}
+	return &standardWriter{logger: l, opts: opts}
+}
+
+// standardWriter logs every line written to it, at the level of its [LEVEL] prefix when inferring levels
+type standardWriter struct {
+	logger *hclogLogger
+	opts   *hclog.StandardLoggerOptions
+}
+
+func (w *standardWriter) Write(p []byte) (int, error) {
+	line := string(bytes.TrimRight(p, " \t\n"))
+	level := hclog.Info
+	if w.opts.ForceLevel != hclog.NoLevel {
+		level, line = w.opts.ForceLevel, trimLevel(line)
+	} else if w.opts.InferLevels {
+		level, line = inferLevel(line)
+	}
+	w.logger.Log(level, line)
+	return len(p), nil
+}
+
+// standardLevels are the prefixes of lines written to standard loggers
+var standardLevels = map[string]hclog.Level{
+	"[TRACE]": hclog.Trace,
+	"[DEBUG]": hclog.Debug,
+	"[INFO]":  hclog.Info,
+	"[WARN]":  hclog.Warn,
+	"[ERR]":   hclog.Error,
+	"[ERROR]": hclog.Error,
+}
+
+func inferLevel(line string) (hclog.Level, string) {
+	for prefix, level := range standardLevels {
+		if strings.HasPrefix(line, prefix) {
+			return level, strings.TrimSpace(line[len(prefix):])
+		}
+	}
+	return hclog.Info, line
+}
+
+func trimLevel(line string) string {
+	_, line = inferLevel(line)
+	return line
+}
+
+func slogLevel(level hclog.Level) slog.Level {
+	switch level {
+	case hclog.Trace:
+		return LevelTrace
+	case hclog.Debug:
+		return slog.LevelDebug
+	case hclog.Warn:
+		return slog.LevelWarn
+	case hclog.Error:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
@@ -0,0 +1,121 @@
+// Package logging is the structured logger of Treds. Every subsystem logs through a named logger, the records
+// are written as logfmt or JSON lines and the level can be changed while the node runs.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// Formats of the log lines
+const (
+	FormatLogfmt = "logfmt"
+	FormatJSON   = "json"
+)
+
+// LevelTrace is below debug, Raft logs its RPCs at this level
+const LevelTrace = slog.Level(-8)
+
+var (
+	level = new(slog.LevelVar)
+	// output is the handler named loggers forward to, swapped by Configure
+	output atomic.Pointer[slog.Handler]
+)
+
+func init() {
+	if err := Configure(os.Stderr, FormatLogfmt); err != nil {
+		panic(err)
+	}
+}
+
+// Configure writes the logs of every logger to w in format, logfmt or json
+func Configure(w io.Writer, format string) error {
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: replaceLevel}
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case FormatLogfmt:
+		handler = slog.NewTextHandler(w, opts)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, opts)
+	default:
+		return fmt.Errorf("invalid log format '%s', expected logfmt or json", format)
+	}
+	output.Store(&handler)
+	return nil
+}
+
+// ParseLevel parses trace, debug, info, warn or error
+func ParseLevel(name string) (slog.Level, error) {
+	if strings.EqualFold(name, "trace") {
+		return LevelTrace, nil
+	}
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(name)); err != nil {
+		return 0, fmt.Errorf("invalid log level '%s', expected trace, debug, info, warn or error", name)
+	}
+	return l, nil
+}
+
+// SetLevel sets the least level logged by every logger
+func SetLevel(name string) error {
+	l, err := ParseLevel(name)
+	if err != nil {
+		return err
+	}
+	level.Set(l)
+	return nil
+}
+
+// Level returns the least level logged
+func Level() slog.Level {
+	return level.Level()
+}
+
+// Named returns the logger of a subsystem, its records carry the name under the logger key
+func Named(name string) *slog.Logger {
+	return slog.New(&forwardHandler{}).With("logger", name)
+}
+
+func replaceLevel(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 && a.Key == slog.LevelKey && a.Value.Any() == LevelTrace {
+		a.Value = slog.StringValue("TRACE")
+	}
+	return a
+}
+
+// forwardHandler forwards records to the handler set by Configure when they are logged, so loggers
+// created before the node is configured follow the configured format
+type forwardHandler struct {
+	// scope replays the attributes and groups added with With and WithGroup
+	scope []func(slog.Handler) slog.Handler
+}
+
+func (h *forwardHandler) Enabled(_ context.Context, l slog.Level) bool {
+	return l >= level.Level()
+}
+
+func (h *forwardHandler) Handle(ctx context.Context, r slog.Record) error {
+	handler := *output.Load()
+	for _, apply := range h.scope {
+		handler = apply(handler)
+	}
+	return handler.Handle(ctx, r)
+}
+
+func (h *forwardHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(handler slog.Handler) slog.Handler { return handler.WithAttrs(attrs) })
+}
+
+func (h *forwardHandler) WithGroup(name string) slog.Handler {
+	return h.with(func(handler slog.Handler) slog.Handler { return handler.WithGroup(name) })
+}
+
+func (h *forwardHandler) with(apply func(slog.Handler) slog.Handler) slog.Handler {
+	scope := make([]func(slog.Handler) slog.Handler, 0, len(h.scope)+1)
+	return &forwardHandler{scope: append(append(scope, h.scope...), apply)}
+}
@@ -0,0 +1,68 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+)
+
+func captureLogs(t *testing.T, format string) *bytes.Buffer {
+	var buf bytes.Buffer
+	require.NoError(t, Configure(&buf, format))
+	t.Cleanup(func() {
+		require.NoError(t, Configure(os.Stderr, FormatLogfmt))
+		require.NoError(t, SetLevel("info"))
+	})
+	return &buf
+}
+
+func TestNamed(t *testing.T) {
+	// Loggers created before Configure follow the configured output
+	logger := Named("server").With("client", 7)
+	buf := captureLogs(t, FormatJSON)
+
+	logger.Info("Closing client", "queued", 100)
+	logger.Debug("hidden")
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	require.Equal(t, "INFO", record["level"])
+	require.Equal(t, "server", record["logger"])
+	require.Equal(t, "Closing client", record["msg"])
+	require.Equal(t, float64(7), record["client"])
+	require.Equal(t, float64(100), record["queued"])
+
+	buf.Reset()
+	require.NoError(t, SetLevel("trace"))
+	logger.Log(context.Background(), LevelTrace, "traced")
+	require.Contains(t, buf.String(), `"level":"TRACE"`)
+
+	require.Error(t, SetLevel("verbose"))
+	require.Error(t, Configure(os.Stderr, "xml"))
+}
+
+func TestHclog(t *testing.T) {
+	buf := captureLogs(t, FormatLogfmt)
+	logger := Hclog("raft").Named("snapshot").With("id", "1-2")
+
+	require.Equal(t, "raft.snapshot", logger.Name())
+	require.False(t, logger.IsDebug())
+	logger.Debug("hidden")
+	logger.Warn("failed to contact", "peer", "node2")
+	line := strings.TrimSpace(buf.String())
+	require.Contains(t, line, "level=WARN")
+	require.Contains(t, line, `msg="failed to contact"`)
+	require.Contains(t, line, "logger=raft.snapshot id=1-2 peer=node2")
+
+	buf.Reset()
+	require.NoError(t, SetLevel("debug"))
+	require.True(t, logger.IsDebug())
+	require.Equal(t, hclog.Debug, logger.GetLevel())
+	logger.StandardLogger(&hclog.StandardLoggerOptions{InferLevels: true}).Print("[ERR] raft: failed")
+	require.Contains(t, buf.String(), `level=ERROR msg="raft: failed"`)
+}
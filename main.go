@@ -1,9 +1,10 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
-	"log"
+	"net"
 	"os"
 	"os/signal"
 	"strconv"
@@ -11,15 +12,21 @@ import (
 	"syscall"
 	"time"
 
+	"treds/config"
+	"treds/logging"
 	"treds/server"
+	"treds/store"
 
 	"github.com/panjf2000/gnet/v2"
 )
 
-const DefaultPort = "7997"
-const DefaultBind = "localhost"
-const DefaultAdvertise = "localhost"
-const DefaultSegmentSize = 200
+var logger = logging.Named("main")
+
+// fatal logs err and exits
+func fatal(msg string, err error) {
+	logger.Error(msg, "error", err)
+	os.Exit(1)
+}
 
 func parseServers(input string) []server.BootStrapServer {
 	if input == "" {
@@ -32,7 +39,7 @@ func parseServers(input string) []server.BootStrapServer {
 	for _, entry := range serverEntries {
 		parts := strings.Split(entry, ":")
 		if len(parts) != 3 {
-			fmt.Printf("Error: Invalid server format '%s'. Skipping...\n", entry)
+			logger.Warn("Invalid server format, expected id:host:port, skipping", "server", entry)
 			continue
 		}
 
@@ -53,49 +60,143 @@ func parseServers(input string) []server.BootStrapServer {
 }
 
 func main() {
-	serverId := flag.String("id", "", "Server Id - must be a uuid, if not given a new one will be generated")
-	portFlag := flag.String("port", DefaultPort, "Port at which server will listen")
-	segmentSize := flag.Int("segmentSize", DefaultSegmentSize, "Segment size")
-	bindAddr := flag.String("bind", DefaultBind, "Bind Address")
-	advertiseAddr := flag.String("advertise", DefaultAdvertise, "Advertise Address")
-	applyTimeout := flag.Duration("raftApplyTimeout", 1*time.Second, "Raft Apply Timeout")
-	servers := flag.String("servers", "", "Comma-separated list of servers in the format id:host:port (e.g., 'uuid1:127.0.0.1:8080,uuid2:192.168.1.1:9090')")
+	cfg := config.Default()
+	// TREDS_PORT is the default port, the config file and the flags override it
+	if port := os.Getenv("TREDS_PORT"); port != "" {
+		portInt, err := strconv.Atoi(port)
+		if err != nil {
+			fatal("Invalid TREDS_PORT", err)
+		}
+		cfg.Port = portInt
+	}
+	configFile := flag.String("config", "", "YAML config file, flags given on the command line take precedence over it")
+	config.BindFlags(flag.CommandLine, cfg)
 
 	flag.Parse()
 
-	serverList := parseServers(*servers)
+	if *configFile != "" {
+		fileCfg, err := config.Load(*configFile, cfg)
+		if err != nil {
+			fatal("Error loading the config file", err)
+		}
+		flags := flag.NewFlagSet("", flag.ContinueOnError)
+		config.BindFlags(flags, fileCfg)
+		flag.Visit(func(f *flag.Flag) {
+			if f.Name != "config" {
+				_ = flags.Set(f.Name, f.Value.String())
+			}
+		})
+		cfg = fileCfg
+	}
+
+	if err := logging.Configure(os.Stderr, cfg.LogFormat); err != nil {
+		fatal("Invalid log format", err)
+	}
+	if err := logging.SetLevel(cfg.LogLevel); err != nil {
+		fatal("Invalid log level", err)
+	}
+
+	serverList := parseServers(cfg.Servers)
 
 	var sigs = make(chan os.Signal, 1)
 	signal.Notify(sigs, syscall.SIGTERM, syscall.SIGINT)
 
-	port := os.Getenv("TREDS_PORT")
+	if cfg.EventLoops < 1 {
+		fatal("Invalid eventLoops", fmt.Errorf("eventLoops must be at least 1"))
+	}
 
-	if len(port) == 0 {
-		port = DefaultPort
+	shardBy, err := store.ParseShardBy(cfg.ShardBy)
+	if err != nil {
+		fatal("Invalid shardBy", err)
 	}
 
-	if portFlag != nil && *portFlag != "" {
-		port = *portFlag
+	tlsOptions := &server.TLSOptions{
+		CertFile:    cfg.TLSCert,
+		KeyFile:     cfg.TLSKey,
+		CAFile:      cfg.TLSCA,
+		AuthClients: cfg.TLSAuthClients,
 	}
 
-	portInt, err := strconv.Atoi(port)
+	tredsServer, err := server.New(cfg, serverList, shardBy, tlsOptions)
+	if err != nil {
+		fatal("Error starting the server", err)
+	}
+	tredsServer.SetConfigFile(*configFile)
 
+	listenList := cfg.Listen
+	if listenList == "" {
+		listenList = "tcp://" + net.JoinHostPort(cfg.Bind, strconv.Itoa(tredsServer.Port))
+	}
+	listenAddrs, err := server.ParseListenAddrs(listenList)
 	if err != nil {
-		panic(err)
+		fatal("Invalid listen addresses", err)
 	}
 
-	tredsServer, err := server.New(portInt, *segmentSize, *bindAddr, *advertiseAddr, *serverId, *applyTimeout, serverList)
+	perm, err := strconv.ParseUint(cfg.UnixSocketPerm, 8, 32)
 	if err != nil {
-		log.Fatal(err)
+		fatal("Invalid unixSocketPerm", err)
+	}
+	tredsServer.SetListenAddrs(listenAddrs, os.FileMode(perm))
+	tredsServer.SetClusterSecret(cfg.ClusterSecret)
+
+	gnetAddrs := listenAddrs
+	if tlsOptions.Enabled() {
+		// TLS is terminated in front of the event loops, which then only listen on a private unix socket for TCP.
+		// Unix sockets are local and stay in plaintext.
+		gnetAddrs, err = tredsServer.StartTLSProxies(listenAddrs, cfg.DataDir, tlsOptions)
+		if err != nil {
+			fatal("Error starting the TLS listener", err)
+		}
+	}
+
+	if cfg.HTTPAddr != "" {
+		if _, errHTTP := tredsServer.StartHTTP(cfg.HTTPAddr, tlsOptions); errHTTP != nil {
+			fatal("Error starting the HTTP gateway", errHTTP)
+		}
+	}
+
+	if cfg.GRPCAddr != "" {
+		if _, errGRPC := tredsServer.StartGRPC(cfg.GRPCAddr, tlsOptions); errGRPC != nil {
+			fatal("Error starting the gRPC service", errGRPC)
+		}
 	}
 
-	log.Fatal(gnet.Run(
+	if cfg.MetricsAddr != "" {
+		if _, errMetrics := tredsServer.StartMetrics(cfg.MetricsAddr); errMetrics != nil {
+			fatal("Error starting the metrics server", errMetrics)
+		}
+	}
+
+	shutdownErr := make(chan error, 1)
+	go func() {
+		sig := <-sigs
+		logger.Info("Received signal", "signal", sig.String())
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.DrainTimeout)
+		defer cancel()
+		shutdownErr <- tredsServer.Shutdown(ctx)
+	}()
+
+	// All listeners share the same server and event loops, their unix sockets are never reachable with wider permissions
+	gnetAddrs, err = tredsServer.StageUnixSockets(gnetAddrs)
+	if err != nil {
+		fatal("Error creating the unix sockets", err)
+	}
+	err = gnet.Rotate(
 		tredsServer,
-		"tcp://0.0.0.0:"+strconv.Itoa(tredsServer.Port),
-		// Single Event loop
-		gnet.WithMulticore(false),
+		gnetAddrs,
+		// One event loop per store shard
+		gnet.WithMulticore(cfg.EventLoops > 1),
+		gnet.WithNumEventLoop(cfg.EventLoops),
 		gnet.WithReusePort(false),
 		gnet.WithTCPKeepAlive(300*time.Second),
-	))
-
+		gnet.WithLogger(logging.Gnet("gnet")),
+	)
+	if err != nil {
+		fatal("Error running the event loops", err)
+	}
+	// The event loops are stopped by Shutdown, which then snapshots and closes the WAL
+	if err = <-shutdownErr; err != nil {
+		fatal("Error shutting down", err)
+	}
+	logger.Info("Shutdown complete")
 }
@@ -1,54 +1,26 @@
 package resp
 
 import (
+	"errors"
 	"fmt"
-	"strconv"
-	"strings"
 )
 
-// Decode parses a RESP command string and returns the command and arguments
+// Decode parses a RESP command string and returns the command and arguments.
+// Both the array format and inline commands are accepted.
 func Decode(respInput string) (string, []string, error) {
-	lines := strings.Split(respInput, "\r\n")
-	if len(lines) < 1 || lines[0][0] != '*' {
-		return "", nil, fmt.Errorf("invalid RESP input: missing array prefix '*'")
+	if len(respInput) == 0 {
+		return "", nil, fmt.Errorf("invalid RESP input: empty command")
 	}
 
-	// Parse the array length
-	arrayLength, err := strconv.Atoi(lines[0][1:])
-	if err != nil {
-		return "", nil, fmt.Errorf("invalid array length: %v", err)
-	}
-
-	if arrayLength < 1 {
-		return "", nil, fmt.Errorf("invalid command: array length must be at least 1")
+	args, _, err := parseFrame([]byte(respInput))
+	if errors.Is(err, errIncomplete) {
+		return "", nil, fmt.Errorf("mismatch between declared and parsed array length")
 	}
-
-	// Parse the bulk strings
-	args := make([]string, 0, arrayLength)
-	i := 1
-	for len(args) < arrayLength && i < len(lines) {
-		if len(lines[i]) > 0 && lines[i][0] == '$' {
-			// Parse the bulk string length
-			bulkLength, err := strconv.Atoi(lines[i][1:])
-			if err != nil || bulkLength < 0 {
-				return "", nil, fmt.Errorf("invalid bulk string length: %v", err)
-			}
-
-			// Ensure the bulk string value exists
-			if i+1 >= len(lines) || len(lines[i+1]) != bulkLength {
-				return "", nil, fmt.Errorf("bulk string length mismatch")
-			}
-
-			// Append the value to args
-			args = append(args, lines[i+1])
-			i += 2 // Move to the next bulk string header
-		} else {
-			return "", nil, fmt.Errorf("expected bulk string prefix '$', found: %s", lines[i])
-		}
+	if err != nil {
+		return "", nil, err
 	}
-
-	if len(args) != arrayLength {
-		return "", nil, fmt.Errorf("mismatch between declared and parsed array length")
+	if len(args) == 0 {
+		return "", nil, fmt.Errorf("invalid RESP input: empty command")
 	}
 
 	// The first argument is the command (e.g., "SET")
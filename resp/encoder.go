@@ -3,6 +3,7 @@ package resp
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"strconv"
 )
 
@@ -77,13 +78,18 @@ func EncodeStringArrayRESP(arr []string) string {
 	return buffer.String()
 }
 
-// EncodeArray encodes a RESP array, handling nested arrays and nulls
+// EncodeArray encodes a RESP array, handling nested arrays and nulls.
+// float64, bool and nil elements are encoded with their RESP3 types.
 func EncodeArray(elements []interface{}) string {
 	if elements == nil {
 		return "*-1\r\n" // Null array
 	}
+	return encodeAggregate('*', len(elements), elements)
+}
+
+func encodeAggregate(prefix byte, length int, elements []interface{}) string {
 	var buffer bytes.Buffer
-	buffer.WriteString(fmt.Sprintf("*%d\r\n", len(elements)))
+	buffer.WriteString(fmt.Sprintf("%c%d\r\n", prefix, length))
 	for _, element := range elements {
 		switch v := element.(type) {
 		case string:
@@ -92,6 +98,14 @@ func EncodeArray(elements []interface{}) string {
 			buffer.WriteString(EncodeInteger(v))
 		case []interface{}:
 			buffer.WriteString(EncodeArray(v))
+		case Map:
+			buffer.WriteString(EncodeValueMap(v))
+		case float64:
+			buffer.WriteString(EncodeDouble(v))
+		case bool:
+			buffer.WriteString(EncodeBoolean(v))
+		case nil:
+			buffer.WriteString(EncodeNull())
 		default:
 			buffer.WriteString(EncodeError("ERR unsupported type"))
 		}
@@ -109,3 +123,59 @@ func EncodeMap(m map[string]string) string {
 	}
 	return buffer.String()
 }
+
+// Protocol versions negotiated with HELLO
+const (
+	RESP2 = 2
+	RESP3 = 3
+)
+
+// EncodeNull encodes a RESP3 null
+func EncodeNull() string {
+	return "_\r\n"
+}
+
+// EncodeBoolean encodes a RESP3 boolean
+func EncodeBoolean(b bool) string {
+	if b {
+		return "#t\r\n"
+	}
+	return "#f\r\n"
+}
+
+// EncodeDouble encodes a RESP3 double
+func EncodeDouble(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return ",inf\r\n"
+	case math.IsInf(f, -1):
+		return ",-inf\r\n"
+	case math.IsNaN(f):
+		return ",nan\r\n"
+	}
+	return "," + strconv.FormatFloat(f, 'f', -1, 64) + "\r\n"
+}
+
+// EncodeStringMap encodes alternating keys and values as a RESP3 map, keeping their order
+func EncodeStringMap(pairs []string) string {
+	var buffer bytes.Buffer
+	buffer.WriteString(fmt.Sprintf("%%%d\r\n", len(pairs)/2))
+	for i := 0; i+1 < len(pairs); i += 2 {
+		buffer.WriteString(EncodeBulkString(pairs[i]))
+		buffer.WriteString(EncodeBulkString(pairs[i+1]))
+	}
+	return buffer.String()
+}
+
+// Map holds alternating keys and values, it is encoded as a RESP3 map when nested in an array or a map
+type Map []interface{}
+
+// EncodeValueMap encodes alternating keys and values of any supported type as a RESP3 map
+func EncodeValueMap(pairs []interface{}) string {
+	return encodeAggregate('%', len(pairs)/2, pairs)
+}
+
+// EncodePush encodes a RESP3 push frame, used for out of band data like pub/sub messages
+func EncodePush(elements []interface{}) string {
+	return encodeAggregate('>', len(elements), elements)
+}
@@ -0,0 +1,21 @@
+package resp
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeRESP3(t *testing.T) {
+	require.Equal(t, "_\r\n", EncodeNull())
+	require.Equal(t, "#t\r\n", EncodeBoolean(true))
+	require.Equal(t, "#f\r\n", EncodeBoolean(false))
+	require.Equal(t, ",1.5\r\n", EncodeDouble(1.5))
+	require.Equal(t, ",-inf\r\n", EncodeDouble(math.Inf(-1)))
+	require.Equal(t, "%2\r\n$1\r\nb\r\n$1\r\n1\r\n$1\r\na\r\n$1\r\n2\r\n", EncodeStringMap([]string{"b", "1", "a", "2"}))
+	require.Equal(t, ">2\r\n$7\r\nmessage\r\n:1\r\n", EncodePush([]interface{}{"message", 1}))
+	require.Equal(t, "%1\r\n$5\r\nproto\r\n:3\r\n", EncodeValueMap([]interface{}{"proto", 3}))
+	require.Equal(t, "*1\r\n%1\r\n$1\r\na\r\n*0\r\n", EncodeArray([]interface{}{Map{"a", []interface{}{}}}))
+	require.Equal(t, "*3\r\n,2\r\n#t\r\n_\r\n", EncodeArray([]interface{}{2.0, true, nil}))
+}
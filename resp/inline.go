@@ -0,0 +1,131 @@
+package resp
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxInlineLength is the longest inline command accepted, longer requests must use the array format
+const MaxInlineLength = 64 * 1024
+
+// parseInline parses a telnet style command, space separated tokens terminated by a newline.
+// It returns the tokens and the number of bytes consumed, an empty line yields no tokens.
+// The newline is searched from the offset from, the bytes before it are known not to hold one.
+func parseInline(buf []byte, from int) ([]string, int, error) {
+	end := bytes.IndexByte(buf[from:], '\n')
+	if end >= 0 {
+		end += from
+	}
+	if end < 0 {
+		if len(buf) > MaxInlineLength {
+			return nil, 0, fmt.Errorf("too big inline request")
+		}
+		return nil, 0, errIncomplete
+	}
+	if end > MaxInlineLength {
+		return nil, 0, fmt.Errorf("too big inline request")
+	}
+	line := strings.TrimSuffix(string(buf[:end]), "\r")
+	args, err := SplitArgs(line)
+	if err != nil {
+		return nil, 0, err
+	}
+	return args, end + 1, nil
+}
+
+// SplitArgs splits an inline command into tokens the same way redis-cli does.
+// Double quoted tokens support the escapes \n \r \t \b \a \\ \" and \xHH, single quoted tokens support \'.
+func SplitArgs(line string) ([]string, error) {
+	args := make([]string, 0)
+	i := 0
+	for {
+		// Skip blanks between tokens
+		for i < len(line) && isInlineSpace(line[i]) {
+			i++
+		}
+		if i == len(line) {
+			return args, nil
+		}
+
+		var current strings.Builder
+		inDoubleQuotes := false
+		inSingleQuotes := false
+		done := false
+		for !done {
+			if i == len(line) {
+				if inDoubleQuotes || inSingleQuotes {
+					return nil, fmt.Errorf("unbalanced quotes in request")
+				}
+				break
+			}
+			char := line[i]
+			switch {
+			case inDoubleQuotes:
+				if char == '\\' && i+3 < len(line) && line[i+1] == 'x' && isHexDigit(line[i+2]) && isHexDigit(line[i+3]) {
+					value, _ := strconv.ParseUint(line[i+2:i+4], 16, 8)
+					current.WriteByte(byte(value))
+					i += 3
+				} else if char == '\\' && i+1 < len(line) {
+					i++
+					switch line[i] {
+					case 'n':
+						current.WriteByte('\n')
+					case 'r':
+						current.WriteByte('\r')
+					case 't':
+						current.WriteByte('\t')
+					case 'b':
+						current.WriteByte('\b')
+					case 'a':
+						current.WriteByte('\a')
+					default:
+						current.WriteByte(line[i])
+					}
+				} else if char == '"' {
+					// The closing quote must be followed by a space or the end of the line
+					if i+1 < len(line) && !isInlineSpace(line[i+1]) {
+						return nil, fmt.Errorf("unbalanced quotes in request")
+					}
+					done = true
+				} else {
+					current.WriteByte(char)
+				}
+			case inSingleQuotes:
+				if char == '\\' && i+1 < len(line) && line[i+1] == '\'' {
+					current.WriteByte('\'')
+					i++
+				} else if char == '\'' {
+					if i+1 < len(line) && !isInlineSpace(line[i+1]) {
+						return nil, fmt.Errorf("unbalanced quotes in request")
+					}
+					done = true
+				} else {
+					current.WriteByte(char)
+				}
+			default:
+				switch char {
+				case ' ', '\t', '\r', '\n':
+					done = true
+				case '"':
+					inDoubleQuotes = true
+				case '\'':
+					inSingleQuotes = true
+				default:
+					current.WriteByte(char)
+				}
+			}
+			i++
+		}
+		args = append(args, current.String())
+	}
+}
+
+func isInlineSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
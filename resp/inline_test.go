@@ -0,0 +1,81 @@
+package resp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReader_Inline(t *testing.T) {
+	reader := NewReader()
+	require.NoError(t, reader.Feed([]byte("PING\r\n\r\nSET key \"hello world\"\n*2\r\n$3\r\nGET\r\n$3\r\nkey\r\nGET ke")))
+
+	expected := []string{
+		"*1\r\n$4\r\nPING\r\n",
+		"*3\r\n$3\r\nSET\r\n$3\r\nkey\r\n$11\r\nhello world\r\n",
+		"*2\r\n$3\r\nGET\r\n$3\r\nkey\r\n",
+	}
+	for _, want := range expected {
+		frame, _, ok, err := reader.Next()
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, want, string(frame))
+	}
+
+	// The last inline command is incomplete until the newline arrives
+	_, _, ok, err := reader.Next()
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, reader.Feed([]byte("y\r\n")))
+	frame, args, ok, err := reader.Next()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "*2\r\n$3\r\nGET\r\n$3\r\nkey\r\n", string(frame))
+	require.Equal(t, []string{"GET", "key"}, args)
+}
+
+func TestReader_InlineTooLong(t *testing.T) {
+	reader := NewReader()
+	require.NoError(t, reader.Feed([]byte("SET key "+strings.Repeat("x", MaxInlineLength))))
+	_, _, _, err := reader.Next()
+	require.Error(t, err)
+}
+
+func TestSplitInlineArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		expected []string
+		wantErr  bool
+	}{
+		{"plain", "SET  key\tvalue ", []string{"SET", "key", "value"}, false},
+		{"empty", "   ", []string{}, false},
+		{"double quotes", `SET "my key" "a\"b\n"`, []string{"SET", "my key", "a\"b\n"}, false},
+		{"hex escape", `SET k "\x00\xff"`, []string{"SET", "k", "\x00\xff"}, false},
+		{"single quotes", `SET 'it\'s' 'a\nb'`, []string{"SET", "it's", `a\nb`}, false},
+		{"empty quoted", `SET k ""`, []string{"SET", "k", ""}, false},
+		{"unbalanced", `SET "key`, nil, true},
+		{"quote followed by text", `SET "key"value`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := SplitArgs(tt.line)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, args)
+		})
+	}
+}
+
+func TestDecode_Inline(t *testing.T) {
+	command, args, err := Decode("SET key value\r\n")
+	require.NoError(t, err)
+	require.Equal(t, "SET", command)
+	require.Equal(t, []string{"key", "value"}, args)
+}
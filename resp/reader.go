@@ -0,0 +1,177 @@
+package resp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// MaxBulkLength is the largest bulk string the decoder accepts, it matches the
+// maximum key length accepted by the store
+const MaxBulkLength = 512 * 1024 * 1024
+
+// maxLengthHeader bounds the digits of an array or bulk string length header
+const maxLengthHeader = 32
+
+// MaxQueryBuffer is the most bytes a Reader keeps for a command which is not complete yet, like the default
+// client-query-buffer-limit of Redis
+const MaxQueryBuffer = 1024 * 1024 * 1024
+
+// errIncomplete is returned by parseFrame when the buffer does not hold a complete frame yet
+var errIncomplete = errors.New("incomplete RESP frame")
+
+// Reader incrementally decodes RESP commands from a connection byte stream.
+// Data read from the connection is appended with Feed, and every complete command
+// is returned by Next in the order it was received. Bytes of a frame which is not
+// complete yet are kept until the rest of the frame arrives, the elements parsed
+// already are not parsed again.
+type Reader struct {
+	buf    []byte
+	parser frameParser
+	// limit is the most bytes buffered, MaxQueryBuffer
+	limit int
+}
+
+// NewReader returns a Reader with an empty buffer
+func NewReader() *Reader {
+	return &Reader{limit: MaxQueryBuffer}
+}
+
+// Feed appends data read from the connection to the pending buffer. It fails when the
+// pending command would grow past MaxQueryBuffer, the reader should be discarded then.
+func (r *Reader) Feed(data []byte) error {
+	if len(r.buf)+len(data) > r.limit {
+		return fmt.Errorf("query buffer limit of %d bytes exceeded", r.limit)
+	}
+	r.buf = append(r.buf, data...)
+	return nil
+}
+
+// Buffered returns the number of bytes waiting for the rest of their frame
+func (r *Reader) Buffered() int {
+	return len(r.buf)
+}
+
+// Reset drops all buffered data
+func (r *Reader) Reset() {
+	r.buf = r.buf[:0]
+	r.parser = frameParser{}
+}
+
+// Next returns the raw bytes and the decoded elements, the command name first, of the
+// next complete command in the buffer. ok is false if the buffer does not hold a complete
+// command yet. The returned frame is only valid until the next call to Feed.
+// Inline commands are returned encoded as a RESP array, so callers only ever see arrays.
+// Once an error is returned the stream can not be resynchronised and the reader
+// should be discarded.
+func (r *Reader) Next() (frame []byte, args []string, ok bool, err error) {
+	for len(r.buf) > 0 {
+		args, n, err := r.parser.parse(r.buf)
+		if errors.Is(err, errIncomplete) {
+			return nil, nil, false, nil
+		}
+		if err != nil {
+			return nil, nil, false, err
+		}
+		r.parser = frameParser{}
+		if r.buf[0] == '*' {
+			frame = r.buf[:n]
+		} else if len(args) > 0 {
+			frame = []byte(EncodeStringArray(args))
+		}
+		if n == len(r.buf) {
+			// Everything is consumed, start from the beginning of the backing array again
+			r.buf = r.buf[:0]
+		} else {
+			r.buf = r.buf[n:]
+		}
+		// Empty inline lines are skipped
+		if frame != nil {
+			return frame, args, true, nil
+		}
+	}
+	return nil, nil, false, nil
+}
+
+// frameParser keeps the progress of a frame which is not complete yet, so a large frame
+// arriving in many reads is parsed once
+type frameParser struct {
+	// pos is where parsing resumes, length is the declared array length, 0 before its header is parsed
+	pos    int
+	length int
+	args   []string
+}
+
+// parseFrame parses a single command from the start of buf, either a RESP array of bulk
+// strings or an inline command. It returns the decoded elements and the number of bytes consumed.
+// errIncomplete is returned if buf ends before the frame does.
+func parseFrame(buf []byte) ([]string, int, error) {
+	var p frameParser
+	return p.parse(buf)
+}
+
+// parse resumes parsing the frame at the start of buf, which holds the bytes seen by the previous calls
+func (p *frameParser) parse(buf []byte) ([]string, int, error) {
+	if buf[0] != '*' {
+		args, n, err := parseInline(buf, p.pos)
+		if errors.Is(err, errIncomplete) {
+			p.pos = len(buf)
+		}
+		return args, n, err
+	}
+	if p.length == 0 {
+		arrayLength, pos, err := parseLength(buf, 1)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid array length: %w", err)
+		}
+		if arrayLength < 1 {
+			return nil, 0, fmt.Errorf("invalid command: array length must be at least 1")
+		}
+		// Do not trust the declared length for the allocation
+		p.length, p.pos, p.args = arrayLength, pos, make([]string, 0, min(arrayLength, 1024))
+	}
+
+	for len(p.args) < p.length {
+		if p.pos >= len(buf) {
+			return nil, 0, errIncomplete
+		}
+		if buf[p.pos] != '$' {
+			return nil, 0, fmt.Errorf("expected bulk string prefix '$', found: %q", buf[p.pos])
+		}
+		bulkLength, next, err := parseLength(buf, p.pos+1)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid bulk string length: %w", err)
+		}
+		if bulkLength < 0 || bulkLength > MaxBulkLength {
+			return nil, 0, fmt.Errorf("invalid bulk string length: %d", bulkLength)
+		}
+		end := next + bulkLength
+		if end+2 > len(buf) {
+			return nil, 0, errIncomplete
+		}
+		if buf[end] != '\r' || buf[end+1] != '\n' {
+			return nil, 0, fmt.Errorf("bulk string length mismatch")
+		}
+		p.args = append(p.args, string(buf[next:end]))
+		p.pos = end + 2
+	}
+	return p.args, p.pos, nil
+}
+
+// parseLength parses the integer terminated by CRLF which starts at buf[start].
+// It returns the integer and the position right after the CRLF.
+func parseLength(buf []byte, start int) (int, int, error) {
+	end := bytes.Index(buf[start:], []byte("\r\n"))
+	if end < 0 {
+		if len(buf)-start > maxLengthHeader {
+			return 0, 0, fmt.Errorf("length header too long")
+		}
+		return 0, 0, errIncomplete
+	}
+	n, err := strconv.Atoi(string(buf[start : start+end]))
+	if err != nil {
+		return 0, 0, err
+	}
+	return n, start + end + 2, nil
+}
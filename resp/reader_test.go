@@ -0,0 +1,123 @@
+package resp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReader_Pipelined(t *testing.T) {
+	reader := NewReader()
+	require.NoError(t, reader.Feed([]byte("*1\r\n$4\r\nPING\r\n*3\r\n$3\r\nSET\r\n$1\r\nk\r\n$1\r\nv\r\n*2\r\n$3\r\nGET\r\n$1\r\nk\r\n")))
+
+	expected := []struct {
+		frame string
+		args  []string
+	}{
+		{"*1\r\n$4\r\nPING\r\n", []string{"PING"}},
+		{"*3\r\n$3\r\nSET\r\n$1\r\nk\r\n$1\r\nv\r\n", []string{"SET", "k", "v"}},
+		{"*2\r\n$3\r\nGET\r\n$1\r\nk\r\n", []string{"GET", "k"}},
+	}
+	for _, want := range expected {
+		frame, args, ok, err := reader.Next()
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, want.frame, string(frame))
+		require.Equal(t, want.args, args)
+	}
+
+	_, _, ok, err := reader.Next()
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Equal(t, 0, reader.Buffered())
+}
+
+func TestReader_PartialFrame(t *testing.T) {
+	value := strings.Repeat("x", 4096)
+	input := "*3\r\n$4\r\nMSET\r\n$3\r\nkey\r\n$4096\r\n" + value + "\r\n"
+
+	// Feed the frame one chunk at a time, it must only be returned once complete
+	reader := NewReader()
+	for i := 0; i < len(input); i += 100 {
+		_, _, ok, err := reader.Next()
+		require.NoError(t, err)
+		require.False(t, ok)
+		require.NoError(t, reader.Feed([]byte(input[i:min(i+100, len(input))])))
+	}
+
+	frame, args, ok, err := reader.Next()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, input, string(frame))
+	require.Equal(t, []string{"MSET", "key", value}, args)
+}
+
+func TestReader_ResumesParsing(t *testing.T) {
+	reader := NewReader()
+	require.NoError(t, reader.Feed([]byte("*3\r\n$3\r\nSET\r\n$1\r\nk\r\n$5\r\nval")))
+	_, _, ok, err := reader.Next()
+	require.NoError(t, err)
+	require.False(t, ok)
+	// The complete elements are kept, parsing resumes at the incomplete one
+	require.Equal(t, []string{"SET", "k"}, reader.parser.args)
+	require.Equal(t, len("*3\r\n$3\r\nSET\r\n$1\r\nk\r\n"), reader.parser.pos)
+
+	require.NoError(t, reader.Feed([]byte("ue\r\nPI")))
+	_, args, ok, err := reader.Next()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []string{"SET", "k", "value"}, args)
+
+	// The inline command is not searched for its newline again
+	_, _, ok, err = reader.Next()
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Equal(t, 2, reader.parser.pos)
+	require.NoError(t, reader.Feed([]byte("NG\r\n")))
+	_, args, ok, err = reader.Next()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []string{"PING"}, args)
+}
+
+func TestReader_QueryBufferLimit(t *testing.T) {
+	reader := NewReader()
+	reader.limit = 8
+	require.NoError(t, reader.Feed([]byte("*1\r\n$10")))
+	require.Error(t, reader.Feed([]byte("\r\n")))
+	require.Equal(t, 7, reader.Buffered())
+}
+
+func TestReader_InvalidFrame(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"unbalanced inline quotes", "SET \"key value\r\n"},
+		{"empty array", "*0\r\n"},
+		{"missing bulk prefix", "*1\r\n:1\r\n"},
+		{"negative bulk length", "*1\r\n$-1\r\n"},
+		{"bulk length mismatch", "*1\r\n$2\r\nGET\r\n"},
+		{"length header too long", "*" + strings.Repeat("1", 64)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := NewReader()
+			require.NoError(t, reader.Feed([]byte(tt.input)))
+			_, _, _, err := reader.Next()
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	command, args, err := Decode("*3\r\n$3\r\nSET\r\n$3\r\nkey\r\n$5\r\nvalue\r\n")
+	require.NoError(t, err)
+	require.Equal(t, "SET", command)
+	require.Equal(t, []string{"key", "value"}, args)
+
+	_, _, err = Decode("*3\r\n$3\r\nSET\r\n$3\r\nkey\r\n")
+	require.Error(t, err)
+}
@@ -0,0 +1,74 @@
+package resp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReadReply reads exactly one complete RESP reply from r and returns its raw encoding.
+// Bulk strings are read by their declared length, so replies containing CRLF are returned whole.
+func ReadReply(r *bufio.Reader) (string, error) {
+	var builder strings.Builder
+	if err := readReply(r, &builder); err != nil {
+		return "", err
+	}
+	return builder.String(), nil
+}
+
+func readReply(r *bufio.Reader, builder *strings.Builder) error {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if len(line) < 3 || !strings.HasSuffix(line, "\r\n") {
+		return fmt.Errorf("invalid RESP reply line: %q", line)
+	}
+	builder.WriteString(line)
+
+	switch line[0] {
+	case '+', '-', ':', '_', '#', ',', '(':
+		return nil
+	case '$', '=', '!':
+		length, err := strconv.Atoi(line[1 : len(line)-2])
+		if err != nil {
+			return fmt.Errorf("invalid bulk string length: %v", err)
+		}
+		if length < 0 {
+			// Null bulk string
+			return nil
+		}
+		if length > MaxBulkLength {
+			return fmt.Errorf("invalid bulk string length: %d", length)
+		}
+		data := make([]byte, length+2)
+		if _, err = io.ReadFull(r, data); err != nil {
+			return err
+		}
+		builder.Write(data)
+		return nil
+	case '*', '~', '>', '%', '|':
+		length, err := strconv.Atoi(line[1 : len(line)-2])
+		if err != nil {
+			return fmt.Errorf("invalid aggregate length: %v", err)
+		}
+		if line[0] == '%' || line[0] == '|' {
+			// Maps and attributes hold a key and a value per entry
+			length *= 2
+		}
+		for i := 0; i < length; i++ {
+			if err = readReply(r, builder); err != nil {
+				return err
+			}
+		}
+		if line[0] == '|' {
+			// Attributes are followed by the reply they describe
+			return readReply(r, builder)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown RESP reply type: %q", line[0])
+	}
+}
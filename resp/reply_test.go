@@ -0,0 +1,40 @@
+package resp
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadReply(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply string
+	}{
+		{"simple string", "+OK\r\n"},
+		{"error", "-ERR invalid\r\n"},
+		{"integer", ":42\r\n"},
+		{"bulk string with CRLF", "$7\r\na\r\nb\r\nc\r\n"},
+		{"null bulk string", "$-1\r\n"},
+		{"nested array", "*2\r\n*2\r\n$1\r\na\r\n:1\r\n$2\r\n\r\n\r\n"},
+		{"resp3 map", "%2\r\n$1\r\na\r\n,1.5\r\n$1\r\nb\r\n_\r\n"},
+		{"resp3 push", ">3\r\n$7\r\nmessage\r\n$2\r\nch\r\n$3\r\nmsg\r\n"},
+		{"resp3 boolean", "#t\r\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A trailing reply must be left unread
+			reader := bufio.NewReader(strings.NewReader(tt.reply + "+NEXT\r\n"))
+			got, err := ReadReply(reader)
+			require.NoError(t, err)
+			require.Equal(t, tt.reply, got)
+
+			next, err := ReadReply(reader)
+			require.NoError(t, err)
+			require.Equal(t, "+NEXT\r\n", next)
+		})
+	}
+}
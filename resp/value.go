@@ -0,0 +1,121 @@
+package resp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReplyError is an error reply sent by the server
+type ReplyError string
+
+func (e ReplyError) Error() string {
+	return string(e)
+}
+
+// ParseReply reads exactly one RESP2 or RESP3 reply from r and converts it to Go values.
+// Simple, bulk and verbatim strings become string, integers int64, doubles float64, booleans bool,
+// nulls nil, arrays, sets and pushes []interface{}, maps map[string]interface{} and error replies ReplyError.
+// Big numbers are kept as strings. The returned error is only set when the reply can not be read.
+func ParseReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || !strings.HasSuffix(line, "\r\n") {
+		return nil, fmt.Errorf("invalid RESP reply line: %q", line)
+	}
+	payload := line[1 : len(line)-2]
+
+	switch line[0] {
+	case '+', '(':
+		return payload, nil
+	case '-':
+		return ReplyError(payload), nil
+	case '_':
+		return nil, nil
+	case ':':
+		n, err := strconv.ParseInt(payload, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer reply: %v", err)
+		}
+		return n, nil
+	case ',':
+		f, err := strconv.ParseFloat(payload, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid double reply: %v", err)
+		}
+		return f, nil
+	case '#':
+		return payload == "t", nil
+	case '$', '=', '!':
+		length, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bulk string length: %v", err)
+		}
+		if length < 0 {
+			return nil, nil
+		}
+		if length > MaxBulkLength {
+			return nil, fmt.Errorf("invalid bulk string length: %d", length)
+		}
+		data := make([]byte, length+2)
+		if _, err = io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		s := string(data[:length])
+		switch line[0] {
+		case '=':
+			// Verbatim strings start with their format, txt: or mkd:
+			if len(s) >= 4 && s[3] == ':' {
+				s = s[4:]
+			}
+		case '!':
+			return ReplyError(s), nil
+		}
+		return s, nil
+	case '*', '~', '>':
+		length, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, fmt.Errorf("invalid aggregate length: %v", err)
+		}
+		if length < 0 {
+			return nil, nil
+		}
+		elements := make([]interface{}, 0, length)
+		for i := 0; i < length; i++ {
+			element, err := ParseReply(r)
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, element)
+		}
+		return elements, nil
+	case '%', '|':
+		length, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, fmt.Errorf("invalid aggregate length: %v", err)
+		}
+		m := make(map[string]interface{}, length)
+		for i := 0; i < length; i++ {
+			key, err := ParseReply(r)
+			if err != nil {
+				return nil, err
+			}
+			value, err := ParseReply(r)
+			if err != nil {
+				return nil, err
+			}
+			m[fmt.Sprint(key)] = value
+		}
+		if line[0] == '|' {
+			// Attributes only describe the reply which follows them
+			return ParseReply(r)
+		}
+		return m, nil
+	default:
+		return nil, fmt.Errorf("unknown RESP reply type: %q", line[0])
+	}
+}
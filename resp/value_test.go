@@ -0,0 +1,48 @@
+package resp
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReply(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply string
+		want  interface{}
+	}{
+		{"simple string", "+OK\r\n", "OK"},
+		{"error", "-ERR invalid\r\n", ReplyError("ERR invalid")},
+		{"integer", ":42\r\n", int64(42)},
+		{"bulk string with CRLF", "$4\r\na\r\nb\r\n", "a\r\nb"},
+		{"null bulk string", "$-1\r\n", nil},
+		{"resp3 null", "_\r\n", nil},
+		{"double", ",1.5\r\n", 1.5},
+		{"boolean", "#f\r\n", false},
+		{"verbatim string", "=7\r\ntxt:abc\r\n", "abc"},
+		{"nested array", "*2\r\n*1\r\n:1\r\n$1\r\na\r\n", []interface{}{[]interface{}{int64(1)}, "a"}},
+		{"map", "%2\r\n$1\r\na\r\n,1.5\r\n$1\r\nb\r\n_\r\n", map[string]interface{}{"a": 1.5, "b": nil}},
+		{"push", ">2\r\n$7\r\nmessage\r\n$2\r\nch\r\n", []interface{}{"message", "ch"}},
+		{"attribute", "|1\r\n$3\r\nttl\r\n:3\r\n+OK\r\n", "OK"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A trailing reply must be left unread
+			reader := bufio.NewReader(strings.NewReader(tt.reply + "+NEXT\r\n"))
+			got, err := ParseReply(reader)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+
+			next, err := ParseReply(reader)
+			require.NoError(t, err)
+			require.Equal(t, "NEXT", next)
+		})
+	}
+
+	_, err := ParseReply(bufio.NewReader(strings.NewReader("?1\r\n")))
+	require.Error(t, err)
+}
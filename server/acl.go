@@ -0,0 +1,252 @@
+package server
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/panjf2000/gnet/v2"
+	"treds/acl"
+	"treds/commands"
+	"treds/resp"
+)
+
+const ACLCommandName = "ACL"
+
+func RegisterACLCommand(r ServerCommandRegistry) {
+	r.Add(&ServerCommandRegistration{
+		Name:       ACLCommandName,
+		Execute:    executeACL(),
+		Unlocked:   true,
+		Categories: []string{acl.CategoryAdmin},
+		Spec: commands.Spec{
+			Summary: "Manages the users, ACL SETUSER, DELUSER, GETUSER, LIST and WHOAMI",
+			Group:   commands.GroupServer,
+			Args: []commands.Arg{
+				{Name: "subcommand", Type: commands.ArgString},
+				{Name: "arg", Type: commands.ArgString, Optional: true, Multiple: true},
+			},
+		},
+		Subcommands: map[string][]string{
+			"setuser": nil,
+			"deluser": nil,
+			"getuser": nil,
+			"list":    nil,
+			"whoami":  nil,
+		},
+	})
+}
+
+// executeACL runs ACL SETUSER|DELUSER|GETUSER|LIST|WHOAMI.
+// SETUSER and DELUSER go through Raft so every node holds the same users.
+func executeACL() ExecutionHook {
+	return func(inp string, ts *Server, c gnet.Conn) gnet.Action {
+		_, args, err := parseCommand(inp)
+		if err != nil {
+			ts.RespondErr(c, err)
+			return gnet.None
+		}
+		if len(args) == 0 {
+			ts.RespondErr(c, fmt.Errorf("invalid number of arguments"))
+			return gnet.None
+		}
+
+		var res string
+		switch strings.ToUpper(args[0]) {
+		case "WHOAMI":
+			res = resp.EncodeBulkString(getClientConn(c).user)
+		case "LIST":
+			res = resp.EncodeStringArray(ts.acl.List())
+		case "GETUSER":
+			if len(args) != 2 {
+				ts.RespondErr(c, fmt.Errorf("invalid number of arguments"))
+				return gnet.None
+			}
+			res = encodeACLUser(c, args[1], ts.acl)
+		case "SETUSER", "DELUSER":
+			return ts.applyACLChange(inp, args, c)
+		default:
+			ts.RespondErr(c, fmt.Errorf("unknown subcommand '%s'", args[0]))
+			return gnet.None
+		}
+		_, errConn := c.Write([]byte(res))
+		if errConn != nil {
+			logger.Error("Error occurred writing to connection", "error", errConn)
+		}
+		return gnet.None
+	}
+}
+
+func (ts *Server) applyACLChange(inp string, args []string, c gnet.Conn) gnet.Action {
+	if len(args) < 2 {
+		ts.RespondErr(c, fmt.Errorf("invalid number of arguments"))
+		return gnet.None
+	}
+	if strings.ToUpper(args[0]) == "SETUSER" {
+		// Only the hashes of the passwords are sent to the leader and stored in the Raft log
+		inp = resp.EncodeStringArray(append([]string{ACLCommandName, args[0], args[1]}, acl.HashPasswords(args[2:])...))
+	}
+
+	// Process this command on leader
+	forwarded, rspFwd, err := ts.ForwardRequest([]byte(inp))
+	if err != nil {
+		ts.RespondErr(c, err)
+		return gnet.None
+	}
+
+	// If request is forwarded we just send back the answer from the leader to the client
+	// and stop processing
+	if forwarded {
+		_, errConn := c.Write([]byte(rspFwd))
+		if errConn != nil {
+			logger.Error("Error occurred writing to connection", "error", errConn)
+		}
+		return gnet.None
+	}
+
+	// Validation need to be done before raft Apply so an error is returned before persisting
+	if strings.ToUpper(args[0]) == "SETUSER" {
+		if err = acl.ValidateRules(args[1], args[2:]); err != nil {
+			ts.RespondErr(c, err)
+			return gnet.None
+		}
+	}
+
+	future := ts.apply([]byte(inp))
+	if err = future.Error(); err != nil {
+		ts.RespondErr(c, err)
+		return gnet.None
+	}
+
+	switch rsp := future.Response().(type) {
+	case error:
+		ts.RespondErr(c, rsp)
+	default:
+		_, errConn := c.Write([]byte(rsp.(string)))
+		if errConn != nil {
+			logger.Error("Error occurred writing to connection", "error", errConn)
+		}
+	}
+	return gnet.None
+}
+
+// applyACL applies a replicated ACL SETUSER or DELUSER, it is called by the FSM on every node
+func applyACL(a *acl.ACL, args []string) interface{} {
+	if len(args) < 2 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+	switch strings.ToUpper(args[0]) {
+	case "SETUSER":
+		if err := a.SetUser(args[1], args[2:]); err != nil {
+			return err
+		}
+		return resp.EncodeSimpleString("OK")
+	case "DELUSER":
+		deleted, err := a.DelUser(args[1:])
+		if err != nil {
+			return err
+		}
+		return resp.EncodeInteger(deleted)
+	}
+	return fmt.Errorf("unknown subcommand '%s'", args[0])
+}
+
+func encodeACLUser(c gnet.Conn, name string, a *acl.ACL) string {
+	protocol := getClientConn(c).protocol
+	user, ok := a.User(name)
+	if !ok {
+		if protocol == resp.RESP3 {
+			return resp.EncodeNull()
+		}
+		return resp.EncodeArray(nil)
+	}
+	fields := []interface{}{
+		"flags", toInterfaces(user.Flags()),
+		"passwords", toInterfaces(user.Passwords),
+		"commands", user.CommandRules(),
+		"keys", user.KeyRules(),
+	}
+	if protocol == resp.RESP3 {
+		return resp.EncodeValueMap(fields)
+	}
+	return resp.EncodeArray(fields)
+}
+
+func toInterfaces(values []string) []interface{} {
+	res := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		res = append(res, v)
+	}
+	return res
+}
+
+// authorize checks that the user of c may run command with args, before it is dispatched.
+// AUTH, HELLO and CLUSTERAUTH are always allowed so clients can authenticate.
+func (ts *Server) authorize(command string, args []string, c gnet.Conn) error {
+	client := getClientConn(c)
+	if client.internal {
+		// Requests forwarded by other nodes were authorized by the node the client is connected to
+		return nil
+	}
+	name := strings.ToUpper(command)
+	if name == AuthCommandName || name == HelloCommandName || name == ClusterAuthCommandName {
+		return nil
+	}
+	return ts.authorizeUser(client.user, command, args)
+}
+
+// authorizeUser checks that username may run command with args
+func (ts *Server) authorizeUser(username, command string, args []string) error {
+	name := strings.ToUpper(command)
+	user, ok := ts.acl.User(username)
+	if username == "" || !ok || !user.Enabled {
+		return fmt.Errorf("NOAUTH Authentication required.")
+	}
+
+	subcommand := ""
+	var categories []string
+	var storeCommand *commands.CommandRegistration
+	if reg, err := ts.tredsServerCommandRegistry.Retrieve(name); err == nil {
+		categories = reg.Categories
+		if reg.Subcommands != nil && len(args) > 0 {
+			subcommand = strings.ToLower(args[0])
+			categories = append(slices.Clone(categories), reg.Subcommands[subcommand]...)
+		}
+	} else if storeCommand, err = ts.tredsCommandRegistry.Retrieve(name); err == nil {
+		categories = []string{acl.CategoryRead}
+		if storeCommand.IsWrite() {
+			categories = []string{acl.CategoryWrite}
+		}
+	} else {
+		// Unknown commands are reported by the dispatch
+		return nil
+	}
+
+	if !user.CanRun(name, subcommand, categories) {
+		full := strings.ToLower(name)
+		if subcommand != "" {
+			full += "|" + subcommand
+		}
+		return fmt.Errorf("NOPERM User %s has no permissions to run the '%s' command", user.Name, full)
+	}
+
+	if storeCommand == nil {
+		return nil
+	}
+	// The keys are found from the spec of the command
+	if storeCommand.Spec.AllKeys && !user.AllKeys {
+		return fmt.Errorf("NOPERM User %s has no permissions to access all keys", user.Name)
+	}
+	keys, prefixes := storeCommand.Spec.KeyArgs(args)
+	for _, key := range keys {
+		if !user.CanAccessKey(key) {
+			return fmt.Errorf("NOPERM No permissions to access a key")
+		}
+	}
+	for _, prefix := range prefixes {
+		if !user.CanAccessPrefix(prefix) {
+			return fmt.Errorf("NOPERM No permissions to access a key prefix")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"treds/acl"
+)
+
+func TestCheckForward(t *testing.T) {
+	ts := &Server{acl: acl.New()}
+	require.NoError(t, ts.checkForward())
+
+	// The leader would run the writes of alice as the default user
+	require.NoError(t, ts.acl.SetUser("alice", []string{"on", ">pw", "allkeys", "+@write"}))
+	require.Error(t, ts.checkForward())
+
+	ts.SetClusterSecret("secret")
+	require.NoError(t, ts.checkForward())
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"crypto/subtle"
+	"fmt"
+
+	"github.com/panjf2000/gnet/v2"
+	"treds/acl"
+	"treds/commands"
+	"treds/resp"
+)
+
+const AuthCommandName = "AUTH"
+
+// ClusterAuthCommandName authenticates a connection opened by another node to forward requests to the leader
+const ClusterAuthCommandName = "CLUSTERAUTH"
+
+func RegisterAuthCommand(r ServerCommandRegistry) {
+	r.Add(&ServerCommandRegistration{
+		Name:       AuthCommandName,
+		Execute:    executeAuth(),
+		Categories: []string{acl.CategoryConnection},
+		Spec: commands.Spec{
+			Summary: "Authenticates the connection",
+			Group:   commands.GroupConnection,
+			Args: []commands.Arg{
+				{Name: "username", Type: commands.ArgString, Optional: true},
+				{Name: "password", Type: commands.ArgString},
+			},
+		},
+	})
+}
+
+func RegisterClusterAuthCommand(r ServerCommandRegistry) {
+	r.Add(&ServerCommandRegistration{
+		Name:       ClusterAuthCommandName,
+		Execute:    executeClusterAuth(),
+		Categories: []string{acl.CategoryConnection},
+		Spec: commands.Spec{
+			Summary: "Marks the connection as opened by another node of the cluster",
+			Group:   commands.GroupConnection,
+			Args: []commands.Arg{
+				{Name: "secret", Type: commands.ArgString},
+			},
+		},
+	})
+}
+
+// executeAuth authenticates the connection, AUTH [username] password
+// Without a username the default user is used.
+func executeAuth() ExecutionHook {
+	return func(inp string, ts *Server, c gnet.Conn) gnet.Action {
+		_, args, err := parseCommand(inp)
+		if err != nil {
+			ts.RespondErr(c, err)
+			return gnet.None
+		}
+
+		username := acl.DefaultUser
+		var password string
+		switch len(args) {
+		case 1:
+			password = args[0]
+		case 2:
+			username, password = args[0], args[1]
+		default:
+			ts.RespondErr(c, fmt.Errorf("invalid number of arguments"))
+			return gnet.None
+		}
+
+		if _, err = ts.acl.Authenticate(username, password); err != nil {
+			ts.RespondErr(c, err)
+			return gnet.None
+		}
+		getClientConn(c).user = username
+
+		_, errConn := c.Write([]byte(resp.EncodeSimpleString("OK")))
+		if errConn != nil {
+			logger.Error("Error occurred writing to connection", "error", errConn)
+		}
+		return gnet.None
+	}
+}
+
+// executeClusterAuth marks the connection as opened by another node of the cluster, CLUSTERAUTH secret
+// Such connections skip the ACL checks as requests were checked by the node which forwarded them.
+func executeClusterAuth() ExecutionHook {
+	return func(inp string, ts *Server, c gnet.Conn) gnet.Action {
+		_, args, err := parseCommand(inp)
+		if err != nil {
+			ts.RespondErr(c, err)
+			return gnet.None
+		}
+		if len(args) != 1 {
+			ts.RespondErr(c, fmt.Errorf("invalid number of arguments"))
+			return gnet.None
+		}
+		if ts.clusterSecret == "" || subtle.ConstantTimeCompare([]byte(args[0]), []byte(ts.clusterSecret)) != 1 {
+			ts.RespondErr(c, fmt.Errorf("WRONGPASS invalid cluster secret"))
+			return gnet.None
+		}
+		getClientConn(c).internal = true
+
+		_, errConn := c.Write([]byte(resp.EncodeSimpleString("OK")))
+		if errConn != nil {
+			logger.Error("Error occurred writing to connection", "error", errConn)
+		}
+		return gnet.None
+	}
+}
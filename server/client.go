@@ -0,0 +1,86 @@
+package server
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/panjf2000/gnet/v2"
+	"treds/resp"
+)
+
+// nextClientID numbers connections in the order they are accepted, IDs are never reused
+var nextClientID atomic.Uint64
+
+// clientConn is the per connection state stored in the gnet connection context
+type clientConn struct {
+	// id identifies the connection in the server state, see connID
+	id uint64
+	// addr is the address of the client, unix socket clients have none
+	addr string
+	// localAddr is the listener the client connected to
+	localAddr string
+	reader    *resp.Reader
+	// proxied is set until the PROXY header of a connection relayed by the TLS listener is read,
+	// proxyHeader buffers it
+	proxied     bool
+	proxyHeader []byte
+	// protocol is the RESP version negotiated with HELLO
+	protocol int
+	// user is the ACL user the connection is authenticated as, empty until AUTH succeeds
+	user string
+	// internal is set on connections opened by other nodes of the cluster, see CLUSTERAUTH
+	internal bool
+	// name is set by CLIENT SETNAME
+	name    string
+	created time.Time
+	// lastInteraction and lastCommand are updated by the event loop of the connection
+	// while CLIENT LIST reads them from any loop
+	lastInteraction atomic.Int64
+	lastCommand     atomic.Value
+	// pubsub is set while the client is subscribed to a channel, see checkOutputBuffer
+	pubsub atomic.Bool
+	// monitor is set once the client ran MONITOR
+	monitor atomic.Bool
+	// softLimitSince is when the output buffer went over the soft limit
+	softLimitSince time.Time
+}
+
+func newClientConn(c gnet.Conn) *clientConn {
+	client := &clientConn{
+		id:       nextClientID.Add(1),
+		reader:   resp.NewReader(),
+		protocol: resp.RESP2,
+		created:  time.Now(),
+	}
+	if remote := c.RemoteAddr(); remote != nil && remote.Network() != "unix" {
+		client.addr = remote.String()
+	}
+	if local := c.LocalAddr(); local != nil {
+		client.localAddr = local.String()
+	}
+	client.touch("")
+	return client
+}
+
+// touch records that the client sent command
+func (client *clientConn) touch(command string) {
+	client.lastInteraction.Store(time.Now().UnixNano())
+	client.lastCommand.Store(command)
+}
+
+func getClientConn(c gnet.Conn) *clientConn {
+	return c.Context().(*clientConn)
+}
+
+// connID returns the key of c in the connection, transaction and subscription maps
+func connID(c gnet.Conn) uint64 {
+	return getClientConn(c).id
+}
+
+// encodePubSub encodes a pub/sub frame for c, RESP3 clients receive it as a push
+func encodePubSub(c gnet.Conn, elements []interface{}) string {
+	if getClientConn(c).protocol == resp.RESP3 {
+		return resp.EncodePush(elements)
+	}
+	return resp.EncodeArray(elements)
+}
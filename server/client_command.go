@@ -0,0 +1,247 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/panjf2000/gnet/v2"
+	"treds/acl"
+	"treds/commands"
+	"treds/resp"
+	"treds/store"
+)
+
+const ClientCommandName = "CLIENT"
+
+func RegisterClientCommand(r ServerCommandRegistry) {
+	r.Add(&ServerCommandRegistration{
+		Name:       ClientCommandName,
+		Execute:    executeClient(),
+		Categories: []string{acl.CategoryConnection},
+		Spec: commands.Spec{
+			Summary: "Manages the connections, CLIENT ID, SETNAME, GETNAME, LIST and KILL",
+			Group:   commands.GroupConnection,
+			Args: []commands.Arg{
+				{Name: "subcommand", Type: commands.ArgString},
+				{Name: "arg", Type: commands.ArgString, Optional: true, Multiple: true},
+			},
+		},
+		Subcommands: map[string][]string{
+			"id":      nil,
+			"setname": nil,
+			"getname": nil,
+			"list":    {acl.CategoryAdmin},
+			"kill":    {acl.CategoryAdmin},
+		},
+	})
+}
+
+// executeClient runs CLIENT ID|SETNAME|GETNAME|LIST|KILL.
+// Connections are local to a node, so none of them is forwarded to the leader.
+func executeClient() ExecutionHook {
+	return func(inp string, ts *Server, c gnet.Conn) gnet.Action {
+		_, args, err := parseCommand(inp)
+		if err != nil {
+			ts.RespondErr(c, err)
+			return gnet.None
+		}
+		if len(args) == 0 {
+			ts.RespondErr(c, fmt.Errorf("invalid number of arguments"))
+			return gnet.None
+		}
+
+		client := getClientConn(c)
+		var res string
+		switch strings.ToUpper(args[0]) {
+		case "ID":
+			res = resp.EncodeInteger(int(client.id))
+		case "SETNAME":
+			if len(args) != 2 {
+				ts.RespondErr(c, fmt.Errorf("invalid number of arguments"))
+				return gnet.None
+			}
+			if err = validateClientName(args[1]); err != nil {
+				ts.RespondErr(c, err)
+				return gnet.None
+			}
+			client.name = args[1]
+			res = resp.EncodeSimpleString("OK")
+		case "GETNAME":
+			switch {
+			case client.name != "":
+				res = resp.EncodeBulkString(client.name)
+			case client.protocol == resp.RESP3:
+				res = resp.EncodeNull()
+			default:
+				res = resp.EncodeBulkString(store.NilResp)
+			}
+		case "LIST":
+			res, err = ts.clientList(args[1:])
+		case "KILL":
+			res, err = ts.clientKill(args[1:], c)
+		default:
+			err = fmt.Errorf("unknown subcommand '%s'", args[0])
+		}
+		if err != nil {
+			ts.RespondErr(c, err)
+			return gnet.None
+		}
+		_, errConn := c.Write([]byte(res))
+		if errConn != nil {
+			logger.Error("Error occurred writing to connection", "error", errConn)
+		}
+		return gnet.None
+	}
+}
+
+func validateClientName(name string) error {
+	for _, r := range name {
+		if r <= ' ' || r > '~' {
+			return fmt.Errorf("Client names cannot contain spaces, newlines or special characters.")
+		}
+	}
+	return nil
+}
+
+// clientList describes the connections of this node, one line each, CLIENT LIST [ID id [id ...]]
+func (ts *Server) clientList(args []string) (string, error) {
+	var ids map[uint64]struct{}
+	if len(args) > 0 {
+		if !strings.EqualFold(args[0], "ID") || len(args) == 1 {
+			return "", fmt.Errorf("syntax error")
+		}
+		ids = make(map[uint64]struct{})
+		for _, arg := range args[1:] {
+			id, err := strconv.ParseUint(arg, 10, 64)
+			if err != nil {
+				return "", fmt.Errorf("Invalid client ID")
+			}
+			ids[id] = struct{}{}
+		}
+	}
+
+	conns := make([]gnet.Conn, 0, len(ts.connectionMap))
+	for id, conn := range ts.connectionMap {
+		if _, ok := ids[id]; ids == nil || ok {
+			conns = append(conns, conn)
+		}
+	}
+	sort.Slice(conns, func(i, j int) bool { return connID(conns[i]) < connID(conns[j]) })
+
+	var sb strings.Builder
+	now := time.Now()
+	for _, conn := range conns {
+		sb.WriteString(ts.describeClient(conn, now))
+		sb.WriteString("\n")
+	}
+	return resp.EncodeBulkString(sb.String()), nil
+}
+
+// describeClient formats the connection the way CLIENT LIST shows it
+func (ts *Server) describeClient(c gnet.Conn, now time.Time) string {
+	client := getClientConn(c)
+	id := client.id
+
+	flags := "N"
+	multi := -1
+	if queued, ok := ts.clientTransaction[id]; ok {
+		flags = "x"
+		multi = len(queued)
+	}
+	lastInteraction := time.Unix(0, client.lastInteraction.Load())
+	lastCommand, _ := client.lastCommand.Load().(string)
+	if lastCommand == "" {
+		lastCommand = "NULL"
+	}
+
+	return fmt.Sprintf("id=%d addr=%s laddr=%s name=%s age=%d idle=%d flags=%s sub=%d multi=%d cmd=%s user=%s resp=%d",
+		id, client.addr, client.localAddr, client.name,
+		int(now.Sub(client.created).Seconds()), int(now.Sub(lastInteraction).Seconds()),
+		flags, len(ts.connectionSubscription[id]), multi, lastCommand, client.user, client.protocol)
+}
+
+// clientKill closes connections of this node.
+// CLIENT KILL addr closes the client with that address and replies OK.
+// CLIENT KILL [ID id] [ADDR addr] [LADDR addr] [NAME name] [USER user] [SKIPME yes|no] closes every client
+// matching all filters and replies how many were closed. The calling client is skipped unless SKIPME is no.
+func (ts *Server) clientKill(args []string, c gnet.Conn) (string, error) {
+	if len(args) == 0 {
+		return "", fmt.Errorf("invalid number of arguments")
+	}
+	if len(args) == 1 {
+		for _, conn := range ts.connectionMap {
+			if getClientConn(conn).addr == args[0] {
+				ts.killClient(conn)
+				return resp.EncodeSimpleString("OK"), nil
+			}
+		}
+		return "", fmt.Errorf("No such client")
+	}
+	if len(args)%2 != 0 {
+		return "", fmt.Errorf("syntax error")
+	}
+
+	var filters []func(*clientConn) bool
+	skipMe := true
+	for i := 0; i < len(args); i += 2 {
+		value := args[i+1]
+		switch strings.ToUpper(args[i]) {
+		case "ID":
+			id, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return "", fmt.Errorf("client-id should be greater than 0")
+			}
+			filters = append(filters, func(client *clientConn) bool { return client.id == id })
+		case "ADDR":
+			filters = append(filters, func(client *clientConn) bool { return client.addr == value })
+		case "LADDR":
+			filters = append(filters, func(client *clientConn) bool { return client.localAddr == value })
+		case "NAME":
+			filters = append(filters, func(client *clientConn) bool { return client.name == value })
+		case "USER":
+			filters = append(filters, func(client *clientConn) bool { return client.user == value })
+		case "SKIPME":
+			switch strings.ToLower(value) {
+			case "yes":
+				skipMe = true
+			case "no":
+				skipMe = false
+			default:
+				return "", fmt.Errorf("syntax error")
+			}
+		default:
+			return "", fmt.Errorf("syntax error")
+		}
+	}
+
+	killed := 0
+	for _, conn := range ts.connectionMap {
+		client := getClientConn(conn)
+		if skipMe && client.id == connID(c) {
+			continue
+		}
+		matches := true
+		for _, filter := range filters {
+			if !filter(client) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			ts.killClient(conn)
+			killed++
+		}
+	}
+	return resp.EncodeInteger(killed), nil
+}
+
+// killClient closes c once the event loop serving it gets to it, so the reply to the
+// calling client is written first when it kills itself
+func (ts *Server) killClient(c gnet.Conn) {
+	if err := c.Close(); err != nil {
+		logger.Error("Error occurred closing connection", "error", err)
+	}
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/panjf2000/gnet/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeConn implements the parts of gnet.Conn the CLIENT command and the shutdown use
+type fakeConn struct {
+	gnet.Conn
+	ctx      interface{}
+	remote   net.Addr
+	closed   bool
+	buffered int
+	written  []byte
+}
+
+func (f *fakeConn) Context() interface{}       { return f.ctx }
+func (f *fakeConn) SetContext(ctx interface{}) { f.ctx = ctx }
+func (f *fakeConn) RemoteAddr() net.Addr       { return f.remote }
+func (f *fakeConn) LocalAddr() net.Addr        { return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 7997} }
+func (f *fakeConn) OutboundBuffered() int      { return f.buffered }
+func (f *fakeConn) Close() error               { f.closed = true; return nil }
+func (f *fakeConn) Write(buf []byte) (int, error) {
+	f.written = append(f.written, buf...)
+	return len(buf), nil
+}
+func (f *fakeConn) AsyncWrite(buf []byte, callback gnet.AsyncCallback) error {
+	f.written = append(f.written, buf...)
+	return callback(f, nil)
+}
+
+func newTestClients(t *testing.T, n int) (*Server, []*fakeConn) {
+	ts := &Server{
+		clientTransaction:      make(map[uint64][]string),
+		connectionSubscription: make(map[uint64]map[string]struct{}),
+		connectionMap:          make(map[uint64]gnet.Conn),
+	}
+	conns := make([]*fakeConn, 0, n)
+	for i := 0; i < n; i++ {
+		conn := &fakeConn{remote: &net.TCPAddr{IP: net.IPv4(10, 0, 0, byte(i+1)), Port: 5000}}
+		conn.SetContext(newClientConn(conn))
+		ts.connectionMap[connID(conn)] = conn
+		conns = append(conns, conn)
+	}
+	return ts, conns
+}
+
+func TestClientIDsAreUnique(t *testing.T) {
+	_, conns := newTestClients(t, 3)
+	require.Less(t, connID(conns[0]), connID(conns[1]))
+	require.Less(t, connID(conns[1]), connID(conns[2]))
+}
+
+func TestClientList(t *testing.T) {
+	ts, conns := newTestClients(t, 2)
+	getClientConn(conns[0]).name = "worker"
+	ts.clientTransaction[connID(conns[1])] = []string{"SET k v"}
+
+	res, err := ts.clientList(nil)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(strings.SplitN(res, "\r\n", 3)[1], "\n"), "\n")
+	require.Len(t, lines, 2)
+	require.Contains(t, lines[0], "addr=10.0.0.1:5000 ")
+	require.Contains(t, lines[0], "name=worker ")
+	require.Contains(t, lines[0], "flags=N ")
+	require.Contains(t, lines[1], "flags=x ")
+	require.Contains(t, lines[1], "multi=1 ")
+
+	_, err = ts.clientList([]string{"ID", "nope"})
+	require.Error(t, err)
+}
+
+func TestClientKill(t *testing.T) {
+	ts, conns := newTestClients(t, 3)
+	getClientConn(conns[1]).name = "worker"
+
+	res, err := ts.clientKill([]string{"NAME", "worker"}, conns[0])
+	require.NoError(t, err)
+	require.Equal(t, ":1\r\n", res)
+	require.True(t, conns[1].closed)
+
+	// The calling client is skipped unless SKIPME is no
+	res, err = ts.clientKill([]string{"ADDR", "10.0.0.1:5000"}, conns[0])
+	require.NoError(t, err)
+	require.Equal(t, ":0\r\n", res)
+	res, err = ts.clientKill([]string{"ADDR", "10.0.0.1:5000", "SKIPME", "no"}, conns[0])
+	require.NoError(t, err)
+	require.Equal(t, ":1\r\n", res)
+
+	_, err = ts.clientKill([]string{"10.0.0.3:5000"}, conns[0])
+	require.NoError(t, err)
+	require.True(t, conns[2].closed)
+
+	_, err = ts.clientKill([]string{"10.0.0.9:5000"}, conns[0])
+	require.Error(t, err)
+}
@@ -0,0 +1,252 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/panjf2000/gnet/v2"
+	"treds/acl"
+	"treds/commands"
+	"treds/resp"
+)
+
+const CommandCommandName = "COMMAND"
+
+func RegisterCommandCommand(r ServerCommandRegistry) {
+	r.Add(&ServerCommandRegistration{
+		Name:       CommandCommandName,
+		Execute:    executeCommand(),
+		Categories: []string{acl.CategoryConnection},
+		Spec: commands.Spec{
+			Summary: "Describes the commands, COMMAND, COMMAND COUNT, LIST, INFO and DOCS",
+			Group:   commands.GroupServer,
+			Args: []commands.Arg{
+				{Name: "subcommand", Type: commands.ArgString, Optional: true},
+				{Name: "command-name", Type: commands.ArgString, Optional: true, Multiple: true},
+			},
+		},
+	})
+}
+
+// commandDescription is a command as COMMAND describes it, a store or a server command
+type commandDescription struct {
+	name       string
+	spec       commands.Spec
+	flags      []string
+	categories []string
+}
+
+// executeCommand runs COMMAND [COUNT|LIST|INFO [command-name ...]|DOCS [command-name ...]]
+func executeCommand() ExecutionHook {
+	return func(inp string, ts *Server, c gnet.Conn) gnet.Action {
+		_, args, err := parseCommand(inp)
+		if err != nil {
+			ts.RespondErr(c, err)
+			return gnet.None
+		}
+
+		var res string
+		subcommand := ""
+		if len(args) > 0 {
+			subcommand = strings.ToUpper(args[0])
+		}
+		switch {
+		case subcommand == "":
+			res = resp.EncodeArray(ts.commandInfo(nil))
+		case subcommand == "COUNT" && len(args) == 1:
+			res = resp.EncodeInteger(len(ts.commandDescriptions()))
+		case subcommand == "LIST" && len(args) == 1:
+			names := make([]string, 0)
+			for _, command := range ts.commandDescriptions() {
+				names = append(names, strings.ToLower(command.name))
+			}
+			res = resp.EncodeStringArray(names)
+		case subcommand == "INFO":
+			res = resp.EncodeArray(ts.commandInfo(args[1:]))
+		case subcommand == "DOCS":
+			res = ts.commandDocs(args[1:], getClientConn(c).protocol)
+		case subcommand == "COUNT" || subcommand == "LIST":
+			err = fmt.Errorf("invalid number of arguments")
+		default:
+			err = fmt.Errorf("unknown subcommand '%s'", args[0])
+		}
+		if err != nil {
+			ts.RespondErr(c, err)
+			return gnet.None
+		}
+		_, errConn := c.Write([]byte(res))
+		if errConn != nil {
+			logger.Error("Error occurred writing to connection", "error", errConn)
+		}
+		return gnet.None
+	}
+}
+
+// commandDescriptions returns the store and server commands sorted by name
+func (ts *Server) commandDescriptions() []commandDescription {
+	descriptions := make([]commandDescription, 0)
+	for _, reg := range ts.tredsCommandRegistry.All() {
+		category := acl.CategoryRead
+		if reg.IsWrite() {
+			category = acl.CategoryWrite
+		}
+		descriptions = append(descriptions, commandDescription{
+			name:       reg.Name,
+			spec:       reg.Spec,
+			flags:      reg.Spec.Flags,
+			categories: []string{category},
+		})
+	}
+	for _, reg := range ts.tredsServerCommandRegistry.All() {
+		descriptions = append(descriptions, commandDescription{
+			name:       reg.Name,
+			spec:       reg.Spec,
+			flags:      serverCommandFlags(reg.Categories),
+			categories: reg.Categories,
+		})
+	}
+	sort.Slice(descriptions, func(i, j int) bool { return descriptions[i].name < descriptions[j].name })
+	return descriptions
+}
+
+// serverCommandFlags derives the flags of a server command from its ACL categories
+func serverCommandFlags(categories []string) []string {
+	flags := make([]string, 0)
+	for _, category := range categories {
+		switch category {
+		case acl.CategoryAdmin:
+			flags = append(flags, commands.FlagAdmin)
+		case acl.CategoryPubSub:
+			flags = append(flags, commands.FlagPubSub)
+		case acl.CategoryWrite:
+			flags = append(flags, commands.FlagWrite)
+		case acl.CategoryRead:
+			flags = append(flags, commands.FlagReadonly)
+		}
+	}
+	return flags
+}
+
+func (ts *Server) findCommand(name string) (commandDescription, bool) {
+	for _, command := range ts.commandDescriptions() {
+		if strings.EqualFold(command.name, name) {
+			return command, true
+		}
+	}
+	return commandDescription{}, false
+}
+
+// commandInfo describes the named commands, every command without names, like Redis:
+// [name, arity, [flags...], first key, last key, step, [@categories...], [], [], []].
+// Unknown commands are described as nil.
+func (ts *Server) commandInfo(names []string) []interface{} {
+	var described []*commandDescription
+	if len(names) == 0 {
+		for _, command := range ts.commandDescriptions() {
+			described = append(described, &command)
+		}
+	}
+	for _, name := range names {
+		command, ok := ts.findCommand(name)
+		if !ok {
+			described = append(described, nil)
+			continue
+		}
+		described = append(described, &command)
+	}
+
+	info := make([]interface{}, 0, len(described))
+	for _, command := range described {
+		if command == nil {
+			info = append(info, nil)
+			continue
+		}
+		first, last, step := command.spec.KeyPositions()
+		info = append(info, []interface{}{
+			strings.ToLower(command.name),
+			command.spec.Arity(),
+			stringsToValues(command.flags, ""),
+			first,
+			last,
+			step,
+			stringsToValues(command.categories, "@"),
+			[]interface{}{},
+			[]interface{}{},
+			[]interface{}{},
+		})
+	}
+	return info
+}
+
+// commandDocs returns the documentation of the named commands, every command without names, as a map of
+// name to summary, group and arguments. Maps are flattened to arrays for RESP2 like Redis does.
+func (ts *Server) commandDocs(names []string, protocol int) string {
+	docs := make([]interface{}, 0)
+	for _, command := range ts.commandDescriptions() {
+		if len(names) > 0 && !containsFold(names, command.name) {
+			continue
+		}
+		docs = append(docs, strings.ToLower(command.name), docsMap(protocol,
+			"summary", command.spec.Summary,
+			"group", command.spec.Group,
+			"arguments", docsArgs(command.spec.Args, protocol),
+		))
+	}
+	if protocol == resp.RESP3 {
+		return resp.EncodeValueMap(docs)
+	}
+	return resp.EncodeArray(docs)
+}
+
+func docsArgs(args []commands.Arg, protocol int) []interface{} {
+	docs := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		argType := arg.Type
+		if argType == commands.ArgPrefix {
+			// Key prefixes are strings for Redis clients
+			argType = commands.ArgString
+		}
+		pairs := []interface{}{"name", arg.Name, "type", string(argType)}
+		flags := make([]interface{}, 0)
+		if arg.Optional {
+			flags = append(flags, "optional")
+		}
+		if arg.Multiple {
+			flags = append(flags, "multiple")
+		}
+		if len(flags) > 0 {
+			pairs = append(pairs, "flags", flags)
+		}
+		if arg.Type == commands.ArgBlock {
+			pairs = append(pairs, "arguments", docsArgs(arg.Args, protocol))
+		}
+		docs = append(docs, docsMap(protocol, pairs...))
+	}
+	return docs
+}
+
+// docsMap returns pairs as a map for RESP3 and as an array of alternating keys and values for RESP2
+func docsMap(protocol int, pairs ...interface{}) interface{} {
+	if protocol == resp.RESP3 {
+		return resp.Map(pairs)
+	}
+	return pairs
+}
+
+func stringsToValues(values []string, prefix string) []interface{} {
+	converted := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		converted = append(converted, prefix+value)
+	}
+	return converted
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"treds/resp"
+)
+
+func TestCommandInfo(t *testing.T) {
+	ts, _ := newTestServer(t)
+
+	info := ts.commandInfo([]string{"get", "mset", "SUBSCRIBE", "missing"})
+	require.Equal(t, []interface{}{
+		[]interface{}{"get", 2, []interface{}{"readonly"}, 1, 1, 1, []interface{}{"@read"}, []interface{}{}, []interface{}{}, []interface{}{}},
+		[]interface{}{"mset", -3, []interface{}{"write"}, 1, -1, 2, []interface{}{"@write"}, []interface{}{}, []interface{}{}, []interface{}{}},
+		[]interface{}{"subscribe", -2, []interface{}{"pubsub"}, 0, 0, 0, []interface{}{"@pubsub"}, []interface{}{}, []interface{}{}, []interface{}{}},
+		nil,
+	}, info)
+
+	// Every store and server command is described, sorted by name
+	all := ts.commandInfo(nil)
+	require.Len(t, all, len(ts.tredsCommandRegistry.All())+len(ts.tredsServerCommandRegistry.All()))
+	require.Equal(t, "acl", all[0].([]interface{})[0])
+}
+
+func TestCommandDocs(t *testing.T) {
+	ts, _ := newTestServer(t)
+
+	docs := ts.commandDocs([]string{"hset"}, resp.RESP2)
+	require.Equal(t, resp.EncodeArray([]interface{}{
+		"hset", []interface{}{
+			"summary", "Sets fields of a hash",
+			"group", "hash",
+			"arguments", []interface{}{
+				[]interface{}{"name", "key", "type", "key"},
+				[]interface{}{"name", "data", "type", "block", "flags", []interface{}{"multiple"}, "arguments", []interface{}{
+					[]interface{}{"name", "field", "type", "string"},
+					[]interface{}{"name", "value", "type", "string"},
+				}},
+			},
+		},
+	}), docs)
+
+	docs = ts.commandDocs([]string{"ping"}, resp.RESP3)
+	require.Equal(t, resp.EncodeValueMap([]interface{}{
+		"ping", resp.Map{
+			"summary", "Replies with PONG",
+			"group", "connection",
+			"arguments", []interface{}{
+				resp.Map{"name", "message", "type", "string", "flags", []interface{}{"optional"}},
+			},
+		},
+	}), docs)
+}
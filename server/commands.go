@@ -13,4 +13,14 @@ func RegisterCommands(r ServerCommandRegistry) {
 	RegisterPUnsubscribeCommand(r)
 	RegisterUnsubscribeCommand(r)
 	RegisterPubSubChannels(r)
+	RegisterHelloCommand(r)
+	RegisterAuthCommand(r)
+	RegisterClusterAuthCommand(r)
+	RegisterACLCommand(r)
+	RegisterClientCommand(r)
+	RegisterConfigCommand(r)
+	RegisterInfoCommand(r)
+	RegisterSlowlogCommand(r)
+	RegisterMonitorCommand(r)
+	RegisterCommandCommand(r)
 }
@@ -0,0 +1,197 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/panjf2000/gnet/v2"
+	"treds/acl"
+	"treds/commands"
+	"treds/config"
+	"treds/logging"
+	"treds/resp"
+)
+
+const ConfigCommandName = "CONFIG"
+
+func RegisterConfigCommand(r ServerCommandRegistry) {
+	r.Add(&ServerCommandRegistration{
+		Name:       ConfigCommandName,
+		Execute:    executeConfig(),
+		Categories: []string{acl.CategoryAdmin},
+		Spec: commands.Spec{
+			Summary: "Reads and changes the configuration of the node, CONFIG GET, SET and REWRITE",
+			Group:   commands.GroupServer,
+			Args: []commands.Arg{
+				{Name: "subcommand", Type: commands.ArgString},
+				{Name: "arg", Type: commands.ArgString, Optional: true, Multiple: true},
+			},
+		},
+		Subcommands: map[string][]string{
+			"get":     nil,
+			"set":     nil,
+			"rewrite": nil,
+		},
+	})
+}
+
+// executeConfig runs CONFIG GET|SET|REWRITE.
+// The configuration is local to a node, so none of them is forwarded to the leader.
+func executeConfig() ExecutionHook {
+	return func(inp string, ts *Server, c gnet.Conn) gnet.Action {
+		_, args, err := parseCommand(inp)
+		if err != nil {
+			ts.RespondErr(c, err)
+			return gnet.None
+		}
+		if len(args) == 0 {
+			ts.RespondErr(c, fmt.Errorf("invalid number of arguments"))
+			return gnet.None
+		}
+
+		var res string
+		switch strings.ToUpper(args[0]) {
+		case "GET":
+			res, err = ts.configGet(args[1:], getClientConn(c).protocol)
+		case "SET":
+			err = ts.configSet(args[1:])
+			res = resp.EncodeSimpleString("OK")
+		case "REWRITE":
+			err = ts.configRewrite()
+			res = resp.EncodeSimpleString("OK")
+		default:
+			err = fmt.Errorf("unknown subcommand '%s'", args[0])
+		}
+		if err != nil {
+			ts.RespondErr(c, err)
+			return gnet.None
+		}
+		_, errConn := c.Write([]byte(res))
+		if errConn != nil {
+			logger.Error("Error occurred writing to connection", "error", errConn)
+		}
+		return gnet.None
+	}
+}
+
+// configRedacted replaces the value of a secret parameter which is set in CONFIG GET replies
+const configRedacted = "(redacted)"
+
+// configGet returns the parameters matching any of the patterns with their values, CONFIG GET pattern [pattern ...].
+// Secret parameters are masked, an empty value tells they are not set.
+func (ts *Server) configGet(patterns []string, protocol int) (string, error) {
+	if len(patterns) == 0 {
+		return "", fmt.Errorf("invalid number of arguments")
+	}
+	matched := make(map[string]struct{})
+	for _, pattern := range patterns {
+		for _, name := range config.Match(pattern) {
+			matched[name] = struct{}{}
+		}
+	}
+	var pairs []string
+	for _, name := range config.Names() {
+		if _, ok := matched[name]; ok {
+			value, _ := ts.config.Get(name)
+			if value != "" && config.IsSecret(name) {
+				value = configRedacted
+			}
+			pairs = append(pairs, name, value)
+		}
+	}
+	if protocol == resp.RESP3 {
+		return resp.EncodeStringMap(pairs), nil
+	}
+	return resp.EncodeStringArray(pairs), nil
+}
+
+// configSet changes parameters while the node runs, CONFIG SET parameter value [parameter value ...].
+// Either every parameter is changed or none is.
+func (ts *Server) configSet(args []string) error {
+	if len(args) == 0 || len(args)%2 != 0 {
+		return fmt.Errorf("invalid number of arguments")
+	}
+	cfg := *ts.config
+	for i := 0; i < len(args); i += 2 {
+		if _, ok := cfg.Get(args[i]); !ok {
+			return fmt.Errorf("unknown parameter '%s'", args[i])
+		}
+		if !config.IsLive(args[i]) {
+			return fmt.Errorf("parameter '%s' can not be changed while the server runs", args[i])
+		}
+		if err := cfg.Set(args[i], args[i+1]); err != nil {
+			return err
+		}
+	}
+	return ts.applyConfig(&cfg)
+}
+
+// configRewrite persists the running configuration to the config file, CONFIG REWRITE
+func (ts *Server) configRewrite() error {
+	if ts.configFile == "" {
+		return fmt.Errorf("The server is running without a config file")
+	}
+	return config.Rewrite(ts.configFile, ts.config)
+}
+
+// SetConfigFile sets the file CONFIG REWRITE writes to
+func (ts *Server) SetConfigFile(path string) {
+	ts.configFile = path
+}
+
+// applyConfig makes cfg the running configuration, it is called with ts.mu held once the server started.
+// Nothing is changed when a parameter is invalid.
+func (ts *Server) applyConfig(cfg *config.Config) error {
+	normal, pubsub, err := parseClientLimits(cfg)
+	if err != nil {
+		return err
+	}
+	if cfg.RaftApplyTimeout <= 0 || cfg.ExpirySweepInterval <= 0 {
+		return fmt.Errorf("raft-apply-timeout and expiry-sweep-interval must be positive")
+	}
+	if cfg.SlowlogMaxLen < 0 {
+		return fmt.Errorf("slowlog-max-len must not be negative")
+	}
+	if _, err = logging.ParseLevel(cfg.LogLevel); err != nil {
+		return err
+	}
+	if ts.raft != nil {
+		err = ts.raft.ReloadConfig(raft.ReloadableConfig{
+			TrailingLogs:      cfg.RaftTrailingLogs,
+			SnapshotInterval:  cfg.RaftSnapshotInterval,
+			SnapshotThreshold: cfg.RaftSnapshotThreshold,
+			HeartbeatTimeout:  cfg.RaftHeartbeatTimeout,
+			ElectionTimeout:   cfg.RaftElectionTimeout,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	ts.SetClientLimits(cfg.MaxClients, normal, pubsub)
+	ts.raftApplyTimeout.Store(int64(cfg.RaftApplyTimeout))
+	ts.expirySweepInterval.Store(int64(cfg.ExpirySweepInterval))
+	ts.slowlog.configure(cfg.SlowlogLogSlowerThan, cfg.SlowlogMaxLen)
+	_ = logging.SetLevel(cfg.LogLevel)
+	ts.config = cfg
+	return nil
+}
+
+// parseClientLimits parses the output buffer limits of cfg
+func parseClientLimits(cfg *config.Config) (OutputBufferLimit, OutputBufferLimit, error) {
+	normal, err := ParseOutputBufferLimit(cfg.ClientOutputBufferLimitNormal)
+	if err != nil {
+		return OutputBufferLimit{}, OutputBufferLimit{}, err
+	}
+	pubsub, err := ParseOutputBufferLimit(cfg.ClientOutputBufferLimitPubSub)
+	if err != nil {
+		return OutputBufferLimit{}, OutputBufferLimit{}, err
+	}
+	return normal, pubsub, nil
+}
+
+// expirySweep is how long the expiry sweep waits between two runs
+func (ts *Server) expirySweep() time.Duration {
+	return time.Duration(ts.expirySweepInterval.Load())
+}
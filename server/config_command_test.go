@@ -0,0 +1,79 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"treds/config"
+	"treds/resp"
+)
+
+func newTestConfigServer(t *testing.T) *Server {
+	ts, _ := newTestClients(t, 0)
+	require.NoError(t, ts.applyConfig(config.Default()))
+	return ts
+}
+
+func TestConfigGet(t *testing.T) {
+	ts := newTestConfigServer(t)
+
+	res, err := ts.configGet([]string{"maxclients", "raft-apply-*"}, resp.RESP2)
+	require.NoError(t, err)
+	require.Equal(t, resp.EncodeStringArray([]string{"maxclients", "10000", "raft-apply-timeout", "1s"}), res)
+
+	res, err = ts.configGet([]string{"MAXCLIENTS"}, resp.RESP3)
+	require.NoError(t, err)
+	require.Equal(t, resp.EncodeStringMap([]string{"maxclients", "10000"}), res)
+
+	// Secrets are masked once set
+	res, err = ts.configGet([]string{"cluster-*"}, resp.RESP2)
+	require.NoError(t, err)
+	require.Equal(t, resp.EncodeStringArray([]string{"cluster-secret", ""}), res)
+	ts.config.ClusterSecret = "s3cret"
+	res, err = ts.configGet([]string{"*"}, resp.RESP2)
+	require.NoError(t, err)
+	require.NotContains(t, res, "s3cret")
+	require.Contains(t, res, resp.EncodeStringArray([]string{"cluster-secret", configRedacted})[len("*2\r\n"):])
+
+	res, err = ts.configGet([]string{"nope"}, resp.RESP2)
+	require.NoError(t, err)
+	require.Equal(t, resp.EncodeStringArray(nil), res)
+}
+
+func TestConfigSet(t *testing.T) {
+	ts := newTestConfigServer(t)
+
+	require.NoError(t, ts.configSet([]string{"maxclients", "5", "raft-apply-timeout", "3s", "client-output-buffer-limit-normal", "1mb 0 0"}))
+	require.Equal(t, 5, ts.maxClients)
+	require.Equal(t, 3*time.Second, ts.GetRaftApplyTimeout())
+	require.Equal(t, OutputBufferLimit{HardBytes: 1 << 20}, (*ts.outputBufferLimits.Load())[ClientClassNormal])
+
+	// Nothing changes when one of the parameters is refused
+	require.Error(t, ts.configSet([]string{"maxclients", "6", "client-output-buffer-limit-pubsub", "x"}))
+	require.Error(t, ts.configSet([]string{"maxclients", "6", "port", "1"}))
+	require.Error(t, ts.configSet([]string{"maxclients", "6", "nope", "1"}))
+	require.Error(t, ts.configSet([]string{"maxclients"}))
+	require.Equal(t, 5, ts.maxClients)
+	require.Equal(t, 5, ts.config.MaxClients)
+}
+
+func TestConfigRewrite(t *testing.T) {
+	ts := newTestConfigServer(t)
+	require.Error(t, ts.configRewrite())
+
+	path := filepath.Join(t.TempDir(), "treds.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("port: 8000\n"), 0600))
+	cfg, err := config.Load(path, config.Default())
+	require.NoError(t, err)
+	require.NoError(t, ts.applyConfig(cfg))
+	ts.SetConfigFile(path)
+	require.NoError(t, ts.configSet([]string{"maxclients", "5"}))
+	require.NoError(t, ts.configRewrite())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "port: 8000\nmaxclients: 5\n", string(data))
+}
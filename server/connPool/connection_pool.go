@@ -1,6 +1,7 @@
 package connPool
 
 import (
+	"crypto/tls"
 	"github.com/pkg/errors"
 	"net"
 	"sync"
@@ -16,7 +17,11 @@ type PooledConn struct {
 func newPooledConn(network string, addr string, pool *ConnPool) (*PooledConn, error) {
 	conn := &PooledConn{pool: pool}
 	var err error
-	conn.Conn, err = net.Dial(network, addr)
+	if pool.tlsConfig != nil {
+		conn.Conn, err = tls.Dial(network, addr, pool.tlsConfig)
+	} else {
+		conn.Conn, err = net.Dial(network, addr)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -38,6 +43,8 @@ type ConnPool struct {
 	pool     map[string]*PooledConn
 	poolLock sync.Mutex
 	timeout  time.Duration
+	// tlsConfig is used to dial when set
+	tlsConfig *tls.Config
 }
 
 func NewConnPool(timeout time.Duration) *ConnPool {
@@ -45,6 +52,13 @@ func NewConnPool(timeout time.Duration) *ConnPool {
 	return &ConnPool{pool: pool, timeout: timeout}
 }
 
+// NewTLSConnPool returns a pool dialing every connection over TLS
+func NewTLSConnPool(timeout time.Duration, tlsConfig *tls.Config) *ConnPool {
+	cp := NewConnPool(timeout)
+	cp.tlsConfig = tlsConfig
+	return cp
+}
+
 func (cp *ConnPool) Dial(network, addr string) (net.Conn, error) {
 	conn, err := newPooledConn(network, addr, cp)
 	if err != nil {
@@ -1,9 +1,9 @@
 package server
 
 import (
-	"fmt"
-
 	"github.com/panjf2000/gnet/v2"
+	"treds/acl"
+	"treds/commands"
 	"treds/resp"
 )
 
@@ -11,8 +11,14 @@ const DiscardCommandName = "DISCARD"
 
 func RegisterDiscardCommand(r ServerCommandRegistry) {
 	r.Add(&ServerCommandRegistration{
-		Name:    DiscardCommandName,
-		Execute: executeDiscard(),
+		Name:       DiscardCommandName,
+		Execute:    executeDiscard(),
+		Unlocked:   true,
+		Categories: []string{acl.CategoryTransaction},
+		Spec: commands.Spec{
+			Summary: "Discards the commands queued since MULTI",
+			Group:   commands.GroupTransactions,
+		},
 	})
 }
 
@@ -30,12 +36,14 @@ func executeDiscard() ExecutionHook {
 		if forwarded {
 			_, errConn := c.Write([]byte(rspFwd))
 			if errConn != nil {
-				fmt.Println("Error occurred writing to connection", errConn)
+				logger.Error("Error occurred writing to connection", "error", errConn)
 			}
 			return gnet.None
 		}
 
-		delete(ts.GetClientTransaction(), c.RemoteAddr().String())
+		ts.mu.Lock()
+		delete(ts.GetClientTransaction(), connID(c))
+		ts.mu.Unlock()
 
 		res := "OK"
 		_, errConn := c.Write([]byte(resp.EncodeSimpleString(res)))
@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"github.com/panjf2000/gnet/v2"
+	"treds/acl"
+	"treds/commands"
 	"treds/resp"
 )
 
@@ -12,8 +14,14 @@ const ExecCommandName = "EXEC"
 
 func RegisterExecCommand(r ServerCommandRegistry) {
 	r.Add(&ServerCommandRegistration{
-		Name:    ExecCommandName,
-		Execute: executeExec(),
+		Name:       ExecCommandName,
+		Execute:    executeExec(),
+		Unlocked:   true,
+		Categories: []string{acl.CategoryTransaction},
+		Spec: commands.Spec{
+			Summary: "Runs the commands queued since MULTI",
+			Group:   commands.GroupTransactions,
+		},
 	})
 }
 
@@ -31,12 +39,15 @@ func executeExec() ExecutionHook {
 		if forwarded {
 			_, errConn := c.Write([]byte(rspFwd))
 			if errConn != nil {
-				fmt.Println("Error occurred writing to connection", errConn)
+				logger.Error("Error occurred writing to connection", "error", errConn)
 			}
 			return gnet.None
 		}
 
-		clientTransaction, ok := ts.GetClientTransaction()[c.RemoteAddr().String()]
+		// The queued commands are applied without ts.mu, only this connection changes its transaction
+		ts.mu.Lock()
+		clientTransaction, ok := ts.GetClientTransaction()[connID(c)]
+		ts.mu.Unlock()
 		if !ok {
 			ts.RespondErr(c, fmt.Errorf("no transaction started"))
 			return gnet.None
@@ -61,7 +72,7 @@ func executeExec() ExecutionHook {
 				continue
 			}
 
-			future := ts.GetRaft().Apply([]byte(transactionCommand), ts.GetRaftApplyTimeout())
+			future := ts.apply([]byte(transactionCommand))
 
 			if err := future.Error(); err != nil {
 				ts.RespondErr(c, err)
@@ -77,7 +88,9 @@ func executeExec() ExecutionHook {
 				replies = append(replies, rsp.(string))
 			}
 		}
-		delete(ts.GetClientTransaction(), c.RemoteAddr().String())
+		ts.mu.Lock()
+		delete(ts.GetClientTransaction(), connID(c))
+		ts.mu.Unlock()
 
 		_, errConn := c.Write([]byte(resp.EncodeStringArrayRESP(replies)))
 		if errConn != nil {
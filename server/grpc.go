@@ -0,0 +1,424 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"treds/commands"
+	"treds/resp"
+	tredspb "treds/server/proto"
+	kvstore "treds/store/proto"
+)
+
+// DefaultScanBatchSize is the number of pairs a streaming scan reads from the store at once
+const DefaultScanBatchSize = 1000
+
+// grpcService implements the Treds gRPC service on top of the store command registry
+type grpcService struct {
+	tredspb.UnimplementedTredsServer
+	ts *Server
+}
+
+// StartGRPC serves the gRPC service on addr in the background, over TLS when it is enabled
+func (ts *Server) StartGRPC(addr string, tlsOptions *TLSOptions) (*grpc.Server, error) {
+	var options []grpc.ServerOption
+	if tlsOptions.Enabled() {
+		config, err := tlsOptions.ServerConfig(tlsOptions.AuthClients)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, grpc.Creds(credentials.NewTLS(config)))
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	grpcServer := grpc.NewServer(options...)
+	tredspb.RegisterTredsServer(grpcServer, &grpcService{ts: ts})
+	ts.grpcServer = grpcServer
+	go func() {
+		if errServe := grpcServer.Serve(listener); errServe != nil {
+			logger.Error("gRPC server stopped", "error", errServe)
+		}
+	}()
+	logger.Info("gRPC listening", "addr", addr)
+	return grpcServer, nil
+}
+
+// grpcUser returns the ACL user of a call, from basic credentials in the authorization metadata
+// or the default user
+func (ts *Server) grpcUser(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		if user, ok := ts.acl.NoAuthUser(); ok {
+			return user.Name, nil
+		}
+		return "", status.Error(codes.Unauthenticated, "NOAUTH Authentication required.")
+	}
+	encoded, ok := strings.CutPrefix(values[0], "Basic ")
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "authorization must use the Basic scheme")
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", status.Error(codes.Unauthenticated, "invalid basic credentials")
+	}
+	username, password, _ := strings.Cut(string(decoded), ":")
+	if _, err = ts.acl.Authenticate(username, password); err != nil {
+		return "", status.Error(codes.Unauthenticated, err.Error())
+	}
+	return username, nil
+}
+
+// authorizeCall authenticates the caller and checks it may run command with args
+func (s *grpcService) authorizeCall(ctx context.Context, command string, args []string) error {
+	username, err := s.ts.grpcUser(ctx)
+	if err != nil {
+		return err
+	}
+	if err = s.ts.authorizeUser(username, command, args); err != nil {
+		if strings.HasPrefix(err.Error(), "NOAUTH") {
+			return status.Error(codes.Unauthenticated, err.Error())
+		}
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return nil
+}
+
+// run executes the store command args the same way commands of RESP clients are run and returns
+// the reply converted by resp.ParseReply. The deadline of ctx bounds the wait for the reply.
+func (s *grpcService) run(ctx context.Context, args ...string) (res interface{}, err error) {
+	s.ts.stats.CommandsProcessed.Add(1)
+	name := s.ts.metricCommand(args[0])
+	defer func(start time.Time) {
+		s.ts.metrics.observeCommand(name, start)
+		if err != nil {
+			s.ts.metrics.commandError(name)
+		}
+	}(time.Now())
+	command := args[0]
+	if _, err := s.ts.tredsCommandRegistry.Retrieve(strings.ToUpper(command)); err != nil {
+		return nil, status.Errorf(codes.Unimplemented, "unknown command '%s'", command)
+	}
+	if err := s.authorizeCall(ctx, command, args[1:]); err != nil {
+		return nil, err
+	}
+	source := "grpc"
+	if p, ok := peer.FromContext(ctx); ok {
+		source = p.Addr.String()
+	}
+	s.ts.feedMonitors(source, command, args[1:])
+
+	type result struct {
+		res string
+		err error
+	}
+	done := make(chan result, 1)
+	// The command keeps running when the deadline passes first, a shutdown waits for it like for RESP commands
+	s.ts.inflight.Add(1)
+	go func() {
+		defer s.ts.inflight.Add(-1)
+		// RESP3 replies keep maps, doubles, booleans and nulls apart
+		res, err := s.ts.runCommand(resp.EncodeStringArray(args), command, args[1:], resp.RESP3)
+		done <- result{res, err}
+	}()
+	var r result
+	select {
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	case r = <-done:
+	}
+	if r.err != nil {
+		return nil, grpcError(r.err)
+	}
+
+	value, err := resp.ParseReply(bufio.NewReader(strings.NewReader(r.res)))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if replyErr, ok := value.(resp.ReplyError); ok {
+		return nil, status.Error(codes.FailedPrecondition, replyErr.Error())
+	}
+	return value, nil
+}
+
+// grpcError converts an error of runCommand, Raft errors mean the call can be retried
+func grpcError(err error) error {
+	if errors.Is(err, raft.ErrNotLeader) || errors.Is(err, raft.ErrLeadershipLost) ||
+		errors.Is(err, raft.ErrEnqueueTimeout) || errors.Is(err, raft.ErrRaftShutdown) {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	return status.Error(codes.InvalidArgument, err.Error())
+}
+
+func (s *grpcService) Command(ctx context.Context, req *tredspb.CommandRequest) (*tredspb.CommandResponse, error) {
+	if req.Cmd == "" {
+		return nil, status.Error(codes.InvalidArgument, "cmd is required")
+	}
+	args := []string{req.Cmd}
+	for _, arg := range req.Args {
+		args = append(args, string(arg))
+	}
+	value, err := s.run(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &tredspb.CommandResponse{Result: toValue(value)}, nil
+}
+
+func (s *grpcService) Get(ctx context.Context, req *tredspb.GetRequest) (*tredspb.GetResponse, error) {
+	value, err := s.run(ctx, commands.GetCommand, string(req.Key))
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return &tredspb.GetResponse{}, nil
+	}
+	return &tredspb.GetResponse{Value: []byte(fmt.Sprint(value)), Found: true}, nil
+}
+
+func (s *grpcService) Set(ctx context.Context, req *tredspb.SetRequest) (*tredspb.SetResponse, error) {
+	if _, err := s.run(ctx, commands.SetCommand, string(req.Key), string(req.Value)); err != nil {
+		return nil, err
+	}
+	return &tredspb.SetResponse{}, nil
+}
+
+// Scan pages through SCANKVS and streams every pair, so large prefixes are not held in memory at once
+func (s *grpcService) Scan(req *tredspb.ScanRequest, stream grpc.ServerStreamingServer[kvstore.KeyValue]) error {
+	batchSize := req.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultScanBatchSize
+	}
+	remaining := req.Limit
+	cursor := "0"
+	for {
+		count := batchSize
+		if remaining > 0 && remaining < count {
+			count = remaining
+		}
+		value, err := s.run(stream.Context(), commands.PrefixScanCommand, cursor, string(req.Prefix), strconv.FormatInt(count, 10))
+		if err != nil {
+			return err
+		}
+		elements := toStrings(value)
+		if len(elements) == 0 {
+			return nil
+		}
+		// The last element is the cursor of the next page, 0 once the prefix is exhausted
+		cursor = elements[len(elements)-1]
+		for i := 0; i+1 < len(elements)-1; i += 2 {
+			if err = stream.Send(&kvstore.KeyValue{Key: []byte(elements[i]), Value: []byte(elements[i+1])}); err != nil {
+				return err
+			}
+			if remaining > 0 {
+				remaining--
+				if remaining == 0 {
+					return nil
+				}
+			}
+		}
+		if cursor == "0" {
+			return nil
+		}
+	}
+}
+
+func (s *grpcService) DeletePrefix(ctx context.Context, req *tredspb.DeletePrefixRequest) (*tredspb.DeletePrefixResponse, error) {
+	value, err := s.run(ctx, commands.DeletePrefixCommand, string(req.Prefix))
+	if err != nil {
+		return nil, err
+	}
+	deleted, _ := value.(int64)
+	return &tredspb.DeletePrefixResponse{Deleted: deleted}, nil
+}
+
+func (s *grpcService) LongestPrefix(ctx context.Context, req *tredspb.LongestPrefixRequest) (*tredspb.LongestPrefixResponse, error) {
+	value, err := s.run(ctx, commands.LongestPrefixCommand, string(req.Key))
+	if err != nil {
+		return nil, err
+	}
+	elements := toStrings(value)
+	if len(elements) < 2 {
+		return &tredspb.LongestPrefixResponse{}, nil
+	}
+	return &tredspb.LongestPrefixResponse{
+		Pair:  &kvstore.KeyValue{Key: []byte(elements[0]), Value: []byte(elements[1])},
+		Found: true,
+	}, nil
+}
+
+func (s *grpcService) ZRangeByScore(ctx context.Context, req *tredspb.ZRangeByScoreRequest) (*tredspb.ZRangeResponse, error) {
+	command := commands.ZRANGESCOREKVS
+	if req.Reverse {
+		command = commands.ZREVRANGESCOREKVS
+	}
+	value, err := s.run(ctx, command, string(req.Key), strconv.FormatFloat(req.Min, 'f', -1, 64),
+		strconv.FormatFloat(req.Max, 'f', -1, 64), strconv.FormatInt(req.Offset, 10), rangeCount(req.Count), "true")
+	if err != nil {
+		return nil, err
+	}
+	return &tredspb.ZRangeResponse{Members: toScoredKeyValues(value)}, nil
+}
+
+func (s *grpcService) ZRangeByLex(ctx context.Context, req *tredspb.ZRangeByLexRequest) (*tredspb.ZRangeResponse, error) {
+	command := commands.ZRANGELEXKVS
+	if req.Reverse {
+		command = commands.ZREVRANGELEXKVS
+	}
+	value, err := s.run(ctx, command, string(req.Key), strconv.FormatInt(req.Offset, 10), rangeCount(req.Count), "true",
+		string(req.Min), string(req.Max))
+	if err != nil {
+		return nil, err
+	}
+	return &tredspb.ZRangeResponse{Members: toScoredKeyValues(value)}, nil
+}
+
+func (s *grpcService) DQuery(ctx context.Context, req *tredspb.DQueryRequest) (*tredspb.DQueryResponse, error) {
+	value, err := s.run(ctx, commands.DQuery, req.Collection, req.Query)
+	if err != nil {
+		return nil, err
+	}
+	return &tredspb.DQueryResponse{Documents: toStrings(value)}, nil
+}
+
+func (s *grpcService) VSearch(ctx context.Context, req *tredspb.VSearchRequest) (*tredspb.VSearchResponse, error) {
+	args := append([]string{commands.VSearch, req.Name}, formatFloats(req.Vector)...)
+	value, err := s.run(ctx, append(args, strconv.Itoa(int(req.K)))...)
+	if err != nil {
+		return nil, err
+	}
+	results, _ := value.([]interface{})
+	res := &tredspb.VSearchResponse{Results: make([]*tredspb.VSearchResult, 0, len(results))}
+	for _, result := range results {
+		// Every result is the vector ID followed by the vector
+		fields := toStrings(result)
+		if len(fields) == 0 {
+			continue
+		}
+		vector := make([]float64, 0, len(fields)-1)
+		for _, field := range fields[1:] {
+			f, errParse := strconv.ParseFloat(field, 64)
+			if errParse != nil {
+				return nil, status.Error(codes.Internal, errParse.Error())
+			}
+			vector = append(vector, f)
+		}
+		res.Results = append(res.Results, &tredspb.VSearchResult{Id: fields[0], Vector: vector})
+	}
+	return res, nil
+}
+
+// Watch streams published messages until the call ends. Messages are delivered by the node they are
+// published on, writes and PUBLISH go to the leader, so watchers should connect to the leader.
+func (s *grpcService) Watch(req *tredspb.WatchRequest, stream grpc.ServerStreamingServer[tredspb.WatchEvent]) error {
+	if len(req.Channels) == 0 {
+		return status.Error(codes.InvalidArgument, "channels are required")
+	}
+	command := SubscribeCommandName
+	if req.Prefix {
+		command = PSubscribeCommandName
+	}
+	if err := s.authorizeCall(stream.Context(), command, req.Channels); err != nil {
+		return err
+	}
+
+	w := s.ts.addWatcher(unique(req.Channels), req.Prefix)
+	defer s.ts.closeWatcher(w)
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-w.dropped:
+			if w.err == ErrShuttingDown {
+				return status.Error(codes.Unavailable, w.err.Error())
+			}
+			return status.Error(codes.ResourceExhausted, w.err.Error())
+		case msg := <-w.messages:
+			err := stream.Send(&tredspb.WatchEvent{Channel: msg.Channel, Pattern: msg.Pattern, Message: []byte(msg.Message)})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// rangeCount converts a count of a range request, where 0 means every member
+func rangeCount(count int64) string {
+	if count <= 0 {
+		return strconv.FormatInt(math.MaxInt64, 10)
+	}
+	return strconv.FormatInt(count, 10)
+}
+
+// toScoredKeyValues converts the flat score, key, value reply of a sorted map range
+func toScoredKeyValues(value interface{}) []*tredspb.ScoredKeyValue {
+	elements, _ := value.([]interface{})
+	members := make([]*tredspb.ScoredKeyValue, 0, len(elements)/3)
+	for i := 0; i+2 < len(elements); i += 3 {
+		score, ok := elements[i].(float64)
+		if !ok {
+			score, _ = strconv.ParseFloat(fmt.Sprint(elements[i]), 64)
+		}
+		members = append(members, &tredspb.ScoredKeyValue{
+			Score: score,
+			Key:   []byte(fmt.Sprint(elements[i+1])),
+			Value: []byte(fmt.Sprint(elements[i+2])),
+		})
+	}
+	return members
+}
+
+func toStrings(value interface{}) []string {
+	elements, _ := value.([]interface{})
+	res := make([]string, 0, len(elements))
+	for _, element := range elements {
+		res = append(res, fmt.Sprint(element))
+	}
+	return res
+}
+
+// toValue converts a reply parsed by resp.ParseReply
+func toValue(value interface{}) *tredspb.Value {
+	switch v := value.(type) {
+	case nil:
+		return &tredspb.Value{}
+	case string:
+		return &tredspb.Value{Kind: &tredspb.Value_Bulk{Bulk: []byte(v)}}
+	case int64:
+		return &tredspb.Value{Kind: &tredspb.Value_Integer{Integer: v}}
+	case float64:
+		return &tredspb.Value{Kind: &tredspb.Value_Double{Double: v}}
+	case bool:
+		return &tredspb.Value{Kind: &tredspb.Value_Boolean{Boolean: v}}
+	case []interface{}:
+		list := &tredspb.ValueList{Values: make([]*tredspb.Value, 0, len(v))}
+		for _, element := range v {
+			list.Values = append(list.Values, toValue(element))
+		}
+		return &tredspb.Value{Kind: &tredspb.Value_List{List: list}}
+	case map[string]interface{}:
+		m := &tredspb.ValueMap{Entries: make(map[string]*tredspb.Value, len(v))}
+		for key, element := range v {
+			m.Entries[key] = toValue(element)
+		}
+		return &tredspb.Value{Kind: &tredspb.Value_Map{Map: m}}
+	}
+	return &tredspb.Value{Kind: &tredspb.Value_Bulk{Bulk: []byte(fmt.Sprint(value))}}
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"treds/acl"
+	"treds/commands"
+	"treds/resp"
+	tredspb "treds/server/proto"
+	"treds/store"
+)
+
+func newTestGRPCClient(t *testing.T, ts *Server) tredspb.TredsClient {
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	tredspb.RegisterTredsServer(grpcServer, &grpcService{ts: ts})
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return tredspb.NewTredsClient(conn)
+}
+
+func TestGRPCReads(t *testing.T) {
+	ts, tredsStore := newTestServer(t)
+	require.NoError(t, tredsStore.MSet([]string{"user:1", "a", "user:2", "b", "user:3", "c", "order:1", "d"}))
+	require.NoError(t, tredsStore.ZAdd([]string{"z", "1", "k1", "v1", "2", "k2", "v2"}))
+	client := newTestGRPCClient(t, ts)
+	ctx := context.Background()
+
+	get, err := client.Get(ctx, &tredspb.GetRequest{Key: []byte("user:1")})
+	require.NoError(t, err)
+	require.True(t, get.Found)
+	require.Equal(t, "a", string(get.Value))
+
+	get, err = client.Get(ctx, &tredspb.GetRequest{Key: []byte("missing")})
+	require.NoError(t, err)
+	require.False(t, get.Found)
+
+	// Pages of one pair exercise the cursor
+	stream, err := client.Scan(ctx, &tredspb.ScanRequest{Prefix: []byte("user:"), BatchSize: 1})
+	require.NoError(t, err)
+	var keys []string
+	for {
+		pair, errRecv := stream.Recv()
+		if errRecv == io.EOF {
+			break
+		}
+		require.NoError(t, errRecv)
+		keys = append(keys, string(pair.Key))
+	}
+	require.Equal(t, []string{"user:1", "user:2", "user:3"}, keys)
+
+	longest, err := client.LongestPrefix(ctx, &tredspb.LongestPrefixRequest{Key: []byte("user:1:profile")})
+	require.NoError(t, err)
+	require.True(t, longest.Found)
+	require.Equal(t, "user:1", string(longest.Pair.Key))
+
+	zrange, err := client.ZRangeByScore(ctx, &tredspb.ZRangeByScoreRequest{Key: []byte("z"), Min: 0, Max: 10})
+	require.NoError(t, err)
+	require.Len(t, zrange.Members, 2)
+	require.Equal(t, 2.0, zrange.Members[1].Score)
+	require.Equal(t, "k2", string(zrange.Members[1].Key))
+
+	res, err := client.Command(ctx, &tredspb.CommandRequest{Cmd: "MGET", Args: [][]byte{[]byte("user:2"), []byte("missing")}})
+	require.NoError(t, err)
+	values := res.Result.GetList().Values
+	require.Equal(t, "b", string(values[0].GetBulk()))
+	require.Nil(t, values[1].Kind)
+
+	_, err = client.Command(ctx, &tredspb.CommandRequest{Cmd: "MULTI"})
+	require.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func TestGRPCAuth(t *testing.T) {
+	ts, _ := newTestServer(t)
+	require.NoError(t, ts.acl.SetUser(acl.DefaultUser, []string{"resetpass", ">secret"}))
+	require.NoError(t, ts.acl.SetUser("reader", []string{"on", ">pw", "+@read", "~user:"}))
+	client := newTestGRPCClient(t, ts)
+
+	_, err := client.Get(context.Background(), &tredspb.GetRequest{Key: []byte("user:1")})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	credentials := "Basic " + base64.StdEncoding.EncodeToString([]byte("reader:pw"))
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", credentials)
+	_, err = client.Get(ctx, &tredspb.GetRequest{Key: []byte("user:1")})
+	require.NoError(t, err)
+	_, err = client.Get(ctx, &tredspb.GetRequest{Key: []byte("order:1")})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestGRPCWatch(t *testing.T) {
+	ts, _ := newTestServer(t)
+	client := newTestGRPCClient(t, ts)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.Watch(ctx, &tredspb.WatchRequest{Channels: []string{"news"}})
+	require.NoError(t, err)
+	// The watcher is registered once the call reached the server
+	require.Eventually(t, func() bool {
+		ts.mu.Lock()
+		defer ts.mu.Unlock()
+		return len(ts.watchers) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	ts.mu.Lock()
+	for id := range ts.connectionSubscription {
+		require.True(t, ts.deliver(id, Message, "news", "news", "hello"))
+	}
+	ts.mu.Unlock()
+
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, "news", event.Channel)
+	require.Equal(t, "hello", string(event.Message))
+
+	cancel()
+	require.Eventually(t, func() bool {
+		ts.mu.Lock()
+		defer ts.mu.Unlock()
+		return len(ts.watchers) == 0 && len(ts.connectionSubscription) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestGRPCTimedOutCommandInflight(t *testing.T) {
+	ts, _ := newTestServer(t)
+	release := make(chan struct{})
+	require.NoError(t, ts.tredsCommandRegistry.Add(&commands.CommandRegistration{
+		Name: "BLOCK",
+		Spec: commands.Spec{Flags: []string{commands.FlagReadonly}},
+		Execute: func(args []string, _ store.Store) string {
+			<-release
+			return resp.EncodeSimpleString("OK")
+		},
+	}))
+	client := newTestGRPCClient(t, ts)
+
+	// The call times out while the command still runs, a shutdown keeps waiting for it
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.Command(ctx, &tredspb.CommandRequest{Cmd: "BLOCK"})
+	require.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	require.Equal(t, int64(1), ts.inflight.Load())
+
+	close(release)
+	require.NoError(t, ts.drain(context.Background()))
+}
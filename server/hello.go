@@ -0,0 +1,123 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/raft"
+	"github.com/panjf2000/gnet/v2"
+	"treds/acl"
+	"treds/commands"
+	"treds/resp"
+)
+
+const HelloCommandName = "HELLO"
+
+// Version is the server version reported to clients
+const Version = "0.1.0"
+
+func RegisterHelloCommand(r ServerCommandRegistry) {
+	r.Add(&ServerCommandRegistration{
+		Name:       HelloCommandName,
+		Execute:    executeHello(),
+		Categories: []string{acl.CategoryConnection},
+		Spec: commands.Spec{
+			Summary: "Switches the connection to RESP2 or RESP3, optionally authenticates and names it, and returns server details",
+			Group:   commands.GroupConnection,
+			Args: []commands.Arg{
+				{Name: "protover", Type: commands.ArgInteger, Optional: true},
+				{Name: "option", Type: commands.ArgString, Optional: true, Multiple: true},
+			},
+		},
+	})
+}
+
+// executeHello switches the protocol of the connection, HELLO [protover [AUTH username password] [SETNAME name]]
+// Without a version the current protocol is kept and only the server details are returned. Nothing changes
+// on the connection unless every option is valid and the credentials are accepted.
+func executeHello() ExecutionHook {
+	return func(inp string, ts *Server, c gnet.Conn) gnet.Action {
+		_, args, err := parseCommand(inp)
+		if err != nil {
+			ts.RespondErr(c, err)
+			return gnet.None
+		}
+
+		client := getClientConn(c)
+		protocol := client.protocol
+		if len(args) > 0 {
+			protocol, err = strconv.Atoi(args[0])
+			if err != nil {
+				ts.RespondErr(c, fmt.Errorf("protocol version is not an integer or out of range"))
+				return gnet.None
+			}
+			if protocol != resp.RESP2 && protocol != resp.RESP3 {
+				ts.RespondErr(c, fmt.Errorf("NOPROTO unsupported protocol version"))
+				return gnet.None
+			}
+		}
+
+		var username, name string
+		var authenticate, setName bool
+		for i := 1; i < len(args); i++ {
+			switch option := strings.ToUpper(args[i]); {
+			case option == "AUTH" && i+2 < len(args):
+				username, authenticate = args[i+1], true
+				if _, err = ts.acl.Authenticate(username, args[i+2]); err != nil {
+					ts.RespondErr(c, err)
+					return gnet.None
+				}
+				i += 2
+			case option == "SETNAME" && i+1 < len(args):
+				name, setName = args[i+1], true
+				if err = validateClientName(name); err != nil {
+					ts.RespondErr(c, err)
+					return gnet.None
+				}
+				i++
+			default:
+				ts.RespondErr(c, fmt.Errorf("syntax error in HELLO option '%s'", args[i]))
+				return gnet.None
+			}
+		}
+
+		client.protocol = protocol
+		if authenticate {
+			client.user = username
+		}
+		if setName {
+			client.name = name
+		}
+
+		role, leader := "follower", ""
+		if ts.raft != nil {
+			if ts.raft.State() == raft.Leader {
+				role = "leader"
+			}
+			// leader is where clients reach the leader, empty while there is no leader
+			leaderAddr, _ := ts.raft.LeaderWithID()
+			leader, _ = ts.convertRaftToTredsAddress(string(leaderAddr))
+		}
+
+		fields := []interface{}{
+			"server", "treds",
+			"version", Version,
+			"proto", client.protocol,
+			"mode", "cluster",
+			"role", role,
+			"leader", leader,
+			"modules", []interface{}{},
+		}
+
+		res := resp.EncodeArray(fields)
+		if client.protocol == resp.RESP3 {
+			res = resp.EncodeValueMap(fields)
+		}
+		_, errConn := c.Write([]byte(res))
+		if errConn != nil {
+			logger.Error("Error occurred writing to connection", "error", errConn)
+		}
+		return gnet.None
+	}
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"treds/resp"
+)
+
+func TestHelloOptions(t *testing.T) {
+	ts, _ := newTestServer(t)
+	require.NoError(t, ts.acl.SetUser("alice", []string{"on", ">pw", "allkeys", "+@all"}))
+	conn := &fakeConn{remote: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 5000}}
+	conn.SetContext(newClientConn(conn))
+	client := getClientConn(conn)
+	hello := func(args ...string) string {
+		conn.written = nil
+		executeHello()(resp.EncodeStringArray(append([]string{HelloCommandName}, args...)), ts, conn)
+		return string(conn.written)
+	}
+
+	// Nothing changes when the credentials are refused
+	require.True(t, strings.HasPrefix(hello("3", "AUTH", "alice", "nope", "SETNAME", "worker"), "-WRONGPASS"))
+	require.Equal(t, resp.RESP2, client.protocol)
+	require.Empty(t, client.user)
+	require.Empty(t, client.name)
+
+	res := hello("3", "auth", "alice", "pw", "SETNAME", "worker")
+	require.True(t, strings.HasPrefix(res, "%"), res)
+	// Clients routing writes find the leader without INFO, which needs the admin category
+	require.Contains(t, res, "$6\r\nleader\r\n$0\r\n\r\n")
+	require.Equal(t, resp.RESP3, client.protocol)
+	require.Equal(t, "alice", client.user)
+	require.Equal(t, "worker", client.name)
+
+	for _, args := range [][]string{
+		{"2", "SETNAME"},
+		{"2", "AUTH", "alice"},
+		{"2", "FOO"},
+		{"2", "SETNAME", "bad name"},
+	} {
+		require.True(t, strings.HasPrefix(hello(args...), "-"), args)
+		require.Equal(t, resp.RESP3, client.protocol, args)
+		require.Equal(t, "worker", client.name, args)
+	}
+
+	require.True(t, strings.HasPrefix(hello("2"), "*"))
+	require.Equal(t, resp.RESP2, client.protocol)
+}
@@ -0,0 +1,372 @@
+package server
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"treds/commands"
+	"treds/resp"
+)
+
+// httpCommand is the body of POST /v1/cmd
+type httpCommand struct {
+	Cmd  string   `json:"cmd"`
+	Args []string `json:"args"`
+}
+
+// httpReply is the body of every gateway response, Result holds the reply converted by resp.ParseReply
+type httpReply struct {
+	Result interface{} `json:"result"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// HTTPHandler serves the store commands as JSON.
+// POST /v1/cmd runs any store command, the other routes are shortcuts for the KV, hash, collection and vector stores.
+// Requests are authenticated as ACL users with HTTP basic auth, or as the default user when it has no password.
+func (ts *Server) HTTPHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/cmd", ts.handleHTTPCommand)
+	mux.HandleFunc("GET /v1/pubsub", ts.handleWebSocket)
+
+	mux.HandleFunc("GET /v1/kv", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		cursor := query.Get("cursor")
+		if cursor == "" {
+			cursor = "0"
+		}
+		args := []string{commands.PrefixScanCommand, cursor, query.Get("prefix")}
+		if count := query.Get("count"); count != "" {
+			args = append(args, count)
+		}
+		ts.serveHTTPCommand(w, r, args, nil)
+	})
+	mux.HandleFunc("GET /v1/kv/{key...}", func(w http.ResponseWriter, r *http.Request) {
+		ts.serveHTTPCommand(w, r, []string{commands.GetCommand, r.PathValue("key")}, nil)
+	})
+	mux.HandleFunc("PUT /v1/kv/{key...}", func(w http.ResponseWriter, r *http.Request) {
+		// The body is the value as is, so values are binary safe
+		value, err := readHTTPBody(w, r)
+		if err != nil {
+			writeHTTPError(w, httpBodyStatus(err), err)
+			return
+		}
+		ts.serveHTTPCommand(w, r, []string{commands.SetCommand, r.PathValue("key"), string(value)}, nil)
+	})
+	mux.HandleFunc("DELETE /v1/kv/{key...}", func(w http.ResponseWriter, r *http.Request) {
+		ts.serveHTTPCommand(w, r, []string{commands.DeleteCommand, r.PathValue("key")}, nil)
+	})
+
+	mux.HandleFunc("GET /v1/hashes/{key}", func(w http.ResponseWriter, r *http.Request) {
+		ts.serveHTTPCommand(w, r, []string{commands.HGetAllCommand, r.PathValue("key")}, nil)
+	})
+	mux.HandleFunc("GET /v1/hashes/{key}/{field}", func(w http.ResponseWriter, r *http.Request) {
+		ts.serveHTTPCommand(w, r, []string{commands.HGetCommand, r.PathValue("key"), r.PathValue("field")}, nil)
+	})
+	mux.HandleFunc("PUT /v1/hashes/{key}", func(w http.ResponseWriter, r *http.Request) {
+		var fields map[string]string
+		if err := decodeHTTPBody(w, r, &fields); err != nil {
+			writeHTTPError(w, httpBodyStatus(err), err)
+			return
+		}
+		args := []string{commands.HSetCommand, r.PathValue("key")}
+		for field, value := range fields {
+			args = append(args, field, value)
+		}
+		ts.serveHTTPCommand(w, r, args, nil)
+	})
+	mux.HandleFunc("DELETE /v1/hashes/{key}/{field}", func(w http.ResponseWriter, r *http.Request) {
+		ts.serveHTTPCommand(w, r, []string{commands.HDelCommand, r.PathValue("key"), r.PathValue("field")}, nil)
+	})
+
+	mux.HandleFunc("POST /v1/collections/{name}", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Schema  json.RawMessage `json:"schema"`
+			Indexes json.RawMessage `json:"indexes"`
+		}
+		if err := decodeHTTPBody(w, r, &body); err != nil {
+			writeHTTPError(w, httpBodyStatus(err), err)
+			return
+		}
+		args := []string{commands.DCreateCollection, r.PathValue("name"), string(body.Schema)}
+		if len(body.Indexes) > 0 {
+			args = append(args, string(body.Indexes))
+		}
+		ts.serveHTTPCommand(w, r, args, nil)
+	})
+	mux.HandleFunc("DELETE /v1/collections/{name}", func(w http.ResponseWriter, r *http.Request) {
+		ts.serveHTTPCommand(w, r, []string{commands.DDropCollection, r.PathValue("name")}, nil)
+	})
+	mux.HandleFunc("POST /v1/collections/{name}/documents", func(w http.ResponseWriter, r *http.Request) {
+		document, err := readHTTPJSON(w, r)
+		if err != nil {
+			writeHTTPError(w, httpBodyStatus(err), err)
+			return
+		}
+		ts.serveHTTPCommand(w, r, []string{commands.DInsert, r.PathValue("name"), string(document)}, nil)
+	})
+	mux.HandleFunc("POST /v1/collections/{name}/query", func(w http.ResponseWriter, r *http.Request) {
+		query, err := readHTTPJSON(w, r)
+		if err != nil {
+			writeHTTPError(w, httpBodyStatus(err), err)
+			return
+		}
+		// Documents are returned as JSON strings, they are embedded as objects
+		ts.serveHTTPCommand(w, r, []string{commands.DQuery, r.PathValue("name"), string(query)}, embedJSONStrings)
+	})
+
+	mux.HandleFunc("POST /v1/vectors/{name}", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			MaxNeighbors int     `json:"maxNeighbors"`
+			LevelFactor  float64 `json:"levelFactor"`
+			EfSearch     int     `json:"efSearch"`
+		}
+		if err := decodeHTTPBody(w, r, &body); err != nil {
+			writeHTTPError(w, httpBodyStatus(err), err)
+			return
+		}
+		ts.serveHTTPCommand(w, r, []string{commands.VCreate, r.PathValue("name"), strconv.Itoa(body.MaxNeighbors),
+			strconv.FormatFloat(body.LevelFactor, 'f', -1, 64), strconv.Itoa(body.EfSearch)}, nil)
+	})
+	mux.HandleFunc("POST /v1/vectors/{name}/vectors", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Vector []float64 `json:"vector"`
+		}
+		if err := decodeHTTPBody(w, r, &body); err != nil {
+			writeHTTPError(w, httpBodyStatus(err), err)
+			return
+		}
+		args := append([]string{commands.VInsert, r.PathValue("name")}, formatFloats(body.Vector)...)
+		ts.serveHTTPCommand(w, r, args, nil)
+	})
+	mux.HandleFunc("POST /v1/vectors/{name}/search", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Vector []float64 `json:"vector"`
+			K      int       `json:"k"`
+		}
+		if err := decodeHTTPBody(w, r, &body); err != nil {
+			writeHTTPError(w, httpBodyStatus(err), err)
+			return
+		}
+		args := append([]string{commands.VSearch, r.PathValue("name")}, formatFloats(body.Vector)...)
+		args = append(args, strconv.Itoa(body.K))
+		ts.serveHTTPCommand(w, r, args, nil)
+	})
+	mux.HandleFunc("DELETE /v1/vectors/{name}/vectors/{id}", func(w http.ResponseWriter, r *http.Request) {
+		ts.serveHTTPCommand(w, r, []string{commands.VDelete, r.PathValue("name"), r.PathValue("id")}, nil)
+	})
+	return mux
+}
+
+func (ts *Server) handleHTTPCommand(w http.ResponseWriter, r *http.Request) {
+	var command httpCommand
+	if err := decodeHTTPBody(w, r, &command); err != nil {
+		writeHTTPError(w, httpBodyStatus(err), err)
+		return
+	}
+	if command.Cmd == "" {
+		writeHTTPError(w, http.StatusBadRequest, fmt.Errorf("cmd is required"))
+		return
+	}
+	ts.serveHTTPCommand(w, r, append([]string{command.Cmd}, command.Args...), nil)
+}
+
+// serveHTTPCommand runs the store command args the same way commands of RESP clients are run,
+// transform can rewrite the parsed reply before it is written
+func (ts *Server) serveHTTPCommand(w http.ResponseWriter, r *http.Request, args []string, transform func(interface{}) interface{}) {
+	ts.stats.CommandsProcessed.Add(1)
+	name := ts.metricCommand(args[0])
+	defer ts.metrics.observeCommand(name, time.Now())
+	fail := func(status int, err error) {
+		ts.metrics.commandError(name)
+		writeHTTPError(w, status, err)
+	}
+	username, status, err := ts.httpUser(r)
+	if err != nil {
+		if status == http.StatusUnauthorized {
+			w.Header().Set("WWW-Authenticate", `Basic realm="treds"`)
+		}
+		fail(status, err)
+		return
+	}
+
+	command := args[0]
+	if _, err = ts.tredsCommandRegistry.Retrieve(strings.ToUpper(command)); err != nil {
+		// Server commands work on a RESP connection, only store commands are served
+		fail(http.StatusNotFound, fmt.Errorf("unknown command '%s'", command))
+		return
+	}
+	if err = ts.authorizeUser(username, command, args[1:]); err != nil {
+		status = http.StatusForbidden
+		if strings.HasPrefix(err.Error(), "NOAUTH") {
+			status = http.StatusUnauthorized
+		}
+		fail(status, err)
+		return
+	}
+	ts.feedMonitors(r.RemoteAddr, command, args[1:])
+
+	// RESP3 replies keep maps, doubles, booleans and nulls apart, so they convert to JSON without guessing
+	res, err := ts.runCommand(resp.EncodeStringArray(args), command, args[1:], resp.RESP3)
+	if err != nil {
+		fail(http.StatusBadRequest, err)
+		return
+	}
+	value, err := resp.ParseReply(bufio.NewReader(strings.NewReader(res)))
+	if err != nil {
+		fail(http.StatusInternalServerError, err)
+		return
+	}
+	if replyErr, ok := value.(resp.ReplyError); ok {
+		fail(http.StatusBadRequest, replyErr)
+		return
+	}
+	if transform != nil {
+		value = transform(value)
+	}
+	writeHTTPReply(w, http.StatusOK, httpReply{Result: value})
+}
+
+// httpUser returns the ACL user of the request, from basic auth or the default user
+func (ts *Server) httpUser(r *http.Request) (string, int, error) {
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		if user, noAuth := ts.acl.NoAuthUser(); noAuth {
+			return user.Name, http.StatusOK, nil
+		}
+		return "", http.StatusUnauthorized, fmt.Errorf("NOAUTH Authentication required.")
+	}
+	if _, err := ts.acl.Authenticate(username, password); err != nil {
+		return "", http.StatusUnauthorized, err
+	}
+	return username, http.StatusOK, nil
+}
+
+// readHTTPBody reads the body of r, failing with an *http.MaxBytesError when it is longer than httpMaxBodyLength
+func readHTTPBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	return io.ReadAll(http.MaxBytesReader(w, r.Body, httpMaxBodyLength))
+}
+
+// httpBodyStatus is the status of the error reading or decoding a request body
+func httpBodyStatus(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+func decodeHTTPBody(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	body, err := readHTTPBody(w, r)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("invalid JSON body: %v", err)
+	}
+	return nil
+}
+
+// readHTTPJSON returns the body after checking it is a JSON document
+func readHTTPJSON(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	body, err := readHTTPBody(w, r)
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(body) {
+		return nil, fmt.Errorf("invalid JSON body")
+	}
+	return body, nil
+}
+
+// embedJSONStrings replaces the strings of a reply holding JSON documents with the documents
+func embedJSONStrings(value interface{}) interface{} {
+	elements, ok := value.([]interface{})
+	if !ok {
+		return value
+	}
+	for i, element := range elements {
+		if s, isString := element.(string); isString && json.Valid([]byte(s)) {
+			elements[i] = json.RawMessage(s)
+		}
+	}
+	return elements
+}
+
+func formatFloats(values []float64) []string {
+	res := make([]string, 0, len(values))
+	for _, v := range values {
+		res = append(res, strconv.FormatFloat(v, 'f', -1, 64))
+	}
+	return res
+}
+
+func writeHTTPError(w http.ResponseWriter, status int, err error) {
+	writeHTTPReply(w, status, httpReply{Error: err.Error()})
+}
+
+func writeHTTPReply(w http.ResponseWriter, status int, reply httpReply) {
+	body, err := json.Marshal(reply)
+	if err != nil {
+		status = http.StatusInternalServerError
+		body, _ = json.Marshal(httpReply{Error: err.Error()})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, err = w.Write(body); err != nil {
+		logger.Error("Error occurred writing HTTP response", "error", err)
+	}
+}
+
+var (
+	// httpReadHeaderTimeout, httpReadTimeout and httpIdleTimeout keep slow or idle clients of the HTTP gateway and the
+	// metrics server from holding connections open, WebSocket connections are not bound by them once upgraded
+	httpReadHeaderTimeout = 10 * time.Second
+	httpReadTimeout       = 30 * time.Second
+	httpIdleTimeout       = 2 * time.Minute
+	// httpMaxBodyLength bounds request bodies, a value can not be longer than a bulk string
+	httpMaxBodyLength int64 = resp.MaxBulkLength
+)
+
+// newHTTPServer returns an http.Server serving handler with the timeouts of the HTTP gateway
+func newHTTPServer(handler http.Handler) *http.Server {
+	return &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: httpReadHeaderTimeout,
+		ReadTimeout:       httpReadTimeout,
+		IdleTimeout:       httpIdleTimeout,
+	}
+}
+
+// StartHTTP serves the HTTP gateway on addr in the background, over TLS when it is enabled
+func (ts *Server) StartHTTP(addr string, tlsOptions *TLSOptions) (*http.Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if tlsOptions.Enabled() {
+		config, errTLS := tlsOptions.ServerConfig(tlsOptions.AuthClients)
+		if errTLS != nil {
+			_ = listener.Close()
+			return nil, errTLS
+		}
+		listener = tls.NewListener(listener, config)
+	}
+	httpServer := newHTTPServer(ts.HTTPHandler())
+	ts.httpServer = httpServer
+	go func() {
+		if errServe := httpServer.Serve(listener); errServe != nil && !errors.Is(errServe, http.ErrServerClosed) {
+			logger.Error("HTTP gateway stopped", "error", errServe)
+		}
+	}()
+	logger.Info("HTTP gateway listening", "addr", addr)
+	return httpServer, nil
+}
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asheshvidyut/prefix-search-optimized-radix"
+	"github.com/panjf2000/gnet/v2"
+	"github.com/stretchr/testify/require"
+	"treds/acl"
+	"treds/commands"
+	"treds/resp"
+	"treds/store"
+)
+
+func newTestServer(t *testing.T) (*Server, *store.ShardedStore) {
+	registry := commands.NewRegistry()
+	commands.RegisterCommands(registry)
+	tredsStore := store.NewShardedStore(1, store.ShardByHash, store.DefaultShardDelimiter)
+	serverRegistry := NewRegistry()
+	RegisterCommands(serverRegistry)
+	users := acl.New()
+	return &Server{
+		tredsCommandRegistry:       registry,
+		tredsServerCommandRegistry: serverRegistry,
+		fsm:                        NewTredsFsm(registry, tredsStore, users),
+		acl:                        users,
+		connectionMap:              make(map[uint64]gnet.Conn),
+		connectionSubscription:     make(map[uint64]map[string]struct{}),
+		watchers:                   make(map[uint64]*watcher),
+		channelSubscriptionData:    radix.New(),
+	}, tredsStore
+}
+
+func doHTTP(t *testing.T, handler http.Handler, req *http.Request) (int, httpReply) {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	var reply httpReply
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &reply))
+	return rec.Code, reply
+}
+
+func TestHTTPReads(t *testing.T) {
+	ts, tredsStore := newTestServer(t)
+	require.NoError(t, tredsStore.Set("user:1", "alice"))
+	require.NoError(t, tredsStore.HSet("h", []string{"f1", "v1", "f2", "v2"}))
+	handler := ts.HTTPHandler()
+
+	status, reply := doHTTP(t, handler, httptest.NewRequest(http.MethodGet, "/v1/kv/user:1", nil))
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, "alice", reply.Result)
+
+	status, reply = doHTTP(t, handler, httptest.NewRequest(http.MethodGet, "/v1/kv/missing", nil))
+	require.Equal(t, http.StatusOK, status)
+	require.Nil(t, reply.Result)
+
+	status, reply = doHTTP(t, handler, httptest.NewRequest(http.MethodGet, "/v1/hashes/h", nil))
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, map[string]interface{}{"f1": "v1", "f2": "v2"}, reply.Result)
+
+	body := `{"cmd":"MGET","args":["user:1","missing"]}`
+	status, reply = doHTTP(t, handler, httptest.NewRequest(http.MethodPost, "/v1/cmd", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, []interface{}{"alice", nil}, reply.Result)
+
+	status, reply = doHTTP(t, handler, httptest.NewRequest(http.MethodPost, "/v1/cmd", strings.NewReader(`{"cmd":"MULTI"}`)))
+	require.Equal(t, http.StatusNotFound, status)
+	require.NotEmpty(t, reply.Error)
+
+	status, _ = doHTTP(t, handler, httptest.NewRequest(http.MethodPost, "/v1/cmd", strings.NewReader(`{"cmd":"GET"}`)))
+	require.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestHTTPAuth(t *testing.T) {
+	ts, _ := newTestServer(t)
+	require.NoError(t, ts.acl.SetUser(acl.DefaultUser, []string{"resetpass", ">secret"}))
+	require.NoError(t, ts.acl.SetUser("reader", []string{"on", ">pw", "+@read", "~user:"}))
+	handler := ts.HTTPHandler()
+
+	status, _ := doHTTP(t, handler, httptest.NewRequest(http.MethodGet, "/v1/kv/user:1", nil))
+	require.Equal(t, http.StatusUnauthorized, status)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/kv/user:1", nil)
+	req.SetBasicAuth("reader", "pw")
+	status, _ = doHTTP(t, handler, req)
+	require.Equal(t, http.StatusOK, status)
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/kv/order:1", nil)
+	req.SetBasicAuth("reader", "pw")
+	status, _ = doHTTP(t, handler, req)
+	require.Equal(t, http.StatusForbidden, status)
+
+	req = httptest.NewRequest(http.MethodPut, "/v1/kv/user:1", strings.NewReader("bob"))
+	req.SetBasicAuth("reader", "pw")
+	status, _ = doHTTP(t, handler, req)
+	require.Equal(t, http.StatusForbidden, status)
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/kv/user:1", nil)
+	req.SetBasicAuth("reader", "wrong")
+	status, _ = doHTTP(t, handler, req)
+	require.Equal(t, http.StatusUnauthorized, status)
+}
+
+func TestHTTPBodyTooLarge(t *testing.T) {
+	ts, _ := newTestServer(t)
+	handler := ts.HTTPHandler()
+	command := `{"cmd":"GET","args":["k"]}`
+	httpMaxBodyLength = int64(len(command))
+	t.Cleanup(func() { httpMaxBodyLength = resp.MaxBulkLength })
+
+	status, _ := doHTTP(t, handler, httptest.NewRequest(http.MethodPost, "/v1/cmd", strings.NewReader(command)))
+	require.Equal(t, http.StatusOK, status)
+	status, _ = doHTTP(t, handler, httptest.NewRequest(http.MethodPost, "/v1/cmd", strings.NewReader(command+" ")))
+	require.Equal(t, http.StatusRequestEntityTooLarge, status)
+	status, _ = doHTTP(t, handler, httptest.NewRequest(http.MethodPut, "/v1/kv/k", strings.NewReader(command+" ")))
+	require.Equal(t, http.StatusRequestEntityTooLarge, status)
+}
+
+func TestHTTPReadHeaderTimeout(t *testing.T) {
+	ts, _ := newTestServer(t)
+	httpReadHeaderTimeout = 100 * time.Millisecond
+	t.Cleanup(func() { httpReadHeaderTimeout = 10 * time.Second })
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	httpServer := newHTTPServer(ts.HTTPHandler())
+	go func() { _ = httpServer.Serve(listener) }()
+	defer httpServer.Close()
+
+	// A client never finishing its headers is disconnected
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("GET /v1/kv/k HTTP/1.1\r\nHost: treds\r\n"))
+	require.NoError(t, err)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = io.ReadAll(conn)
+	require.NoError(t, err)
+}
@@ -0,0 +1,178 @@
+package server
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/panjf2000/gnet/v2"
+	"treds/acl"
+	"treds/commands"
+	"treds/resp"
+)
+
+const InfoCommandName = "INFO"
+
+// infoSections are the sections of INFO in the order they are shown
+var infoSections = []string{"server", "clients", "memory", "keyspace", "raft", "stats"}
+
+func RegisterInfoCommand(r ServerCommandRegistry) {
+	r.Add(&ServerCommandRegistration{
+		Name:       InfoCommandName,
+		Execute:    executeInfo(),
+		Categories: []string{acl.CategoryAdmin},
+		Spec: commands.Spec{
+			Summary: "Describes the node",
+			Group:   commands.GroupServer,
+			Args: []commands.Arg{
+				{Name: "section", Type: commands.ArgString, Optional: true, Multiple: true},
+			},
+		},
+	})
+}
+
+// executeInfo runs INFO [section [section ...]], describing this node only
+func executeInfo() ExecutionHook {
+	return func(inp string, ts *Server, c gnet.Conn) gnet.Action {
+		_, args, err := parseCommand(inp)
+		if err != nil {
+			ts.RespondErr(c, err)
+			return gnet.None
+		}
+		_, errConn := c.Write([]byte(resp.EncodeBulkString(ts.info(args))))
+		if errConn != nil {
+			logger.Error("Error occurred writing to connection", "error", errConn)
+		}
+		return gnet.None
+	}
+}
+
+// info returns the requested sections, every section without any or with all, default or everything.
+// It is called with ts.mu held.
+func (ts *Server) info(sections []string) string {
+	requested := make(map[string]bool)
+	for _, section := range sections {
+		section = strings.ToLower(section)
+		if section == "all" || section == "default" || section == "everything" {
+			requested = nil
+			break
+		}
+		requested[section] = true
+	}
+
+	var b strings.Builder
+	for _, section := range infoSections {
+		if len(requested) > 0 && !requested[section] {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("\r\n")
+		}
+		b.WriteString("# " + strings.ToUpper(section[:1]) + section[1:] + "\r\n")
+		for _, field := range ts.infoSection(section) {
+			b.WriteString(field[0] + ":" + field[1] + "\r\n")
+		}
+	}
+	return b.String()
+}
+
+func (ts *Server) infoSection(section string) [][2]string {
+	switch section {
+	case "server":
+		uptime := time.Since(ts.started)
+		return [][2]string{
+			{"treds_version", Version},
+			{"go_version", runtime.Version()},
+			{"os", runtime.GOOS + " " + runtime.GOARCH},
+			{"process_id", fmt.Sprint(os.Getpid())},
+			{"run_id", string(ts.id)},
+			{"tcp_port", fmt.Sprint(ts.Port)},
+			{"uptime_in_seconds", fmt.Sprint(int64(uptime.Seconds()))},
+			{"uptime_in_days", fmt.Sprint(int64(uptime.Hours() / 24))},
+		}
+	case "clients":
+		pubsub := 0
+		for _, conn := range ts.connectionMap {
+			if getClientConn(conn).pubsub.Load() {
+				pubsub++
+			}
+		}
+		return [][2]string{
+			{"connected_clients", fmt.Sprint(len(ts.connectionMap))},
+			{"pubsub_clients", fmt.Sprint(pubsub)},
+			{"watchers", fmt.Sprint(len(ts.watchers))},
+			{"multi_clients", fmt.Sprint(len(ts.clientTransaction))},
+			{"maxclients", fmt.Sprint(ts.maxClients)},
+		}
+	case "memory":
+		var m runtime.MemStats
+		runtime.ReadMemStats(&m)
+		return [][2]string{
+			{"used_memory", fmt.Sprint(m.HeapAlloc)},
+			{"used_memory_human", fmt.Sprintf("%.2fM", float64(m.HeapAlloc)/(1<<20))},
+			{"heap_inuse", fmt.Sprint(m.HeapInuse)},
+			{"heap_idle", fmt.Sprint(m.HeapIdle)},
+			{"heap_released", fmt.Sprint(m.HeapReleased)},
+			{"heap_objects", fmt.Sprint(m.HeapObjects)},
+			{"total_alloc", fmt.Sprint(m.TotalAlloc)},
+			{"sys", fmt.Sprint(m.Sys)},
+			{"num_gc", fmt.Sprint(m.NumGC)},
+			{"gc_pause_total_ns", fmt.Sprint(m.PauseTotalNs)},
+			{"goroutines", fmt.Sprint(runtime.NumGoroutine())},
+		}
+	case "keyspace":
+		keyspace := ts.fsm.tredsStore.Keyspace()
+		return [][2]string{
+			{"keys", fmt.Sprint(keyspace.Keys)},
+			{"sorted_maps", fmt.Sprint(keyspace.SortedMaps)},
+			{"lists", fmt.Sprint(keyspace.Lists)},
+			{"sets", fmt.Sprint(keyspace.Sets)},
+			{"hashes", fmt.Sprint(keyspace.Hashes)},
+			{"collections", fmt.Sprint(keyspace.Collections)},
+			{"vectors", fmt.Sprint(keyspace.Vectors)},
+			{"expires", fmt.Sprint(keyspace.Expires)},
+		}
+	case "raft":
+		if ts.raft == nil {
+			return nil
+		}
+		leaderAddr, leaderID := ts.raft.LeaderWithID()
+		// leader_client_addr is where clients reach the leader, it is empty while there is no leader
+		leaderClientAddr, _ := ts.convertRaftToTredsAddress(string(leaderAddr))
+		fields := [][2]string{
+			{"leader_id", string(leaderID)},
+			{"leader_addr", string(leaderAddr)},
+			{"leader_client_addr", leaderClientAddr},
+		}
+		var peers []string
+		for _, peer := range ts.raftPeers() {
+			peers = append(peers, string(peer.ID)+"@"+string(peer.Address))
+		}
+		fields = append(fields, [2]string{"peers", strings.Join(peers, ",")})
+		stats := ts.raft.Stats()
+		names := make([]string, 0, len(stats))
+		for name := range stats {
+			// The configuration is already described by peers
+			if name != "latest_configuration" {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fields = append(fields, [2]string{name, stats[name]})
+		}
+		return fields
+	case "stats":
+		return [][2]string{
+			{"total_connections_received", fmt.Sprint(ts.stats.ConnectionsReceived.Load())},
+			{"total_commands_processed", fmt.Sprint(ts.stats.CommandsProcessed.Load())},
+			{"rejected_connections", fmt.Sprint(ts.stats.RejectedConnections.Load())},
+			{"client_output_buffer_limit_disconnections", fmt.Sprint(ts.stats.OutputBufferLimitDisconnections.Load())},
+			{"expired_keys", fmt.Sprint(ts.fsm.tredsStore.Keyspace().ExpiredKeys)},
+		}
+	}
+	return nil
+}
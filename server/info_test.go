@@ -0,0 +1,29 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInfo(t *testing.T) {
+	ts, tredsStore := newTestServer(t)
+	require.NoError(t, tredsStore.Set("key", "value"))
+	ts.stats.CommandsProcessed.Add(2)
+
+	info := ts.info(nil)
+	for _, section := range []string{"# Server\r\n", "# Clients\r\n", "# Memory\r\n", "# Keyspace\r\n", "# Stats\r\n"} {
+		require.Contains(t, info, section)
+	}
+	require.Contains(t, info, "\r\nkeys:1\r\n")
+	require.Contains(t, info, "\r\ntotal_commands_processed:2\r\n")
+	require.Contains(t, ts.info([]string{"all"}), "# Memory\r\n")
+
+	info = ts.info([]string{"keyspace", "CLIENTS"})
+	require.True(t, strings.HasPrefix(info, "# Clients\r\nconnected_clients:0\r\n"))
+	require.Contains(t, info, "\r\n\r\n# Keyspace\r\n")
+	require.NotContains(t, info, "# Server")
+
+	require.Empty(t, ts.info([]string{"nope"}))
+}
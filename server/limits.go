@@ -0,0 +1,169 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/panjf2000/gnet/v2"
+	"treds/resp"
+)
+
+// Output buffer limit classes, clients subscribed to a channel are pubsub clients
+const (
+	ClientClassNormal = "normal"
+	ClientClassPubSub = "pubsub"
+)
+
+// OutputBufferLimit bounds the replies queued for a client which does not read them fast enough.
+// The client is disconnected as soon as HardBytes are queued, or when at least SoftBytes stay queued
+// for SoftDuration. A zero size disables the threshold.
+type OutputBufferLimit struct {
+	HardBytes    int
+	SoftBytes    int
+	SoftDuration time.Duration
+}
+
+var (
+	// DefaultOutputBufferLimitNormal does not limit clients sending requests, replies are bounded by their requests
+	DefaultOutputBufferLimitNormal = OutputBufferLimit{}
+	// DefaultOutputBufferLimitPubSub disconnects subscribers which can not keep up with the published messages
+	DefaultOutputBufferLimitPubSub = OutputBufferLimit{HardBytes: 32 << 20, SoftBytes: 8 << 20, SoftDuration: 60 * time.Second}
+)
+
+// String formats the limit the way ParseOutputBufferLimit reads it
+func (l OutputBufferLimit) String() string {
+	return fmt.Sprintf("%s %s %d", formatBytes(l.HardBytes), formatBytes(l.SoftBytes), int(l.SoftDuration.Seconds()))
+}
+
+// ParseOutputBufferLimit parses "<hard> <soft> <soft seconds>", sizes are bytes or have a kb, mb or gb suffix
+// like "32mb 8mb 60"
+func ParseOutputBufferLimit(s string) (OutputBufferLimit, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 3 {
+		return OutputBufferLimit{}, fmt.Errorf("invalid output buffer limit %q, expected '<hard> <soft> <soft seconds>'", s)
+	}
+	hard, err := parseBytes(fields[0])
+	if err != nil {
+		return OutputBufferLimit{}, err
+	}
+	soft, err := parseBytes(fields[1])
+	if err != nil {
+		return OutputBufferLimit{}, err
+	}
+	seconds, err := strconv.Atoi(fields[2])
+	if err != nil || seconds < 0 {
+		return OutputBufferLimit{}, fmt.Errorf("invalid output buffer soft limit seconds %q", fields[2])
+	}
+	return OutputBufferLimit{HardBytes: hard, SoftBytes: soft, SoftDuration: time.Duration(seconds) * time.Second}, nil
+}
+
+var byteUnits = []struct {
+	suffix string
+	size   int
+}{
+	{"gb", 1 << 30},
+	{"mb", 1 << 20},
+	{"kb", 1 << 10},
+	{"g", 1 << 30},
+	{"m", 1 << 20},
+	{"k", 1 << 10},
+	{"b", 1},
+}
+
+func parseBytes(s string) (int, error) {
+	lower := strings.ToLower(s)
+	unit := 1
+	for _, u := range byteUnits {
+		if number, ok := strings.CutSuffix(lower, u.suffix); ok {
+			lower, unit = number, u.size
+			break
+		}
+	}
+	n, err := strconv.Atoi(lower)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return n * unit, nil
+}
+
+func formatBytes(n int) string {
+	for _, u := range byteUnits[:3] {
+		if n != 0 && n%u.size == 0 {
+			return strconv.Itoa(n/u.size) + u.suffix
+		}
+	}
+	return strconv.Itoa(n)
+}
+
+// SetClientLimits sets the number of connections accepted, 0 for no limit, and the output buffer limits.
+// It is called with ts.mu held once the server started, the event loops read the limits without it.
+func (ts *Server) SetClientLimits(maxClients int, normal, pubsub OutputBufferLimit) {
+	ts.maxClients = maxClients
+	ts.outputBufferLimits.Store(&map[string]OutputBufferLimit{
+		ClientClassNormal: normal,
+		ClientClassPubSub: pubsub,
+	})
+}
+
+// acceptClient tells if a new connection is accepted, it fits in maxclients and the server is not shutting down.
+// It is called with ts.mu held.
+func (ts *Server) acceptClient() ([]byte, bool) {
+	if ts.shuttingDown.Load() {
+		ts.stats.RejectedConnections.Add(1)
+		return []byte(resp.EncodeError(ErrShuttingDown.Error())), false
+	}
+	if ts.maxClients > 0 && len(ts.connectionMap) >= ts.maxClients {
+		ts.stats.RejectedConnections.Add(1)
+		return []byte(resp.EncodeError("ERR max number of clients reached")), false
+	}
+	ts.stats.ConnectionsReceived.Add(1)
+	return nil, true
+}
+
+// checkOutputBuffer disconnects c if the replies queued for it exceed the limit of its class.
+// It must run on the event loop serving c, where the outbound buffer is owned.
+func (ts *Server) checkOutputBuffer(c gnet.Conn) gnet.Action {
+	client := getClientConn(c)
+	class := ClientClassNormal
+	if client.pubsub.Load() || client.monitor.Load() {
+		class = ClientClassPubSub
+	}
+	var limit OutputBufferLimit
+	if limits := ts.outputBufferLimits.Load(); limits != nil {
+		limit = (*limits)[class]
+	}
+	buffered := c.OutboundBuffered()
+
+	exceeded := limit.HardBytes > 0 && buffered >= limit.HardBytes
+	if !exceeded && limit.SoftBytes > 0 && buffered >= limit.SoftBytes {
+		if client.softLimitSince.IsZero() {
+			client.softLimitSince = time.Now()
+		}
+		exceeded = time.Since(client.softLimitSince) >= limit.SoftDuration
+	} else {
+		client.softLimitSince = time.Time{}
+	}
+	if !exceeded {
+		return gnet.None
+	}
+
+	ts.stats.OutputBufferLimitDisconnections.Add(1)
+	logger.Warn("Closing client for exceeding the output buffer limit", "client", client.id, "class", class, "queued", buffered)
+	return gnet.Close
+}
+
+// asyncWrite queues buf on the event loop serving c, used to write to clients of other loops.
+// The output buffer limit of c is checked once buf is queued.
+func (ts *Server) asyncWrite(c gnet.Conn, buf []byte) error {
+	return c.AsyncWrite(buf, func(c gnet.Conn, err error) error {
+		if err != nil {
+			return nil
+		}
+		if ts.checkOutputBuffer(c) == gnet.Close {
+			return c.Close()
+		}
+		return nil
+	})
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/panjf2000/gnet/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOutputBufferLimit(t *testing.T) {
+	limit, err := ParseOutputBufferLimit("32mb 8MB 60")
+	require.NoError(t, err)
+	require.Equal(t, DefaultOutputBufferLimitPubSub, limit)
+	require.Equal(t, "32mb 8mb 60", limit.String())
+
+	limit, err = ParseOutputBufferLimit("1000 512k 0")
+	require.NoError(t, err)
+	require.Equal(t, OutputBufferLimit{HardBytes: 1000, SoftBytes: 512 << 10}, limit)
+
+	for _, invalid := range []string{"", "1 2", "1mb x 3", "-1 0 0", "1 2 -3"} {
+		_, err = ParseOutputBufferLimit(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestMaxClients(t *testing.T) {
+	ts, _ := newTestClients(t, 2)
+	ts.SetClientLimits(2, DefaultOutputBufferLimitNormal, DefaultOutputBufferLimitPubSub)
+	out, ok := ts.acceptClient()
+	require.False(t, ok)
+	require.Contains(t, string(out), "max number of clients reached")
+	require.EqualValues(t, 1, ts.Stats().RejectedConnections.Load())
+
+	ts.SetClientLimits(0, DefaultOutputBufferLimitNormal, DefaultOutputBufferLimitPubSub)
+	_, ok = ts.acceptClient()
+	require.True(t, ok)
+}
+
+func TestCheckOutputBuffer(t *testing.T) {
+	ts, conns := newTestClients(t, 1)
+	conn := conns[0]
+	ts.SetClientLimits(0, OutputBufferLimit{}, OutputBufferLimit{HardBytes: 100, SoftBytes: 10, SoftDuration: time.Hour})
+
+	// Normal clients are not limited
+	conn.buffered = 1000
+	require.Equal(t, gnet.None, ts.checkOutputBuffer(conn))
+
+	getClientConn(conn).pubsub.Store(true)
+	conn.buffered = 50
+	require.Equal(t, gnet.None, ts.checkOutputBuffer(conn))
+	require.False(t, getClientConn(conn).softLimitSince.IsZero())
+
+	// Over the soft limit for longer than allowed
+	getClientConn(conn).softLimitSince = time.Now().Add(-2 * time.Hour)
+	require.Equal(t, gnet.Close, ts.checkOutputBuffer(conn))
+
+	// Draining the buffer resets the soft limit timer
+	conn.buffered = 0
+	require.Equal(t, gnet.None, ts.checkOutputBuffer(conn))
+	require.True(t, getClientConn(conn).softLimitSince.IsZero())
+
+	conn.buffered = 100
+	require.Equal(t, gnet.Close, ts.checkOutputBuffer(conn))
+	require.EqualValues(t, 2, ts.Stats().OutputBufferLimitDisconnections.Load())
+}
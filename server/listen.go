@@ -0,0 +1,99 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	tcpScheme  = "tcp://"
+	unixScheme = "unix://"
+)
+
+// DefaultUnixSocketPerm only lets the user running Treds connect to its unix sockets
+const DefaultUnixSocketPerm os.FileMode = 0700
+
+// ParseListenAddrs parses a comma separated list of listen addresses into gnet addresses.
+// Entries are tcp://host:port, unix:///path/to/socket or host:port, which is a TCP address.
+func ParseListenAddrs(list string) ([]string, error) {
+	addrs := make([]string, 0)
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if path, ok := strings.CutPrefix(entry, unixScheme); ok {
+			if path == "" {
+				return nil, fmt.Errorf("invalid listen address %s: missing socket path", entry)
+			}
+			addrs = append(addrs, entry)
+			continue
+		}
+		hostPort := strings.TrimPrefix(entry, tcpScheme)
+		if strings.Contains(hostPort, "://") {
+			return nil, fmt.Errorf("invalid listen address %s: only tcp and unix are supported", entry)
+		}
+		if _, _, err := net.SplitHostPort(hostPort); err != nil {
+			return nil, fmt.Errorf("invalid listen address %s: %v", entry, err)
+		}
+		addrs = append(addrs, tcpScheme+hostPort)
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no listen address")
+	}
+	return addrs, nil
+}
+
+// IsTCPAddr tells if a gnet address parsed by ParseListenAddrs is a TCP address
+func IsTCPAddr(addr string) bool {
+	return strings.HasPrefix(addr, tcpScheme)
+}
+
+// SetListenAddrs records the addresses the event loops listen on, unix sockets are created with unixSocketPerm
+func (ts *Server) SetListenAddrs(addrs []string, unixSocketPerm os.FileMode) {
+	ts.listenAddrs = addrs
+	ts.unixSocketPerm = unixSocketPerm
+}
+
+// StageUnixSockets returns the gnet addresses with every unix socket but the TLS backend created in a staging
+// directory next to it, which only the user running Treds can reach. OnBoot gives the sockets unixSocketPerm and
+// moves them into place, so they are never reachable with wider permissions whatever the umask of the process.
+func (ts *Server) StageUnixSockets(addrs []string) ([]string, error) {
+	staged := make([]string, 0, len(addrs))
+	ts.stagedSockets = make(map[string]string)
+	for _, addr := range addrs {
+		path, ok := strings.CutPrefix(addr, unixScheme)
+		if !ok || path == ts.tlsBackend {
+			staged = append(staged, addr)
+			continue
+		}
+		dir, err := os.MkdirTemp(filepath.Dir(path), ".treds-")
+		if err != nil {
+			return nil, fmt.Errorf("error staging unix socket %s: %v", path, err)
+		}
+		stagedPath := filepath.Join(dir, "sock")
+		ts.stagedSockets[stagedPath] = path
+		staged = append(staged, unixScheme+stagedPath)
+	}
+	return staged, nil
+}
+
+// publishUnixSockets gives the sockets created in their staging directory unixSocketPerm and moves them into place
+func (ts *Server) publishUnixSockets() {
+	for stagedPath, path := range ts.stagedSockets {
+		if err := os.Chmod(stagedPath, ts.unixSocketPerm); err != nil {
+			logger.Error("Error setting unix socket permissions", "path", path, "error", err)
+			continue
+		}
+		if err := os.Rename(stagedPath, path); err != nil {
+			logger.Error("Error moving unix socket into place", "path", path, "error", err)
+			continue
+		}
+		if err := os.Remove(filepath.Dir(stagedPath)); err != nil {
+			logger.Error("Error removing unix socket staging directory", "path", path, "error", err)
+		}
+	}
+}
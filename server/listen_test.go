@@ -0,0 +1,56 @@
+package server
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseListenAddrs(t *testing.T) {
+	addrs, err := ParseListenAddrs("127.0.0.1:7997, tcp://[::1]:7997,unix:///tmp/treds.sock")
+	require.NoError(t, err)
+	require.Equal(t, []string{"tcp://127.0.0.1:7997", "tcp://[::1]:7997", "unix:///tmp/treds.sock"}, addrs)
+	require.True(t, IsTCPAddr(addrs[0]))
+	require.False(t, IsTCPAddr(addrs[2]))
+
+	for _, invalid := range []string{"", "localhost", "udp://127.0.0.1:7997", "unix://"} {
+		_, err = ParseListenAddrs(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestStageUnixSockets(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "treds.sock")
+	ts := &Server{tlsBackend: filepath.Join(dir, "tls.sock")}
+	ts.SetListenAddrs([]string{unixScheme + path, "tcp://127.0.0.1:7997"}, 0o770)
+
+	addrs, err := ts.StageUnixSockets(append(ts.listenAddrs, unixScheme+ts.tlsBackend))
+	require.NoError(t, err)
+	require.Len(t, addrs, 3)
+	require.Equal(t, []string{"tcp://127.0.0.1:7997", unixScheme + ts.tlsBackend}, addrs[1:])
+	stagedPath := strings.TrimPrefix(addrs[0], unixScheme)
+	require.NotEqual(t, path, stagedPath)
+	info, err := os.Stat(filepath.Dir(stagedPath))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o700), info.Mode().Perm())
+
+	// The event loops create the socket in the staging directory, OnBoot moves it into place
+	listener, err := net.Listen("unix", stagedPath)
+	require.NoError(t, err)
+	defer listener.Close()
+	ts.publishUnixSockets()
+	info, err = os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.ModeSocket, info.Mode().Type())
+	require.Equal(t, os.FileMode(0o770), info.Mode().Perm())
+	_, err = os.Stat(filepath.Dir(stagedPath))
+	require.ErrorIs(t, err, os.ErrNotExist)
+	conn, err := net.Dial("unix", path)
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+}
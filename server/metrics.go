@@ -0,0 +1,232 @@
+package server
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// unknownCommand labels the metrics of commands missing from the registries, so clients can not
+// create a time series per misspelled command
+const unknownCommand = "UNKNOWN"
+
+// metrics are the Prometheus metrics of a node. A nil metrics records nothing.
+type metrics struct {
+	registry          *prometheus.Registry
+	commands          *prometheus.CounterVec
+	commandDuration   *prometheus.HistogramVec
+	commandErrors     *prometheus.CounterVec
+	raftApplyDuration prometheus.Histogram
+	raftLeaderChanges prometheus.Counter
+	snapshotDuration  prometheus.Histogram
+	snapshotSize      prometheus.Gauge
+}
+
+func newMetrics(ts *Server) *metrics {
+	m := &metrics{
+		registry: prometheus.NewRegistry(),
+		commands: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "treds_commands_total",
+			Help: "Commands received, by command.",
+		}, []string{"command"}),
+		commandDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "treds_command_duration_seconds",
+			Help:    "Time taken to run commands, by command.",
+			Buckets: prometheus.ExponentialBuckets(0.00001, 4, 10),
+		}, []string{"command"}),
+		commandErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "treds_command_errors_total",
+			Help: "Commands answered with an error, by command.",
+		}, []string{"command"}),
+		raftApplyDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "treds_raft_apply_duration_seconds",
+			Help:    "Time taken for a write to be committed and applied through Raft.",
+			Buckets: prometheus.ExponentialBuckets(0.0001, 4, 10),
+		}),
+		raftLeaderChanges: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "treds_raft_leader_changes_total",
+			Help: "Leader changes seen by this node.",
+		}),
+		snapshotDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "treds_raft_snapshot_duration_seconds",
+			Help:    "Time taken to create and persist a Raft snapshot.",
+			Buckets: prometheus.ExponentialBuckets(0.001, 4, 10),
+		}),
+		snapshotSize: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "treds_raft_snapshot_size_bytes",
+			Help: "Size of the last Raft snapshot.",
+		}),
+	}
+	m.registry.MustRegister(
+		m.commands, m.commandDuration, m.commandErrors,
+		m.raftApplyDuration, m.raftLeaderChanges, m.snapshotDuration, m.snapshotSize,
+		&serverCollector{ts: ts},
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+func (m *metrics) observeCommand(command string, start time.Time) {
+	if m == nil {
+		return
+	}
+	m.commands.WithLabelValues(command).Inc()
+	m.commandDuration.WithLabelValues(command).Observe(time.Since(start).Seconds())
+}
+
+func (m *metrics) commandError(command string) {
+	if m == nil {
+		return
+	}
+	m.commandErrors.WithLabelValues(command).Inc()
+}
+
+func (m *metrics) observeRaftApply(start time.Time) {
+	if m == nil {
+		return
+	}
+	m.raftApplyDuration.Observe(time.Since(start).Seconds())
+}
+
+func (m *metrics) observeSnapshot(start time.Time, size int) {
+	if m == nil {
+		return
+	}
+	m.snapshotDuration.Observe(time.Since(start).Seconds())
+	m.snapshotSize.Set(float64(size))
+}
+
+// watchLeader counts the leader changes of r
+func (m *metrics) watchLeader(r *raft.Raft) {
+	if m == nil {
+		return
+	}
+	observations := make(chan raft.Observation, 16)
+	r.RegisterObserver(raft.NewObserver(observations, false, func(o *raft.Observation) bool {
+		_, ok := o.Data.(raft.LeaderObservation)
+		return ok
+	}))
+	go m.countLeaderChanges(observations)
+}
+
+// countLeaderChanges counts the observations electing a leader other than the previous one. A node losing its
+// leader, or observing the same leader again, does not change the leader.
+func (m *metrics) countLeaderChanges(observations <-chan raft.Observation) {
+	var leader raft.ServerID
+	for o := range observations {
+		observed := o.Data.(raft.LeaderObservation).LeaderID
+		if observed == "" || observed == leader {
+			continue
+		}
+		leader = observed
+		m.raftLeaderChanges.Inc()
+	}
+}
+
+// metricCommand returns the label of command, its name in the registries
+func (ts *Server) metricCommand(command string) string {
+	name := strings.ToUpper(command)
+	if _, err := ts.tredsCommandRegistry.Retrieve(name); err == nil {
+		return name
+	}
+	if ts.isServerCommand(name) {
+		return name
+	}
+	return unknownCommand
+}
+
+var (
+	connectedClientsDesc = prometheus.NewDesc("treds_connected_clients", "Connections of RESP clients.", nil, nil)
+	subscribersDesc      = prometheus.NewDesc("treds_pubsub_subscribers", "RESP clients, gRPC and WebSocket watchers subscribed to a channel.", nil, nil)
+	keysDesc             = prometheus.NewDesc("treds_keys", "Keys, by store.", []string{"store"}, nil)
+	expiresDesc          = prometheus.NewDesc("treds_keys_with_expiry", "Keys having an expiry.", nil, nil)
+	expiredKeysDesc      = prometheus.NewDesc("treds_expired_keys_total", "Keys removed once they expired.", nil, nil)
+	commitIndexDesc      = prometheus.NewDesc("treds_raft_commit_index", "Index of the last committed Raft log entry.", nil, nil)
+	appliedIndexDesc     = prometheus.NewDesc("treds_raft_applied_index", "Index of the last Raft log entry applied to the store.", nil, nil)
+	applyLagDesc         = prometheus.NewDesc("treds_raft_apply_lag", "Committed Raft log entries not applied to the store yet.", nil, nil)
+)
+
+// serverCollector reads the state of the node when it is scraped
+type serverCollector struct {
+	ts *Server
+}
+
+func (sc *serverCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{connectedClientsDesc, subscribersDesc, keysDesc, expiresDesc, expiredKeysDesc,
+		commitIndexDesc, appliedIndexDesc, applyLagDesc} {
+		ch <- desc
+	}
+}
+
+func (sc *serverCollector) Collect(ch chan<- prometheus.Metric) {
+	ts := sc.ts
+	ts.mu.Lock()
+	clients, subscribers := len(ts.connectionMap), len(ts.connectionSubscription)
+	ts.mu.Unlock()
+	ch <- prometheus.MustNewConstMetric(connectedClientsDesc, prometheus.GaugeValue, float64(clients))
+	ch <- prometheus.MustNewConstMetric(subscribersDesc, prometheus.GaugeValue, float64(subscribers))
+
+	keyspace := ts.fsm.tredsStore.Keyspace()
+	for _, store := range []struct {
+		name string
+		keys int
+	}{
+		{"kv", keyspace.Keys},
+		{"sorted_map", keyspace.SortedMaps},
+		{"list", keyspace.Lists},
+		{"set", keyspace.Sets},
+		{"hash", keyspace.Hashes},
+		{"collection", keyspace.Collections},
+		{"vector", keyspace.Vectors},
+	} {
+		ch <- prometheus.MustNewConstMetric(keysDesc, prometheus.GaugeValue, float64(store.keys), store.name)
+	}
+	ch <- prometheus.MustNewConstMetric(expiresDesc, prometheus.GaugeValue, float64(keyspace.Expires))
+	ch <- prometheus.MustNewConstMetric(expiredKeysDesc, prometheus.CounterValue, float64(keyspace.ExpiredKeys))
+
+	if ts.raft == nil {
+		return
+	}
+	stats := ts.raft.Stats()
+	commitIndex, _ := strconv.ParseUint(stats["commit_index"], 10, 64)
+	appliedIndex, _ := strconv.ParseUint(stats["applied_index"], 10, 64)
+	ch <- prometheus.MustNewConstMetric(commitIndexDesc, prometheus.GaugeValue, float64(commitIndex))
+	ch <- prometheus.MustNewConstMetric(appliedIndexDesc, prometheus.GaugeValue, float64(appliedIndex))
+	lag := 0.0
+	if commitIndex > appliedIndex {
+		lag = float64(commitIndex - appliedIndex)
+	}
+	ch <- prometheus.MustNewConstMetric(applyLagDesc, prometheus.GaugeValue, lag)
+}
+
+// MetricsHandler serves the Prometheus metrics at /metrics
+func (ts *Server) MetricsHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", promhttp.HandlerFor(ts.metrics.registry, promhttp.HandlerOpts{}))
+	return mux
+}
+
+// StartMetrics serves the Prometheus metrics on addr at /metrics in the background
+func (ts *Server) StartMetrics(addr string) (*http.Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	metricsServer := newHTTPServer(ts.MetricsHandler())
+	ts.metricsServer = metricsServer
+	go func() {
+		if errServe := metricsServer.Serve(listener); errServe != nil && !errors.Is(errServe, http.ErrServerClosed) {
+			logger.Error("Metrics server stopped", "error", errServe)
+		}
+	}()
+	logger.Info("Metrics listening", "addr", addr)
+	return metricsServer, nil
+}
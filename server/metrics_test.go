@@ -0,0 +1,55 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/raft"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func scrapeMetrics(t *testing.T, ts *Server) string {
+	rec := httptest.NewRecorder()
+	ts.MetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestMetrics(t *testing.T) {
+	ts, tredsStore := newTestServer(t)
+	ts.metrics = newMetrics(ts)
+	require.NoError(t, tredsStore.Set("user:1", "alice"))
+	require.NoError(t, tredsStore.HSet("h", []string{"f1", "v1"}))
+	handler := ts.HTTPHandler()
+
+	doHTTP(t, handler, httptest.NewRequest(http.MethodPost, "/v1/cmd", strings.NewReader(`{"cmd":"get","args":["user:1"]}`)))
+	doHTTP(t, handler, httptest.NewRequest(http.MethodPost, "/v1/cmd", strings.NewReader(`{"cmd":"GET"}`)))
+	doHTTP(t, handler, httptest.NewRequest(http.MethodPost, "/v1/cmd", strings.NewReader(`{"cmd":"NOPE"}`)))
+
+	metrics := scrapeMetrics(t, ts)
+	require.Contains(t, metrics, `treds_commands_total{command="GET"} 2`)
+	require.Contains(t, metrics, `treds_commands_total{command="UNKNOWN"} 1`)
+	require.Contains(t, metrics, `treds_command_errors_total{command="GET"} 1`)
+	require.Contains(t, metrics, `treds_command_duration_seconds_count{command="GET"} 2`)
+	require.Contains(t, metrics, `treds_keys{store="kv"} 1`)
+	require.Contains(t, metrics, `treds_keys{store="hash"} 1`)
+	require.Contains(t, metrics, "treds_connected_clients 0")
+}
+
+func TestCountLeaderChanges(t *testing.T) {
+	m := &metrics{raftLeaderChanges: prometheus.NewCounter(prometheus.CounterOpts{Name: "leader_changes"})}
+	observations := make(chan raft.Observation, 8)
+	for _, leader := range []raft.ServerID{"a", "", "a", "b", "b", "", "a"} {
+		observations <- raft.Observation{Data: raft.LeaderObservation{LeaderID: leader}}
+	}
+	close(observations)
+	m.countLeaderChanges(observations)
+	require.Equal(t, float64(3), testutil.ToFloat64(m.raftLeaderChanges))
+}
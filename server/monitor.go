@@ -0,0 +1,201 @@
+package server
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/panjf2000/gnet/v2"
+	"treds/acl"
+	"treds/commands"
+	"treds/resp"
+)
+
+const MonitorCommandName = "MONITOR"
+
+// monitorSourceRaft is shown in place of a client address for commands replicated from the leader
+const monitorSourceRaft = "raft"
+
+// forwardedTTL is how long a forwarded write is waited for in the replicated log, a write the leader refused is
+// never replicated
+const forwardedTTL = time.Minute
+
+// forwardedWrites are the writes a follower forwarded to the leader while it was monitored. They were streamed
+// with the address of their client already, so they are skipped once replicated back.
+type forwardedWrites struct {
+	mu      sync.Mutex
+	pending map[uint64][]time.Time
+}
+
+// fingerprint identifies a command and its arguments
+func fingerprint(command string, args []string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(strings.ToUpper(command)))
+	for _, arg := range args {
+		h.Write([]byte{0})
+		h.Write([]byte(arg))
+	}
+	return h.Sum64()
+}
+
+func (f *forwardedWrites) add(command string, args []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.pending == nil {
+		f.pending = make(map[uint64][]time.Time)
+	}
+	now := time.Now()
+	for key, times := range f.pending {
+		for len(times) > 0 && now.Sub(times[0]) > forwardedTTL {
+			times = times[1:]
+		}
+		if len(times) == 0 {
+			delete(f.pending, key)
+		} else {
+			f.pending[key] = times
+		}
+	}
+	key := fingerprint(command, args)
+	f.pending[key] = append(f.pending[key], now)
+}
+
+// take removes a pending write and tells if there was one
+func (f *forwardedWrites) take(command string, args []string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := fingerprint(command, args)
+	times, ok := f.pending[key]
+	if !ok {
+		return false
+	}
+	if len(times) == 1 {
+		delete(f.pending, key)
+	} else {
+		f.pending[key] = times[1:]
+	}
+	return true
+}
+
+// monitorHidden are the commands not streamed to monitors since their arguments can carry passwords
+var monitorHidden = map[string]struct{}{
+	AuthCommandName:        {},
+	HelloCommandName:       {},
+	ClusterAuthCommandName: {},
+	ACLCommandName:         {},
+	MonitorCommandName:     {},
+}
+
+func RegisterMonitorCommand(r ServerCommandRegistry) {
+	r.Add(&ServerCommandRegistration{
+		Name:       MonitorCommandName,
+		Execute:    executeMonitor(),
+		Categories: []string{acl.CategoryAdmin},
+		Spec: commands.Spec{
+			Summary: "Streams every command processed by the node",
+			Group:   commands.GroupServer,
+		},
+	})
+}
+
+// executeMonitor runs MONITOR, the connection is then sent a line for every command this node processes
+func executeMonitor() ExecutionHook {
+	return func(inp string, ts *Server, c gnet.Conn) gnet.Action {
+		_, args, err := parseCommand(inp)
+		if err != nil {
+			ts.RespondErr(c, err)
+			return gnet.None
+		}
+		if len(args) != 0 {
+			ts.RespondErr(c, fmt.Errorf("invalid number of arguments"))
+			return gnet.None
+		}
+		ts.addMonitor(c)
+		_, errConn := c.Write([]byte(resp.EncodeSimpleString("OK")))
+		if errConn != nil {
+			logger.Error("Error occurred writing to connection", "error", errConn)
+		}
+		return gnet.None
+	}
+}
+
+func (ts *Server) addMonitor(c gnet.Conn) {
+	ts.monitorMu.Lock()
+	defer ts.monitorMu.Unlock()
+	if ts.monitors == nil {
+		ts.monitors = make(map[uint64]gnet.Conn)
+	}
+	if _, ok := ts.monitors[connID(c)]; ok {
+		return
+	}
+	// Monitors are streamed to like subscribers, so they get the pubsub output buffer limit
+	getClientConn(c).monitor.Store(true)
+	ts.monitors[connID(c)] = c
+	ts.monitorCount.Add(1)
+}
+
+func (ts *Server) removeMonitor(c gnet.Conn) {
+	ts.monitorMu.Lock()
+	defer ts.monitorMu.Unlock()
+	if _, ok := ts.monitors[connID(c)]; ok {
+		delete(ts.monitors, connID(c))
+		ts.monitorCount.Add(-1)
+	}
+}
+
+// feedMonitors streams the command received from source to the monitors
+func (ts *Server) feedMonitors(source string, command string, args []string) {
+	if ts.monitorCount.Load() == 0 {
+		return
+	}
+	if _, hidden := monitorHidden[strings.ToUpper(command)]; hidden {
+		return
+	}
+	line := []byte(resp.EncodeSimpleString(monitorLine(time.Now(), source, command, args)))
+	ts.monitorMu.Lock()
+	defer ts.monitorMu.Unlock()
+	for _, conn := range ts.monitors {
+		// Monitors can be served by other event loops, so the line is queued on their own loop like a published message
+		errConn := ts.asyncWrite(conn, line)
+		if errConn != nil {
+			logger.Error("Error occurred writing to connection", "error", errConn)
+		}
+	}
+}
+
+// monitorApplied streams a command applied through Raft. The leader streamed it already when its client or
+// the follower forwarding it sent it, so only followers stream the commands replicated to them.
+func (ts *Server) monitorApplied(index uint64, command string, args []string) {
+	if ts.monitorCount.Load() == 0 || ts.raft.State() == raft.Leader || ts.skipApplied(index, command, args) {
+		return
+	}
+	ts.feedMonitors(monitorSourceRaft, command, args)
+}
+
+// skipApplied tells if an applied entry is not streamed, the entries replayed from the log when the node starts
+// were not just processed, and the writes this node forwarded were streamed already
+func (ts *Server) skipApplied(index uint64, command string, args []string) bool {
+	return index <= ts.replayedIndex || ts.forwarded.take(command, args)
+}
+
+// monitorSource is how a RESP client is shown to monitors, its address or the unix socket it connected to
+func monitorSource(c gnet.Conn) string {
+	client := getClientConn(c)
+	if client.addr == "" {
+		return "unix:" + client.localAddr
+	}
+	return client.addr
+}
+
+// monitorLine formats a command like Redis does, 1700000000.123456 [127.0.0.1:53000] "SET" "k" "v"
+func monitorLine(t time.Time, source string, command string, args []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d.%06d [%s] %s", t.Unix(), t.Nanosecond()/int(time.Microsecond), source, strconv.Quote(command))
+	for _, arg := range args {
+		b.WriteString(" " + strconv.Quote(arg))
+	}
+	return b.String()
+}
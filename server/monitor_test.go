@@ -0,0 +1,53 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonitorLine(t *testing.T) {
+	at := time.Unix(1700000000, 123456789)
+	require.Equal(t, `1700000000.123456 [10.0.0.1:5000] "SET" "k" "a \"b\"\n"`,
+		monitorLine(at, "10.0.0.1:5000", "SET", []string{"k", "a \"b\"\n"}))
+}
+
+func TestMonitor(t *testing.T) {
+	ts, conns := newTestClients(t, 2)
+	ts.feedMonitors(monitorSource(conns[1]), "GET", []string{"k"})
+
+	ts.addMonitor(conns[0])
+	ts.addMonitor(conns[0])
+	require.Equal(t, int64(1), ts.monitorCount.Load())
+	require.True(t, getClientConn(conns[0]).monitor.Load())
+
+	ts.feedMonitors(monitorSource(conns[1]), "SET", []string{"k", "v"})
+	ts.feedMonitors(monitorSource(conns[1]), "auth", []string{"secret"})
+	require.Regexp(t, `^\+\d+\.\d{6} \[10\.0\.0\.2:5000\] "SET" "k" "v"\r\n$`, string(conns[0].written))
+
+	ts.removeMonitor(conns[0])
+	require.Equal(t, int64(0), ts.monitorCount.Load())
+	conns[0].written = nil
+	ts.feedMonitors(monitorSource(conns[1]), "SET", []string{"k", "v"})
+	require.Empty(t, conns[0].written)
+}
+
+func TestSkipApplied(t *testing.T) {
+	ts, _ := newTestClients(t, 1)
+	ts.replayedIndex = 10
+
+	// Entries replayed from the log at startup are not streamed
+	require.True(t, ts.skipApplied(10, "SET", []string{"k", "v"}))
+	require.False(t, ts.skipApplied(11, "SET", []string{"k", "v"}))
+
+	// A forwarded write is skipped once, when it is replicated back
+	ts.forwarded.add("set", []string{"k", "v"})
+	ts.forwarded.add("SET", []string{"k", "v"})
+	require.False(t, ts.skipApplied(11, "SET", []string{"k", "v2"}))
+	require.False(t, ts.skipApplied(11, "SET", []string{"kv"}))
+	require.True(t, ts.skipApplied(11, "SET", []string{"k", "v"}))
+	require.True(t, ts.skipApplied(12, "SET", []string{"k", "v"}))
+	require.False(t, ts.skipApplied(13, "SET", []string{"k", "v"}))
+	require.Empty(t, ts.forwarded.pending)
+}
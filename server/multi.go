@@ -1,9 +1,9 @@
 package server
 
 import (
-	"fmt"
-
 	"github.com/panjf2000/gnet/v2"
+	"treds/acl"
+	"treds/commands"
 	"treds/resp"
 )
 
@@ -11,8 +11,14 @@ const MultiCommandName = "MULTI"
 
 func RegisterMultiCommand(r ServerCommandRegistry) {
 	r.Add(&ServerCommandRegistration{
-		Name:    MultiCommandName,
-		Execute: executeMulti(),
+		Name:       MultiCommandName,
+		Execute:    executeMulti(),
+		Unlocked:   true,
+		Categories: []string{acl.CategoryTransaction},
+		Spec: commands.Spec{
+			Summary: "Starts a transaction, the following commands are queued until EXEC",
+			Group:   commands.GroupTransactions,
+		},
 	})
 }
 
@@ -30,13 +36,15 @@ func executeMulti() ExecutionHook {
 		if forwarded {
 			_, errConn := c.Write([]byte(rspFwd))
 			if errConn != nil {
-				fmt.Println("Error occurred writing to connection", errConn)
+				logger.Error("Error occurred writing to connection", "error", errConn)
 			}
 			return gnet.None
 		}
 
 		// Check for transaction first, if transaction just enqueue the command
-		if _, ok := ts.GetClientTransaction()[c.RemoteAddr().String()]; ok {
+		ts.mu.Lock()
+		if _, ok := ts.GetClientTransaction()[connID(c)]; ok {
+			ts.mu.Unlock()
 			_, errConn := c.Write([]byte(resp.EncodeError("MULTI calls cannot be nested")))
 			if errConn != nil {
 				ts.RespondErr(c, errConn)
@@ -44,7 +52,8 @@ func executeMulti() ExecutionHook {
 			return gnet.None
 		}
 
-		ts.GetClientTransaction()[c.RemoteAddr().String()] = make([]string, 0)
+		ts.GetClientTransaction()[connID(c)] = make([]string, 0)
+		ts.mu.Unlock()
 
 		res := "OK"
 		_, errConn := c.Write([]byte(resp.EncodeSimpleString(res)))
@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"github.com/panjf2000/gnet/v2"
+	"treds/acl"
+	"treds/commands"
 	"treds/resp"
 )
 
@@ -13,8 +15,18 @@ const PMessage = "pmessage"
 
 func RegisterPPublishCommand(r ServerCommandRegistry) {
 	r.Add(&ServerCommandRegistration{
-		Name:    PPublishCommandName,
-		Execute: executePPublishCommand(),
+		Name:       PPublishCommandName,
+		Execute:    executePPublishCommand(),
+		Unlocked:   true,
+		Categories: []string{acl.CategoryPubSub},
+		Spec: commands.Spec{
+			Summary: "Publishes a message to every channel having the channel as prefix",
+			Group:   commands.GroupPubSub,
+			Args: []commands.Arg{
+				{Name: "channel", Type: commands.ArgString},
+				{Name: "message", Type: commands.ArgString, Optional: true, Multiple: true},
+			},
+		},
 	})
 }
 
@@ -43,11 +55,12 @@ func executePPublishCommand() ExecutionHook {
 		if forwarded {
 			_, errConn := c.Write([]byte(rspFwd))
 			if errConn != nil {
-				fmt.Println("Error occurred writing to connection", errConn)
+				logger.Error("Error occurred writing to connection", "error", errConn)
 			}
 			return gnet.None
 		}
 
+		ts.mu.Lock()
 		subscriptionData := ts.GetChannelSubscriptionData()
 
 		// make args unique
@@ -64,17 +77,14 @@ func executePPublishCommand() ExecutionHook {
 			if !found {
 				break
 			}
-			connections := value.(map[string]struct{})
+			connections := value.(map[uint64]struct{})
 			for id := range connections {
-				arrayMessage := []string{PMessage, channelPrefix, string(key), message}
-				conn := ts.GetConnectionFromAddress(id)
-				_, errConn := conn.Write([]byte(resp.EncodeStringArray(arrayMessage)))
-				if errConn != nil {
-					fmt.Println("Error occurred writing to connection", errConn)
+				if ts.deliver(id, PMessage, channelPrefix, string(key), message) {
+					countChannelsNotified++
 				}
-				countChannelsNotified++
 			}
 		}
+		ts.mu.Unlock()
 
 		_, errConn := c.Write([]byte(resp.EncodeInteger(countChannelsNotified)))
 		if errConn != nil {
@@ -0,0 +1,521 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: server/proto/treds.proto
+
+package tredspb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	proto "treds/store/proto"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Treds_Command_FullMethodName       = "/treds.v1.Treds/Command"
+	Treds_Get_FullMethodName           = "/treds.v1.Treds/Get"
+	Treds_Set_FullMethodName           = "/treds.v1.Treds/Set"
+	Treds_Scan_FullMethodName          = "/treds.v1.Treds/Scan"
+	Treds_DeletePrefix_FullMethodName  = "/treds.v1.Treds/DeletePrefix"
+	Treds_LongestPrefix_FullMethodName = "/treds.v1.Treds/LongestPrefix"
+	Treds_ZRangeByScore_FullMethodName = "/treds.v1.Treds/ZRangeByScore"
+	Treds_ZRangeByLex_FullMethodName   = "/treds.v1.Treds/ZRangeByLex"
+	Treds_DQuery_FullMethodName        = "/treds.v1.Treds/DQuery"
+	Treds_VSearch_FullMethodName       = "/treds.v1.Treds/VSearch"
+	Treds_Watch_FullMethodName         = "/treds.v1.Treds/Watch"
+)
+
+// TredsClient is the client API for Treds service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Treds serves the store commands over gRPC, next to the RESP listener.
+// Writes are forwarded to the leader and applied through Raft like the writes of RESP clients.
+type TredsClient interface {
+	// Command runs any store command
+	Command(ctx context.Context, in *CommandRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
+	Set(ctx context.Context, in *SetRequest, opts ...grpc.CallOption) (*SetResponse, error)
+	// Scan streams the key/value pairs whose keys start with prefix, in lex order
+	Scan(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[proto.KeyValue], error)
+	DeletePrefix(ctx context.Context, in *DeletePrefixRequest, opts ...grpc.CallOption) (*DeletePrefixResponse, error)
+	LongestPrefix(ctx context.Context, in *LongestPrefixRequest, opts ...grpc.CallOption) (*LongestPrefixResponse, error)
+	ZRangeByScore(ctx context.Context, in *ZRangeByScoreRequest, opts ...grpc.CallOption) (*ZRangeResponse, error)
+	ZRangeByLex(ctx context.Context, in *ZRangeByLexRequest, opts ...grpc.CallOption) (*ZRangeResponse, error)
+	DQuery(ctx context.Context, in *DQueryRequest, opts ...grpc.CallOption) (*DQueryResponse, error)
+	VSearch(ctx context.Context, in *VSearchRequest, opts ...grpc.CallOption) (*VSearchResponse, error)
+	// Watch streams the messages published to channels, like SUBSCRIBE or PSUBSCRIBE when prefix is set
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchEvent], error)
+}
+
+type tredsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTredsClient(cc grpc.ClientConnInterface) TredsClient {
+	return &tredsClient{cc}
+}
+
+func (c *tredsClient) Command(ctx context.Context, in *CommandRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, Treds_Command_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tredsClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetResponse)
+	err := c.cc.Invoke(ctx, Treds_Get_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tredsClient) Set(ctx context.Context, in *SetRequest, opts ...grpc.CallOption) (*SetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetResponse)
+	err := c.cc.Invoke(ctx, Treds_Set_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tredsClient) Scan(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[proto.KeyValue], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Treds_ServiceDesc.Streams[0], Treds_Scan_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ScanRequest, proto.KeyValue]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Treds_ScanClient = grpc.ServerStreamingClient[proto.KeyValue]
+
+func (c *tredsClient) DeletePrefix(ctx context.Context, in *DeletePrefixRequest, opts ...grpc.CallOption) (*DeletePrefixResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeletePrefixResponse)
+	err := c.cc.Invoke(ctx, Treds_DeletePrefix_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tredsClient) LongestPrefix(ctx context.Context, in *LongestPrefixRequest, opts ...grpc.CallOption) (*LongestPrefixResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LongestPrefixResponse)
+	err := c.cc.Invoke(ctx, Treds_LongestPrefix_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tredsClient) ZRangeByScore(ctx context.Context, in *ZRangeByScoreRequest, opts ...grpc.CallOption) (*ZRangeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ZRangeResponse)
+	err := c.cc.Invoke(ctx, Treds_ZRangeByScore_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tredsClient) ZRangeByLex(ctx context.Context, in *ZRangeByLexRequest, opts ...grpc.CallOption) (*ZRangeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ZRangeResponse)
+	err := c.cc.Invoke(ctx, Treds_ZRangeByLex_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tredsClient) DQuery(ctx context.Context, in *DQueryRequest, opts ...grpc.CallOption) (*DQueryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DQueryResponse)
+	err := c.cc.Invoke(ctx, Treds_DQuery_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tredsClient) VSearch(ctx context.Context, in *VSearchRequest, opts ...grpc.CallOption) (*VSearchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VSearchResponse)
+	err := c.cc.Invoke(ctx, Treds_VSearch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tredsClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Treds_ServiceDesc.Streams[1], Treds_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, WatchEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Treds_WatchClient = grpc.ServerStreamingClient[WatchEvent]
+
+// TredsServer is the server API for Treds service.
+// All implementations must embed UnimplementedTredsServer
+// for forward compatibility.
+//
+// Treds serves the store commands over gRPC, next to the RESP listener.
+// Writes are forwarded to the leader and applied through Raft like the writes of RESP clients.
+type TredsServer interface {
+	// Command runs any store command
+	Command(context.Context, *CommandRequest) (*CommandResponse, error)
+	Get(context.Context, *GetRequest) (*GetResponse, error)
+	Set(context.Context, *SetRequest) (*SetResponse, error)
+	// Scan streams the key/value pairs whose keys start with prefix, in lex order
+	Scan(*ScanRequest, grpc.ServerStreamingServer[proto.KeyValue]) error
+	DeletePrefix(context.Context, *DeletePrefixRequest) (*DeletePrefixResponse, error)
+	LongestPrefix(context.Context, *LongestPrefixRequest) (*LongestPrefixResponse, error)
+	ZRangeByScore(context.Context, *ZRangeByScoreRequest) (*ZRangeResponse, error)
+	ZRangeByLex(context.Context, *ZRangeByLexRequest) (*ZRangeResponse, error)
+	DQuery(context.Context, *DQueryRequest) (*DQueryResponse, error)
+	VSearch(context.Context, *VSearchRequest) (*VSearchResponse, error)
+	// Watch streams the messages published to channels, like SUBSCRIBE or PSUBSCRIBE when prefix is set
+	Watch(*WatchRequest, grpc.ServerStreamingServer[WatchEvent]) error
+	mustEmbedUnimplementedTredsServer()
+}
+
+// UnimplementedTredsServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTredsServer struct{}
+
+func (UnimplementedTredsServer) Command(context.Context, *CommandRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Command not implemented")
+}
+func (UnimplementedTredsServer) Get(context.Context, *GetRequest) (*GetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (UnimplementedTredsServer) Set(context.Context, *SetRequest) (*SetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Set not implemented")
+}
+func (UnimplementedTredsServer) Scan(*ScanRequest, grpc.ServerStreamingServer[proto.KeyValue]) error {
+	return status.Errorf(codes.Unimplemented, "method Scan not implemented")
+}
+func (UnimplementedTredsServer) DeletePrefix(context.Context, *DeletePrefixRequest) (*DeletePrefixResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePrefix not implemented")
+}
+func (UnimplementedTredsServer) LongestPrefix(context.Context, *LongestPrefixRequest) (*LongestPrefixResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LongestPrefix not implemented")
+}
+func (UnimplementedTredsServer) ZRangeByScore(context.Context, *ZRangeByScoreRequest) (*ZRangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ZRangeByScore not implemented")
+}
+func (UnimplementedTredsServer) ZRangeByLex(context.Context, *ZRangeByLexRequest) (*ZRangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ZRangeByLex not implemented")
+}
+func (UnimplementedTredsServer) DQuery(context.Context, *DQueryRequest) (*DQueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DQuery not implemented")
+}
+func (UnimplementedTredsServer) VSearch(context.Context, *VSearchRequest) (*VSearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VSearch not implemented")
+}
+func (UnimplementedTredsServer) Watch(*WatchRequest, grpc.ServerStreamingServer[WatchEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedTredsServer) mustEmbedUnimplementedTredsServer() {}
+func (UnimplementedTredsServer) testEmbeddedByValue()               {}
+
+// UnsafeTredsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TredsServer will
+// result in compilation errors.
+type UnsafeTredsServer interface {
+	mustEmbedUnimplementedTredsServer()
+}
+
+func RegisterTredsServer(s grpc.ServiceRegistrar, srv TredsServer) {
+	// If the following call pancis, it indicates UnimplementedTredsServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Treds_ServiceDesc, srv)
+}
+
+func _Treds_Command_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommandRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TredsServer).Command(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Treds_Command_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TredsServer).Command(ctx, req.(*CommandRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Treds_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TredsServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Treds_Get_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TredsServer).Get(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Treds_Set_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TredsServer).Set(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Treds_Set_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TredsServer).Set(ctx, req.(*SetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Treds_Scan_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ScanRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TredsServer).Scan(m, &grpc.GenericServerStream[ScanRequest, proto.KeyValue]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Treds_ScanServer = grpc.ServerStreamingServer[proto.KeyValue]
+
+func _Treds_DeletePrefix_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePrefixRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TredsServer).DeletePrefix(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Treds_DeletePrefix_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TredsServer).DeletePrefix(ctx, req.(*DeletePrefixRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Treds_LongestPrefix_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LongestPrefixRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TredsServer).LongestPrefix(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Treds_LongestPrefix_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TredsServer).LongestPrefix(ctx, req.(*LongestPrefixRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Treds_ZRangeByScore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ZRangeByScoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TredsServer).ZRangeByScore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Treds_ZRangeByScore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TredsServer).ZRangeByScore(ctx, req.(*ZRangeByScoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Treds_ZRangeByLex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ZRangeByLexRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TredsServer).ZRangeByLex(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Treds_ZRangeByLex_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TredsServer).ZRangeByLex(ctx, req.(*ZRangeByLexRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Treds_DQuery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DQueryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TredsServer).DQuery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Treds_DQuery_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TredsServer).DQuery(ctx, req.(*DQueryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Treds_VSearch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VSearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TredsServer).VSearch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Treds_VSearch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TredsServer).VSearch(ctx, req.(*VSearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Treds_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TredsServer).Watch(m, &grpc.GenericServerStream[WatchRequest, WatchEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Treds_WatchServer = grpc.ServerStreamingServer[WatchEvent]
+
+// Treds_ServiceDesc is the grpc.ServiceDesc for Treds service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Treds_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "treds.v1.Treds",
+	HandlerType: (*TredsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Command",
+			Handler:    _Treds_Command_Handler,
+		},
+		{
+			MethodName: "Get",
+			Handler:    _Treds_Get_Handler,
+		},
+		{
+			MethodName: "Set",
+			Handler:    _Treds_Set_Handler,
+		},
+		{
+			MethodName: "DeletePrefix",
+			Handler:    _Treds_DeletePrefix_Handler,
+		},
+		{
+			MethodName: "LongestPrefix",
+			Handler:    _Treds_LongestPrefix_Handler,
+		},
+		{
+			MethodName: "ZRangeByScore",
+			Handler:    _Treds_ZRangeByScore_Handler,
+		},
+		{
+			MethodName: "ZRangeByLex",
+			Handler:    _Treds_ZRangeByLex_Handler,
+		},
+		{
+			MethodName: "DQuery",
+			Handler:    _Treds_DQuery_Handler,
+		},
+		{
+			MethodName: "VSearch",
+			Handler:    _Treds_VSearch_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Scan",
+			Handler:       _Treds_Scan_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Watch",
+			Handler:       _Treds_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "server/proto/treds.proto",
+}
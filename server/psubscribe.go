@@ -5,15 +5,25 @@ import (
 	"strings"
 
 	"github.com/panjf2000/gnet/v2"
-	"treds/resp"
+	"treds/acl"
+	"treds/commands"
 )
 
 const PSubscribeCommandName = "PSUBSCRIBE"
 
 func RegisterPSubscribeCommand(r ServerCommandRegistry) {
 	r.Add(&ServerCommandRegistration{
-		Name:    PSubscribeCommandName,
-		Execute: executePSubscribeCommand(),
+		Name:       PSubscribeCommandName,
+		Execute:    executePSubscribeCommand(),
+		Unlocked:   true,
+		Categories: []string{acl.CategoryPubSub},
+		Spec: commands.Spec{
+			Summary: "Subscribes to the channels whose names are prefixes of the given channels",
+			Group:   commands.GroupPubSub,
+			Args: []commands.Arg{
+				{Name: "channel", Type: commands.ArgString, Multiple: true},
+			},
+		},
 	})
 }
 
@@ -42,7 +52,7 @@ func executePSubscribeCommand() ExecutionHook {
 		if forwarded {
 			_, errConn := c.Write([]byte(rspFwd))
 			if errConn != nil {
-				fmt.Println("Error occurred writing to connection", errConn)
+				logger.Error("Error occurred writing to connection", "error", errConn)
 			}
 			return gnet.None
 		}
@@ -50,45 +60,18 @@ func executePSubscribeCommand() ExecutionHook {
 		// make args unique
 		args = unique(args)
 
-		subscriptionData := ts.GetChannelSubscriptionData()
-		// all channels matching all args prefix
-		allChannels := make(map[string]struct{})
-		for _, channelPrefix := range args {
-			iterator := subscriptionData.Root().Iterator()
-			iterator.SeekPrefix([]byte(channelPrefix))
-			for {
-				key, _, found := iterator.Next()
-				if !found {
-					break
-				}
-				allChannels[string(key)] = struct{}{}
-			}
-			allChannels[channelPrefix] = struct{}{}
-		}
-
-		for channel := range allChannels {
-			prevData, ok := subscriptionData.Get([]byte(channel))
-			if !ok {
-				prevData = make(map[string]struct{})
-			}
-			newData := prevData.(map[string]struct{})
-			newData[c.RemoteAddr().String()] = struct{}{}
-			subscriptionData, _, _ = subscriptionData.Insert([]byte(channel), newData)
-		}
-
-		ts.SetChannelSubscriptionData(subscriptionData)
+		ts.mu.Lock()
+		ts.subscribe(connID(c), args, true)
+		ts.mu.Unlock()
 
 		response := make([]interface{}, 0)
-		if _, ok := ts.GetConnectionSubscription()[c.RemoteAddr().String()]; !ok {
-			ts.GetConnectionSubscription()[c.RemoteAddr().String()] = make(map[string]struct{})
-		}
 		for indx, channel := range args {
 			response = append(response, strings.ToLower(PSubscribeCommandName))
 			response = append(response, channel)
-			ts.GetConnectionSubscription()[c.RemoteAddr().String()][channel] = struct{}{}
 			response = append(response, indx+1)
 		}
-		_, errConn := c.Write([]byte(resp.EncodeArray(response)))
+		getClientConn(c).pubsub.Store(true)
+		_, errConn := c.Write([]byte(encodePubSub(c, response)))
 		if errConn != nil {
 			ts.RespondErr(c, errConn)
 		}
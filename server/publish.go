@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"github.com/panjf2000/gnet/v2"
+	"treds/acl"
+	"treds/commands"
 	"treds/resp"
 )
 
@@ -13,8 +15,18 @@ const Message = "message"
 
 func RegisterPublishCommand(r ServerCommandRegistry) {
 	r.Add(&ServerCommandRegistration{
-		Name:    PublishCommandName,
-		Execute: executePublishCommand(),
+		Name:       PublishCommandName,
+		Execute:    executePublishCommand(),
+		Unlocked:   true,
+		Categories: []string{acl.CategoryPubSub},
+		Spec: commands.Spec{
+			Summary: "Publishes a message to a channel",
+			Group:   commands.GroupPubSub,
+			Args: []commands.Arg{
+				{Name: "channel", Type: commands.ArgString},
+				{Name: "message", Type: commands.ArgString, Multiple: true},
+			},
+		},
 	})
 }
 
@@ -43,13 +55,14 @@ func executePublishCommand() ExecutionHook {
 		if forwarded {
 			_, errConn := c.Write([]byte(rspFwd))
 			if errConn != nil {
-				fmt.Println("Error occurred writing to connection", errConn)
+				logger.Error("Error occurred writing to connection", "error", errConn)
 			}
 			return gnet.None
 		}
 
 		message := strings.Join(args[1:], " ")
 
+		ts.mu.Lock()
 		subscriptionData := ts.GetChannelSubscriptionData()
 
 		channel := args[0]
@@ -57,6 +70,7 @@ func executePublishCommand() ExecutionHook {
 
 		countChannelsNotified := 0
 		if !found {
+			ts.mu.Unlock()
 			_, errConn := c.Write([]byte(resp.EncodeInteger(countChannelsNotified)))
 			if errConn != nil {
 				ts.RespondErr(c, errConn)
@@ -64,16 +78,13 @@ func executePublishCommand() ExecutionHook {
 			return gnet.None
 		}
 
-		connections := value.(map[string]struct{})
+		connections := value.(map[uint64]struct{})
 		for id := range connections {
-			arrayMessage := []string{Message, channel, channel, message}
-			conn := ts.GetConnectionFromAddress(id)
-			_, errConn := conn.Write([]byte(resp.EncodeStringArray(arrayMessage)))
-			if errConn != nil {
-				fmt.Println("Error occurred writing to connection", errConn)
+			if ts.deliver(id, Message, channel, channel, message) {
+				countChannelsNotified++
 			}
-			countChannelsNotified++
 		}
+		ts.mu.Unlock()
 
 		_, errConn := c.Write([]byte(resp.EncodeInteger(countChannelsNotified)))
 		if errConn != nil {
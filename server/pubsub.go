@@ -0,0 +1,194 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/asheshvidyut/prefix-search-optimized-radix"
+)
+
+// DefaultWatcherBuffer is the number of messages queued for a watcher before it is dropped for falling behind
+const DefaultWatcherBuffer = 1024
+
+// errWatcherBehind ends a watcher which did not keep up with the published messages
+var errWatcherBehind = fmt.Errorf("subscriber fell behind by %d messages", DefaultWatcherBuffer)
+
+// ErrShuttingDown is returned to clients once the server started shutting down
+var ErrShuttingDown = errors.New("ERR server is shutting down")
+
+// watchMessage is a published message delivered to a watcher
+type watchMessage struct {
+	Channel string
+	// Pattern is the channel given to PPUBLISH, empty for PUBLISH
+	Pattern string
+	Message string
+}
+
+// watcher receives published messages outside of a RESP connection, like a gRPC Watch stream or a WebSocket.
+// Watchers share the subscriber IDs of connections, so PUBLISH and PPUBLISH reach both the same way.
+type watcher struct {
+	id       uint64
+	messages chan watchMessage
+	// dropped is closed when the watcher was unsubscribed by the server, err tells why
+	dropped chan struct{}
+	err     error
+}
+
+// matchingChannels returns channels and, with prefix, every existing channel starting with one of them
+func matchingChannels(subscriptionData *radix.Tree, channels []string, prefix bool) map[string]struct{} {
+	allChannels := make(map[string]struct{})
+	for _, channel := range channels {
+		allChannels[channel] = struct{}{}
+		if !prefix {
+			continue
+		}
+		iterator := subscriptionData.Root().Iterator()
+		iterator.SeekPrefix([]byte(channel))
+		for {
+			key, _, found := iterator.Next()
+			if !found {
+				break
+			}
+			allChannels[string(key)] = struct{}{}
+		}
+	}
+	return allChannels
+}
+
+// subscribe adds the subscriber id to channels. With prefix, id is also added to every channel
+// starting with one of channels, the way PSUBSCRIBE does. It is called with ts.mu held.
+func (ts *Server) subscribe(id uint64, channels []string, prefix bool) {
+	subscriptionData := ts.GetChannelSubscriptionData()
+	for channel := range matchingChannels(subscriptionData, channels, prefix) {
+		prevData, ok := subscriptionData.Get([]byte(channel))
+		if !ok {
+			prevData = make(map[uint64]struct{})
+		}
+		newData := prevData.(map[uint64]struct{})
+		newData[id] = struct{}{}
+		subscriptionData, _, _ = subscriptionData.Insert([]byte(channel), newData)
+	}
+	ts.SetChannelSubscriptionData(subscriptionData)
+
+	if _, ok := ts.connectionSubscription[id]; !ok {
+		ts.connectionSubscription[id] = make(map[string]struct{})
+	}
+	for _, channel := range channels {
+		ts.connectionSubscription[id][channel] = struct{}{}
+	}
+}
+
+// unsubscribe removes the subscriber id from channels. With prefix, id is also removed from every channel
+// starting with one of channels, the way PUNSUBSCRIBE does. It is called with ts.mu held and returns
+// the number of subscriptions id has left.
+func (ts *Server) unsubscribe(id uint64, channels []string, prefix bool) int {
+	subscriptionData := ts.GetChannelSubscriptionData()
+	for channel := range matchingChannels(subscriptionData, channels, prefix) {
+		prevData, ok := subscriptionData.Get([]byte(channel))
+		if !ok {
+			continue
+		}
+		newData := prevData.(map[uint64]struct{})
+		delete(newData, id)
+		subscriptionData, _, _ = subscriptionData.Insert([]byte(channel), newData)
+	}
+	ts.SetChannelSubscriptionData(subscriptionData)
+
+	subscriptions := ts.connectionSubscription[id]
+	for _, channel := range channels {
+		delete(subscriptions, channel)
+	}
+	if len(subscriptions) == 0 {
+		delete(ts.connectionSubscription, id)
+	}
+	return len(subscriptions)
+}
+
+// unsubscribeAll removes the subscriber id from every channel, it is called with ts.mu held
+func (ts *Server) unsubscribeAll(id uint64) {
+	if _, ok := ts.connectionSubscription[id]; !ok {
+		return
+	}
+	// PSUBSCRIBE adds the subscriber to channels it was not given, so every channel is checked
+	subscriptionData := ts.GetChannelSubscriptionData()
+	iterator := subscriptionData.Root().Iterator()
+	var channels [][]byte
+	for {
+		key, value, found := iterator.Next()
+		if !found {
+			break
+		}
+		if _, subscribed := value.(map[uint64]struct{})[id]; subscribed {
+			channels = append(channels, key)
+		}
+	}
+	for _, channel := range channels {
+		connections, _ := subscriptionData.Get(channel)
+		delete(connections.(map[uint64]struct{}), id)
+		subscriptionData, _, _ = subscriptionData.Insert(channel, connections)
+	}
+	ts.SetChannelSubscriptionData(subscriptionData)
+	delete(ts.connectionSubscription, id)
+}
+
+// deliver sends a published message to the subscriber id, a connection or a watcher.
+// kind is Message or PMessage. It is called with ts.mu held and tells if the subscriber exists.
+func (ts *Server) deliver(id uint64, kind, pattern, channel, message string) bool {
+	if conn := ts.GetConnection(id); conn != nil {
+		arrayMessage := []interface{}{kind, pattern, channel, message}
+		// The subscriber can be served by another event loop, so the message is queued on its own loop
+		errConn := ts.asyncWrite(conn, []byte(encodePubSub(conn, arrayMessage)))
+		if errConn != nil {
+			logger.Error("Error occurred writing to connection", "error", errConn)
+		}
+		return true
+	}
+	w, ok := ts.watchers[id]
+	if !ok {
+		return false
+	}
+	if kind == Message {
+		pattern = ""
+	}
+	select {
+	case w.messages <- watchMessage{Channel: channel, Pattern: pattern, Message: message}:
+	default:
+		// The watcher does not keep up, it is dropped like a connection over its output buffer limit
+		ts.stats.OutputBufferLimitDisconnections.Add(1)
+		logger.Warn("Closing watcher for exceeding the queued messages limit", "watcher", id, "limit", DefaultWatcherBuffer)
+		ts.removeWatcher(w, errWatcherBehind)
+	}
+	return true
+}
+
+// addWatcher subscribes a new watcher to channels
+func (ts *Server) addWatcher(channels []string, prefix bool) *watcher {
+	w := &watcher{
+		id:       nextClientID.Add(1),
+		messages: make(chan watchMessage, DefaultWatcherBuffer),
+		dropped:  make(chan struct{}),
+	}
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.watchers[w.id] = w
+	ts.subscribe(w.id, channels, prefix)
+	return w
+}
+
+// closeWatcher unsubscribes w once its stream ended
+func (ts *Server) closeWatcher(w *watcher) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if _, ok := ts.watchers[w.id]; ok {
+		ts.unsubscribeAll(w.id)
+		delete(ts.watchers, w.id)
+	}
+}
+
+// removeWatcher drops w because of err, it is called with ts.mu held
+func (ts *Server) removeWatcher(w *watcher, err error) {
+	ts.unsubscribeAll(w.id)
+	delete(ts.watchers, w.id)
+	w.err = err
+	close(w.dropped)
+}
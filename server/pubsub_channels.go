@@ -1,9 +1,9 @@
 package server
 
 import (
-	"fmt"
-
 	"github.com/panjf2000/gnet/v2"
+	"treds/acl"
+	"treds/commands"
 	"treds/resp"
 )
 
@@ -11,8 +11,17 @@ const PubSubChannelCommandName = "PUBSUBCHANNELS"
 
 func RegisterPubSubChannels(r ServerCommandRegistry) {
 	r.Add(&ServerCommandRegistration{
-		Name:    PubSubChannelCommandName,
-		Execute: executePubSubChannelsCommand(),
+		Name:       PubSubChannelCommandName,
+		Execute:    executePubSubChannelsCommand(),
+		Unlocked:   true,
+		Categories: []string{acl.CategoryPubSub},
+		Spec: commands.Spec{
+			Summary: "Returns the channels having subscribers, optionally only those having a prefix",
+			Group:   commands.GroupPubSub,
+			Args: []commands.Arg{
+				{Name: "prefix", Type: commands.ArgString, Optional: true},
+			},
+		},
 	})
 }
 
@@ -36,11 +45,12 @@ func executePubSubChannelsCommand() ExecutionHook {
 		if forwarded {
 			_, errConn := c.Write([]byte(rspFwd))
 			if errConn != nil {
-				fmt.Println("Error occurred writing to connection", errConn)
+				logger.Error("Error occurred writing to connection", "error", errConn)
 			}
 			return gnet.None
 		}
 
+		ts.mu.Lock()
 		subscriptionData := ts.GetChannelSubscriptionData()
 
 		prefix := ""
@@ -60,11 +70,12 @@ func executePubSubChannelsCommand() ExecutionHook {
 			if !found {
 				break
 			}
-			subscribers := value.(map[string]struct{})
+			subscribers := value.(map[uint64]struct{})
 			if len(subscribers) > 0 {
 				result = append(result, string(key))
 			}
 		}
+		ts.mu.Unlock()
 
 		_, errConn := c.Write([]byte(resp.EncodeStringArray(result)))
 		if errConn != nil {
@@ -5,15 +5,25 @@ import (
 	"strings"
 
 	"github.com/panjf2000/gnet/v2"
-	"treds/resp"
+	"treds/acl"
+	"treds/commands"
 )
 
 const PUnsubscribeCommandName = "PUNSUBSCRIBE"
 
 func RegisterPUnsubscribeCommand(r ServerCommandRegistry) {
 	r.Add(&ServerCommandRegistration{
-		Name:    PUnsubscribeCommandName,
-		Execute: executePUnsubscribeCommand(),
+		Name:       PUnsubscribeCommandName,
+		Execute:    executePUnsubscribeCommand(),
+		Unlocked:   true,
+		Categories: []string{acl.CategoryPubSub},
+		Spec: commands.Spec{
+			Summary: "Unsubscribes from channels subscribed with PSUBSCRIBE",
+			Group:   commands.GroupPubSub,
+			Args: []commands.Arg{
+				{Name: "channel", Type: commands.ArgString, Multiple: true},
+			},
+		},
 	})
 }
 
@@ -42,7 +52,7 @@ func executePUnsubscribeCommand() ExecutionHook {
 		if forwarded {
 			_, errConn := c.Write([]byte(rspFwd))
 			if errConn != nil {
-				fmt.Println("Error occurred writing to connection", errConn)
+				logger.Error("Error occurred writing to connection", "error", errConn)
 			}
 			return gnet.None
 		}
@@ -50,41 +60,20 @@ func executePUnsubscribeCommand() ExecutionHook {
 		// make args unique
 		args = unique(args)
 
-		subscriptionData := ts.GetChannelSubscriptionData()
-		// all channels matching all args prefix
-		allChannels := make(map[string]struct{})
-		for _, channelPrefix := range args {
-			iterator := subscriptionData.Root().Iterator()
-			iterator.SeekPrefix([]byte(channelPrefix))
-			for {
-				key, _, found := iterator.Next()
-				if !found {
-					break
-				}
-				allChannels[string(key)] = struct{}{}
-			}
-			allChannels[channelPrefix] = struct{}{}
+		ts.mu.Lock()
+		remaining := ts.unsubscribe(connID(c), args, true)
+		ts.mu.Unlock()
+		if remaining == 0 {
+			getClientConn(c).pubsub.Store(false)
 		}
 
-		for channel := range allChannels {
-			prevData, ok := subscriptionData.Get([]byte(channel))
-			if !ok {
-				prevData = make(map[string]struct{})
-			}
-			newData := prevData.(map[string]struct{})
-			delete(newData, c.RemoteAddr().String())
-			subscriptionData, _, _ = subscriptionData.Insert([]byte(channel), newData)
-		}
-
-		ts.SetChannelSubscriptionData(subscriptionData)
-
 		response := make([]interface{}, 0)
 		for indx, channel := range args {
 			response = append(response, strings.ToLower(PUnsubscribeCommandName))
 			response = append(response, channel)
 			response = append(response, indx+1)
 		}
-		_, errConn := c.Write([]byte(resp.EncodeArray(response)))
+		_, errConn := c.Write([]byte(encodePubSub(c, response)))
 		if errConn != nil {
 			ts.RespondErr(c, errConn)
 		}
@@ -2,14 +2,18 @@ package server
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/panjf2000/gnet/v2"
+	"treds/commands"
 )
 
 type ServerCommandRegistry interface {
 	Add(*ServerCommandRegistration) error
 	Retrieve(string) (*ServerCommandRegistration, error)
+	// All returns the registered commands sorted by name
+	All() []*ServerCommandRegistration
 }
 
 type CommandRegistry struct {
@@ -21,6 +25,17 @@ type ExecutionHook func(inp string, server *Server, c gnet.Conn) gnet.Action
 type ServerCommandRegistration struct {
 	Name    string
 	Execute ExecutionHook
+	// Categories are the ACL categories of the command, like acl.CategoryAdmin
+	Categories []string
+	// Subcommands lists the subcommands of commands like ACL and CLIENT with the categories they add
+	// to Categories. ACL rules can then allow or deny a single subcommand, +client|id.
+	Subcommands map[string][]string
+	// Unlocked commands run without ts.mu since they forward to the leader or wait for Raft, which would block
+	// every event loop. They take ts.mu themselves around the connection state they use.
+	Unlocked bool
+	// Spec describes the arguments of the command for COMMAND and COMMAND DOCS,
+	// server commands validate their arguments themselves
+	Spec commands.Spec
 }
 
 func NewRegistry() ServerCommandRegistry {
@@ -45,3 +60,12 @@ func (c *CommandRegistry) Retrieve(name string) (*ServerCommandRegistration, err
 
 	return c.commands[strings.ToUpper(name)], nil
 }
+
+func (c *CommandRegistry) All() []*ServerCommandRegistration {
+	all := make([]*ServerCommandRegistration, 0, len(c.commands))
+	for _, reg := range c.commands {
+		all = append(all, reg)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+	return all
+}
@@ -10,6 +10,8 @@ import (
 
 	"github.com/hashicorp/raft"
 	"github.com/panjf2000/gnet/v2"
+	"treds/acl"
+	"treds/commands"
 	"treds/resp"
 )
 
@@ -17,8 +19,17 @@ const RestoreCommandName = "RESTORE"
 
 func RegisterRestoreCommand(r ServerCommandRegistry) {
 	r.Add(&ServerCommandRegistration{
-		Name:    RestoreCommandName,
-		Execute: executeRestore(),
+		Name:       RestoreCommandName,
+		Execute:    executeRestore(),
+		Unlocked:   true,
+		Categories: []string{acl.CategoryAdmin},
+		Spec: commands.Spec{
+			Summary: "Restores a snapshot persisted on disk",
+			Group:   commands.GroupServer,
+			Args: []commands.Arg{
+				{Name: "folder_path", Type: commands.ArgString},
+			},
+		},
 	})
 }
 
@@ -47,12 +58,15 @@ func executeRestore() ExecutionHook {
 		if forwarded {
 			_, errConn := c.Write([]byte(rspFwd))
 			if errConn != nil {
-				fmt.Println("Error occurred writing to connection", errConn)
+				logger.Error("Error occurred writing to connection", "error", errConn)
 			}
 			return gnet.None
 		}
 
-		if _, ok := ts.GetClientTransaction()[c.RemoteAddr().String()]; ok {
+		ts.mu.Lock()
+		_, inTransaction := ts.GetClientTransaction()[connID(c)]
+		ts.mu.Unlock()
+		if inTransaction {
 			ts.RespondErr(c, fmt.Errorf("please run this command outside transaction"))
 			return gnet.None
 		}
@@ -64,7 +78,7 @@ func executeRestore() ExecutionHook {
 		// Read the file contents
 		metaData, err := os.ReadFile(metaFile)
 		if err != nil {
-			fmt.Println("Error reading file:", err)
+			logger.Error("Error reading file", "error", err)
 			ts.RespondErr(c, err)
 			return gnet.None
 		}
@@ -73,14 +87,14 @@ func executeRestore() ExecutionHook {
 		var metaSnapshot *raft.SnapshotMeta
 		err = json.Unmarshal(metaData, &metaSnapshot)
 		if err != nil {
-			fmt.Println("Error unmarshaling JSON:", err)
+			logger.Error("Error unmarshaling JSON", "error", err)
 			ts.RespondErr(c, err)
 			return gnet.None
 		}
 
 		file, err := os.Open(filepath.Join(snapshotPath, "state.bin"))
 		if err != nil {
-			fmt.Println("Error opening file:", err)
+			logger.Error("Error opening file", "error", err)
 			ts.RespondErr(c, err)
 			return gnet.None
 		}
@@ -4,18 +4,23 @@ import (
 	"bufio"
 	"encoding/hex"
 	"fmt"
-	"io"
 	"io/fs"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/asheshvidyut/prefix-search-optimized-radix"
 	wal "github.com/hashicorp/raft-wal"
+	"treds/acl"
 	"treds/commands"
+	"treds/config"
+	"treds/logging"
 	"treds/resp"
 	"treds/server/connPool"
 	"treds/store"
@@ -23,6 +28,13 @@ import (
 	"github.com/google/uuid"
 	"github.com/hashicorp/raft"
 	"github.com/panjf2000/gnet/v2"
+	"google.golang.org/grpc"
+)
+
+var (
+	logger = logging.Named("server")
+	// raftLogger bridges the logs of Raft into the server logs
+	raftLogger = logging.Hclog("raft")
 )
 
 type BootStrapServer struct {
@@ -37,31 +49,99 @@ type Server struct {
 
 	tredsCommandRegistry       commands.CommandRegistry
 	tredsServerCommandRegistry ServerCommandRegistry
-	clientTransaction          map[string][]string
+	clientTransaction          map[uint64][]string
 
 	channelSubscriptionData *radix.Tree
-	connectionSubscription  map[string]map[string]struct{}
-
-	connectionMap map[string]gnet.Conn
+	connectionSubscription  map[uint64]map[string]struct{}
+
+	connectionMap map[uint64]gnet.Conn
+	// watchers receive published messages without a connection, see addWatcher
+	watchers map[uint64]*watcher
+
+	// listenAddrs are the gnet addresses the event loops listen on
+	listenAddrs    []string
+	unixSocketPerm os.FileMode
+	// stagedSockets maps the unix sockets created in a staging directory to their path, see StageUnixSockets
+	stagedSockets map[string]string
+	// tlsBackend is the unix socket the TLS listeners relay to, see StartTLSProxies.
+	// Shutdown closes tlsProxies first and lets the tlsRelays deliver the last replies once the clients are drained.
+	tlsBackend string
+	tlsProxies []net.Listener
+	tlsRelays  tlsRelays
+
+	maxClients         int
+	outputBufferLimits atomic.Pointer[map[string]OutputBufferLimit]
+	stats              Stats
+	started            time.Time
+	slowlog            slowLog
+
+	// monitors are the connections which ran MONITOR. They have their own lock since store commands are
+	// streamed to them without ts.mu, monitorCount skips the lock while nobody monitors.
+	monitorMu    sync.Mutex
+	monitors     map[uint64]gnet.Conn
+	monitorCount atomic.Int64
+	// forwarded and replayedIndex keep monitors from streaming applied entries twice, see monitorApplied
+	forwarded     forwardedWrites
+	replayedIndex uint64
+
+	// config is the running configuration, CONFIG SET replaces it with ts.mu held.
+	// configFile is where CONFIG REWRITE persists it, empty without a config file.
+	config              *config.Config
+	configFile          string
+	expirySweepInterval atomic.Int64
+
+	// mu guards the connection state above, which is shared by all event loops
+	mu sync.Mutex
 
 	*gnet.BuiltinEventEngine
 	fsm              *TredsFsm
 	raft             *raft.Raft
 	id               raft.ServerID
-	raftApplyTimeout time.Duration
+	raftApplyTimeout atomic.Int64
 	connP            *connPool.ConnPool
+
+	// acl is shared with the FSM, which applies the replicated changes
+	acl *acl.ACL
+	// clusterSecret authenticates requests forwarded between nodes, see CLUSTERAUTH
+	clusterSecret string
+
+	// engine, wal, httpServer, grpcServer and metricsServer are stopped by Shutdown
+	engine        gnet.Engine
+	wal           *wal.WAL
+	httpServer    *http.Server
+	grpcServer    *grpc.Server
+	metricsServer *http.Server
+	metrics       *metrics
+	// shuttingDown refuses new connections and commands, inflight counts the commands of RESP and gRPC clients running
+	shuttingDown atomic.Bool
+	inflight     atomic.Int64
 }
 
-func New(port, segmentSize int, bindAddr, advertiseAddr, serverId string, applyTimeout time.Duration, servers []BootStrapServer) (*Server, error) {
+// New creates the node described by cfg, the Raft cluster is bootstrapped with servers
+func New(cfg *config.Config, servers []BootStrapServer, shardBy store.ShardBy, tlsOptions *TLSOptions) (*Server, error) {
+	port, serverId := cfg.Port, cfg.ID
+	// Invalid limits are refused before Raft starts
+	if _, _, err := parseClientLimits(cfg); err != nil {
+		return nil, err
+	}
 
 	storeCommandRegistry := commands.NewRegistry()
 	serverCommandRegistry := NewRegistry()
 	commands.RegisterCommands(storeCommandRegistry)
 	RegisterCommands(serverCommandRegistry)
-	tredsStore := store.NewTredsStore()
-
-	//TODO: Default config is good enough for now, but probably need to be tweaked
-	config := raft.DefaultConfig()
+	// Every event loop works on the same sharded store, a single shard behaves like a plain TredsStore
+	tredsStore := store.NewShardedStore(cfg.EventLoops, shardBy, cfg.ShardDelimiter)
+
+	raftConfig := raft.DefaultConfig()
+	raftConfig.Logger = raftLogger
+	raftConfig.HeartbeatTimeout = cfg.RaftHeartbeatTimeout
+	raftConfig.ElectionTimeout = cfg.RaftElectionTimeout
+	raftConfig.CommitTimeout = cfg.RaftCommitTimeout
+	raftConfig.LeaderLeaseTimeout = cfg.RaftLeaderLeaseTimeout
+	raftConfig.MaxAppendEntries = cfg.RaftMaxAppendEntries
+	raftConfig.SnapshotInterval = cfg.RaftSnapshotInterval
+	raftConfig.SnapshotThreshold = cfg.RaftSnapshotThreshold
+	raftConfig.TrailingLogs = cfg.RaftTrailingLogs
 
 	serverIdFileName := "server-id"
 
@@ -69,116 +149,164 @@ func New(port, segmentSize int, bindAddr, advertiseAddr, serverId string, applyT
 		// try reading from file
 		if _, err := os.Stat(serverIdFileName); err == nil {
 			// File exists, read the UUID
-			fmt.Println("File found. Reading UUID from file... If boostrap error is seen, try removing 'data' directory, " +
-				"after backup, which can be restored using RESTORE command")
+			logger.Info("Reading the server id from file, if a bootstrap error is seen try removing the 'data' directory "+
+				"after a backup, which can be restored using the RESTORE command", "file", serverIdFileName)
 			data, readErr := os.ReadFile(serverIdFileName)
 			if readErr != nil {
-				fmt.Println("Error reading UUID from file:", err)
+				logger.Error("Error reading the server id from file", "file", serverIdFileName, "error", readErr)
 			}
 			// Parse the UUID
 			id, parseErr := uuid.Parse(string(data))
 			if parseErr != nil {
-				fmt.Println("Error parsing UUID:", parseErr)
+				logger.Error("Error parsing the server id", "error", parseErr)
 			}
-			fmt.Println("UUID read from file:", id)
-			config.LocalID = raft.ServerID(id.String())
+			logger.Info("Server id read from file", "id", id.String())
+			raftConfig.LocalID = raft.ServerID(id.String())
 
 		} else if os.IsNotExist(err) {
 			// File does not exist, generate a new UUID
-			fmt.Println("File not found. Generating a new UUID...")
+			logger.Info("Server id file not found, writing a new one", "file", serverIdFileName)
 			id := uuid.New()
 
 			// Write the UUID to the file
 			err = os.WriteFile(serverIdFileName, []byte(id.String()), 0644)
 			if err != nil {
-				fmt.Println("Error writing UUID to file:", err)
+				logger.Error("Error writing the server id to file", "file", serverIdFileName, "error", err)
 			}
-			fmt.Println("New UUID generated and written to file:", id)
-			config.LocalID = raft.ServerID(id.String())
+			logger.Info("Server id written to file", "id", id)
+			raftConfig.LocalID = raft.ServerID(id.String())
 		} else {
 			// Other errors (e.g., permission issues)
-			fmt.Println("Error checking file:", err)
+			logger.Error("Error checking the server id file", "file", serverIdFileName, "error", err)
 			id := serverId
-			config.LocalID = raft.ServerID(id)
+			raftConfig.LocalID = raft.ServerID(id)
 		}
 	} else {
 		// try reading from file
 		if _, err := os.Stat(serverIdFileName); err == nil {
 			// File exists, read the UUID
-			fmt.Println("File found. Reading UUID from file...")
+			logger.Info("Reading the server id from file", "file", serverIdFileName)
 			data, readErr := os.ReadFile(serverIdFileName)
 			if readErr != nil {
-				fmt.Println("Error reading UUID from file:", err)
+				logger.Error("Error reading the server id from file", "file", serverIdFileName, "error", readErr)
 			}
 			// Parse the UUID
 			id, parseErr := uuid.Parse(string(data))
 			if parseErr != nil {
-				fmt.Println("Error parsing UUID:", parseErr)
+				logger.Error("Error parsing the server id", "error", parseErr)
 			}
 			if id.String() != serverId {
 				return nil, fmt.Errorf("UUID does not match, please fix 'server-id' file")
 			}
-			fmt.Println("UUID read from file:", id)
-			config.LocalID = raft.ServerID(id.String())
+			logger.Info("Server id read from file", "id", id.String())
+			raftConfig.LocalID = raft.ServerID(id.String())
 
 		} else if os.IsNotExist(err) {
 			// File does not exist, generate a new UUID
-			fmt.Println("File not found. Generating a new UUID...")
+			logger.Info("Server id file not found, writing a new one", "file", serverIdFileName)
 			id := serverId
 
 			// Write the UUID to the file
 			err = os.WriteFile(serverIdFileName, []byte(id), 0644)
 			if err != nil {
-				fmt.Println("Error writing UUID to file:", err)
+				logger.Error("Error writing the server id to file", "file", serverIdFileName, "error", err)
 			}
-			fmt.Println("New UUID generated and written to file:", id)
-			config.LocalID = raft.ServerID(id)
+			logger.Info("Server id written to file", "id", id)
+			raftConfig.LocalID = raft.ServerID(id)
 		} else {
 			// Other errors (e.g., permission issues)
-			fmt.Println("Error checking file:", err)
+			logger.Error("Error checking the server id file", "file", serverIdFileName, "error", err)
 			id := serverId
-			config.LocalID = raft.ServerID(id)
+			raftConfig.LocalID = raft.ServerID(id)
 		}
 	}
 
 	//This is the port used by raft for replication and such
 	// We can keep it as a separate port or do multiplexing over TCP
-	addr := fmt.Sprintf("%s:%d", bindAddr, 8300)
+	addr := fmt.Sprintf("%s:%d", cfg.Bind, cfg.RaftPort)
 
-	transport, err := raft.NewTCPTransport(addr, &net.TCPAddr{IP: net.IP(advertiseAddr), Port: port}, 10, time.Second, os.Stdout)
+	advertise := &net.TCPAddr{IP: net.IP(cfg.Advertise), Port: port}
+	connP := connPool.NewConnPool(cfg.ConnPoolTimeout)
 
-	//TODO: do not panic
-	if err != nil {
-		return nil, err
+	var transport raft.Transport
+	if tlsOptions.Enabled() {
+		streamLayer, errTLS := newTLSStreamLayer(addr, advertise, tlsOptions)
+		if errTLS != nil {
+			return nil, errTLS
+		}
+		transport = raft.NewNetworkTransportWithLogger(streamLayer, 10, time.Second, raftLogger.Named("transport"))
+
+		// Requests forwarded to the leader go to its client port, which is encrypted as well
+		clientConfig, errTLS := tlsOptions.ClientConfig()
+		if errTLS != nil {
+			return nil, errTLS
+		}
+		connP = connPool.NewTLSConnPool(cfg.ConnPoolTimeout, clientConfig)
+	} else {
+		tcpTransport, errTCP := raft.NewTCPTransportWithLogger(addr, advertise, 10, time.Second, raftLogger.Named("transport"))
+
+		//TODO: do not panic
+		if errTCP != nil {
+			return nil, errTCP
+		}
+		transport = tcpTransport
 	}
 
 	// Use raft wal as a backend store for raft
-	dir := filepath.Join("data", string(config.LocalID))
+	dir := filepath.Join(cfg.DataDir, string(raftConfig.LocalID))
 
-	err = os.MkdirAll(dir, fs.ModeDir|fs.ModePerm)
+	err := os.MkdirAll(dir, fs.ModeDir|fs.ModePerm)
 	if err != nil {
 
 		return nil, err
 	}
 
-	w, err := wal.Open(dir, wal.WithSegmentSize(segmentSize))
+	w, err := wal.Open(dir, wal.WithSegmentSize(cfg.SegmentSize))
 	if err != nil {
 
 		return nil, err
 	}
 
-	snapshotStore, err := raft.NewFileSnapshotStore("data", 3, nil)
+	snapshotStore, err := raft.NewFileSnapshotStoreWithLogger(cfg.DataDir, cfg.SnapshotRetain, raftLogger.Named("snapshot"))
 	if err != nil {
 		return nil, err
 	}
 
-	fsm := NewTredsFsm(storeCommandRegistry, tredsStore)
-	r, err := raft.NewRaft(config, fsm, w, w, snapshotStore, transport)
+	users := acl.New()
+	fsm := NewTredsFsm(storeCommandRegistry, tredsStore, users)
+	ts := &Server{
+		Port:                       port,
+		tredsCommandRegistry:       storeCommandRegistry,
+		tredsServerCommandRegistry: serverCommandRegistry,
+		fsm:                        fsm,
+		wal:                        w,
+		id:                         raftConfig.LocalID,
+		clientTransaction:          make(map[uint64][]string),
+		connP:                      connP,
+		channelSubscriptionData:    radix.New(),
+		connectionSubscription:     make(map[uint64]map[string]struct{}),
+		connectionMap:              make(map[uint64]gnet.Conn),
+		watchers:                   make(map[uint64]*watcher),
+		acl:                        users,
+		started:                    time.Now(),
+	}
+	// The entries already in the log are replayed when Raft starts
+	if ts.replayedIndex, err = w.LastIndex(); err != nil {
+		return nil, err
+	}
+	// The FSM is set up before Raft starts applying the log to it
+	ts.metrics = newMetrics(ts)
+	fsm.metrics = ts.metrics
+	fsm.monitor = ts.monitorApplied
+
+	r, err := raft.NewRaft(raftConfig, fsm, w, w, snapshotStore, transport)
 	if err != nil {
 		return nil, err
 	}
+	ts.raft = r
+	ts.metrics.watchLeader(r)
 
-	bootStrapServers := []raft.Server{{ID: config.LocalID, Address: raft.ServerAddress(addr), Suffrage: raft.Voter}}
+	bootStrapServers := []raft.Server{{ID: raftConfig.LocalID, Address: raft.ServerAddress(addr), Suffrage: raft.Voter}}
 
 	for _, server := range servers {
 		bootStrapServers = append(bootStrapServers, raft.Server{
@@ -194,45 +322,63 @@ func New(port, segmentSize int, bindAddr, advertiseAddr, serverId string, applyT
 		return nil, err
 	}
 
-	return &Server{
-		Port:                       port,
-		tredsCommandRegistry:       storeCommandRegistry,
-		tredsServerCommandRegistry: serverCommandRegistry,
-		fsm:                        fsm,
-		raft:                       r,
-		id:                         config.LocalID,
-		raftApplyTimeout:           applyTimeout,
-		clientTransaction:          make(map[string][]string),
-		connP:                      connPool.NewConnPool(time.Second * 5),
-		channelSubscriptionData:    radix.New(),
-		connectionSubscription:     make(map[string]map[string]struct{}),
-		connectionMap:              make(map[string]gnet.Conn),
-	}, nil
+	if err = ts.applyConfig(cfg); err != nil {
+		return nil, err
+	}
+	return ts, nil
 }
 
 func (ts *Server) GetChannelSubscriptionData() *radix.Tree {
 	return ts.channelSubscriptionData
 }
 
-func (ts *Server) GetConnectionSubscription() map[string]map[string]struct{} {
+func (ts *Server) GetConnectionSubscription() map[uint64]map[string]struct{} {
 	return ts.connectionSubscription
 }
 
 func (ts *Server) OnOpen(c gnet.Conn) ([]byte, gnet.Action) {
-	ts.connectionMap[c.RemoteAddr().String()] = c
+	// Each connection keeps its own reader, so frames split across reads are buffered per client
+	client := newClientConn(c)
+	if local := c.LocalAddr(); ts.tlsBackend != "" && local != nil && local.String() == ts.tlsBackend {
+		client.proxied = true
+	} else if local != nil && ts.stagedSockets[local.String()] != "" {
+		// Clients connect to the socket moved out of its staging directory
+		client.localAddr = ts.stagedSockets[local.String()]
+	}
+	// Connections are authenticated as the default user unless it requires a password
+	if user, ok := ts.acl.NoAuthUser(); ok {
+		client.user = user.Name
+	}
+	c.SetContext(client)
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if out, ok := ts.acceptClient(); !ok {
+		return out, gnet.Close
+	}
+	ts.connectionMap[connID(c)] = c
 	return nil, gnet.None
 }
 
-func (ts *Server) GetConnectionFromAddress(ra string) gnet.Conn {
-	return ts.connectionMap[ra]
+func (ts *Server) GetConnection(id uint64) gnet.Conn {
+	return ts.connectionMap[id]
 }
 
-func (ts *Server) OnBoot(_ gnet.Engine) gnet.Action {
-	fmt.Println("Server started on", ts.Port)
+func (ts *Server) OnBoot(engine gnet.Engine) gnet.Action {
+	ts.engine = engine
+	logger.Info("Server started", "port", ts.Port)
+	ts.publishUnixSockets()
+	for _, addr := range ts.listenAddrs {
+		logger.Info("Listening", "addr", addr)
+	}
+	if ts.tlsBackend != "" {
+		if err := os.Chmod(ts.tlsBackend, 0o600); err != nil {
+			logger.Error("Error setting unix socket permissions", "path", ts.tlsBackend, "error", err)
+		}
+	}
 	go func() {
 		for {
 			ts.fsm.tredsStore.CleanUpExpiredKeys()
-			time.Sleep(100 * time.Millisecond)
+			time.Sleep(ts.expirySweep())
 		}
 	}()
 	return gnet.None
@@ -246,20 +392,22 @@ func (ts *Server) isServerCommand(command string) bool {
 	return true
 }
 
-func (ts *Server) executeServerCommand(command, inp string, c gnet.Conn) gnet.Action {
-	serverCommandRegistration, err := ts.tredsServerCommandRegistry.Retrieve(command)
-	if err != nil {
-		ts.RespondErr(c, err)
-		return gnet.None
+// commandName returns the lower case name of command as shown by CLIENT LIST, command|subcommand
+// for commands having subcommands
+func (ts *Server) commandName(command string, args []string) string {
+	name := strings.ToLower(command)
+	reg, err := ts.tredsServerCommandRegistry.Retrieve(command)
+	if err == nil && reg.Subcommands != nil && len(args) > 0 {
+		name += "|" + strings.ToLower(args[0])
 	}
-	return serverCommandRegistration.Execute(inp, ts, c)
+	return name
 }
 
 func (ts *Server) GetCommandRegistry() commands.CommandRegistry {
 	return ts.tredsCommandRegistry
 }
 
-func (ts *Server) GetClientTransaction() map[string][]string {
+func (ts *Server) GetClientTransaction() map[uint64][]string {
 	return ts.clientTransaction
 }
 
@@ -267,8 +415,13 @@ func (ts *Server) GetRaft() *raft.Raft {
 	return ts.raft
 }
 
+// SetClusterSecret sets the secret nodes use to authenticate the requests they forward to the leader
+func (ts *Server) SetClusterSecret(secret string) {
+	ts.clusterSecret = secret
+}
+
 func (ts *Server) GetRaftApplyTimeout() time.Duration {
-	return ts.raftApplyTimeout
+	return time.Duration(ts.raftApplyTimeout.Load())
 }
 
 func (ts *Server) SetChannelSubscriptionData(data *radix.Tree) {
@@ -276,33 +429,89 @@ func (ts *Server) SetChannelSubscriptionData(data *radix.Tree) {
 }
 
 func (ts *Server) OnTraffic(c gnet.Conn) gnet.Action {
+	// The counter is raised before the check, so a shutdown either waits for the command or the command is refused
+	ts.inflight.Add(1)
+	defer ts.inflight.Add(-1)
+	if ts.shuttingDown.Load() {
+		ts.RespondErr(c, ErrShuttingDown)
+		return gnet.Close
+	}
 
 	data, _ := c.Next(-1)
-	inp := string(data)
-	if inp == "" {
+	if len(data) == 0 {
 		err := fmt.Errorf("empty command")
 		respErr := fmt.Sprintf("Error Executing command - %v\n", err.Error())
 		_, errConn := c.Write([]byte(resp.EncodeError(respErr)))
 		if errConn != nil {
-			fmt.Println("Error occurred writing to connection", errConn)
+			logger.Error("Error occurred writing to connection", "error", errConn)
 		}
 		return gnet.None
 	}
 
-	// Server Commands
-	command, _, err := parseCommand(inp)
-	if err != nil {
+	client := getClientConn(c)
+	if client.proxied {
+		var err error
+		if data, err = ts.readProxyHeader(client, data); err != nil {
+			ts.RespondErr(c, err)
+			return gnet.Close
+		}
+		if len(data) == 0 {
+			return gnet.None
+		}
+	}
+	reader := client.reader
+	if err := reader.Feed(data); err != nil {
 		ts.RespondErr(c, err)
-		return gnet.None
+		return gnet.Close
 	}
 
-	if ts.isServerCommand(command) {
-		return ts.executeServerCommand(command, inp, c)
+	// A single read can carry many pipelined commands, execute all complete ones in order.
+	// Replies are written in the same order since every command runs to completion before the next one.
+	for {
+		frame, args, ok, err := reader.Next()
+		if err != nil {
+			// The stream can not be resynchronised after a protocol error
+			ts.RespondErr(c, err)
+			return gnet.Close
+		}
+		if !ok {
+			return ts.checkOutputBuffer(c)
+		}
+		if action := ts.handleCommand(string(frame), args[0], args[1:], c); action != gnet.None {
+			return action
+		}
+	}
+}
+
+// handleCommand runs the command inp, decoded already into command and args by the reader of the connection
+func (ts *Server) handleCommand(inp string, command string, args []string, c gnet.Conn) gnet.Action {
+	getClientConn(c).touch(ts.commandName(command, args))
+	ts.stats.CommandsProcessed.Add(1)
+	defer ts.metrics.observeCommand(ts.metricCommand(command), time.Now())
+
+	// ACL rules are enforced before any dispatch, queued transaction commands included
+	if err := ts.authorize(command, args, c); err != nil {
+		ts.RespondErr(c, err)
+		return gnet.None
+	}
+	ts.feedMonitors(monitorSource(c), command, args)
+
+	// Server commands work on the connection state shared by all event loops, so they run one at a time, except the
+	// Unlocked ones which wait for the leader or Raft. Store commands only take the locks of the shards they touch.
+	if reg, errServer := ts.tredsServerCommandRegistry.Retrieve(command); errServer == nil {
+		if !reg.Unlocked {
+			ts.mu.Lock()
+			defer ts.mu.Unlock()
+		}
+		defer ts.slowlog.record(command, args, c, time.Now())
+		return reg.Execute(inp, ts, c)
 	}
 
 	// Check for transaction first, if transaction just enqueue the command
-	if _, ok := ts.clientTransaction[c.RemoteAddr().String()]; ok {
-		ts.clientTransaction[c.RemoteAddr().String()] = append(ts.clientTransaction[c.RemoteAddr().String()], inp)
+	ts.mu.Lock()
+	if _, ok := ts.clientTransaction[connID(c)]; ok {
+		ts.clientTransaction[connID(c)] = append(ts.clientTransaction[connID(c)], inp)
+		ts.mu.Unlock()
 		res := "QUEUED"
 		_, errConn := c.Write([]byte(resp.EncodeSimpleString(res)))
 		if errConn != nil {
@@ -310,116 +519,121 @@ func (ts *Server) OnTraffic(c gnet.Conn) gnet.Action {
 		}
 		return gnet.None
 	}
+	ts.mu.Unlock()
 
 	// No Transaction - Now execute the command
 	// Store Commands
-	return ts.executeCommand(inp, c)
+	defer ts.slowlog.record(command, args, c, time.Now())
+	return ts.executeCommand(inp, command, args, c)
 }
 
-func (ts *Server) executeCommand(inp string, c gnet.Conn) gnet.Action {
-	command, args, err := parseCommand(inp)
+func (ts *Server) executeCommand(inp string, command string, args []string, c gnet.Conn) gnet.Action {
+	res, err := ts.runCommand(inp, command, args, getClientConn(c).protocol)
 	if err != nil {
 		ts.RespondErr(c, err)
 		return gnet.None
 	}
+	_, errConn := c.Write([]byte(res))
+	if errConn != nil {
+		logger.Error("Error occurred writing to connection", "error", errConn)
+	}
+	return gnet.None
+}
+
+// runCommand executes the store command inp, decoded into command and args, and returns its RESP reply,
+// encoded for protocol. Writes are forwarded to the leader, or applied through Raft on the leader.
+func (ts *Server) runCommand(inp string, command string, args []string, protocol int) (string, error) {
 	commandReg, err := ts.tredsCommandRegistry.Retrieve(strings.ToUpper(command))
 	if err != nil {
-		ts.RespondErr(c, err)
-		return gnet.None
+		return "", err
 	}
-	if commandReg.IsWrite {
-
-		// Only writes need to be forwarded to leader
-		forwarded, rspFwd, forwardErr := ts.ForwardRequest([]byte(inp))
-
-		if forwardErr != nil {
-			fmt.Println("forward error:", forwardErr.Error())
-			ts.RespondErr(c, forwardErr)
-			return gnet.None
+	if !commandReg.IsWrite() {
+		if err = commandReg.Validate(args); err != nil {
+			return "", err
 		}
-
-		// If request is forwarded we just send back the answer from the leader to the client
-		// and stop processing
-		if forwarded {
-			_, errConn := c.Write([]byte(rspFwd))
-			if errConn != nil {
-				fmt.Println("Error occurred writing to connection", errConn)
-			}
-			return gnet.None
+		execute := commandReg.Execute
+		if commandReg.ExecuteRESP3 != nil && protocol == resp.RESP3 {
+			execute = commandReg.ExecuteRESP3
 		}
+		return execute(args, ts.fsm.tredsStore), nil
+	}
 
-		// Validation need to be done before raft Apply so an error is returned before persisting
-		if err = commandReg.Validate(args); err != nil {
-			ts.RespondErr(c, err)
-			return gnet.None
-		}
+	// Only writes need to be forwarded to leader. Monitors were sent the write with its client already.
+	monitored := ts.monitorCount.Load() > 0 && ts.raft.State() != raft.Leader
+	if monitored {
+		ts.forwarded.add(command, args)
+	}
+	forwarded, rspFwd, forwardErr := ts.ForwardRequest([]byte(inp))
+	if monitored && (!forwarded || forwardErr != nil) {
+		ts.forwarded.take(command, args)
+	}
+	if forwardErr != nil {
+		logger.Warn("Error forwarding the command to the leader", "error", forwardErr)
+		return "", forwardErr
+	}
 
-		future := ts.raft.Apply([]byte(inp), ts.raftApplyTimeout)
+	// If request is forwarded we just send back the answer from the leader to the client
+	// and stop processing
+	if forwarded {
+		return commandReg.Reply(rspFwd, protocol), nil
+	}
 
-		if err := future.Error(); err != nil {
-			ts.RespondErr(c, err)
-			return gnet.None
-		}
-		rsp := future.Response()
+	// Validation need to be done before raft Apply so an error is returned before persisting
+	if err = commandReg.Validate(args); err != nil {
+		return "", err
+	}
 
-		switch rsp.(type) {
-		case error:
-			err := rsp.(error)
-			ts.RespondErr(c, err)
-			return gnet.None
-		default:
-			_, errConn := c.Write([]byte(rsp.(string)))
-			if errConn != nil {
-				fmt.Println("Error occurred writing to connection", errConn)
-			}
-		}
-	} else {
-		if err = commandReg.Validate(args); err != nil {
-			ts.RespondErr(c, err)
-			return gnet.None
-		}
-		res := commandReg.Execute(args, ts.fsm.tredsStore)
-		_, errConn := c.Write([]byte(res))
-		if errConn != nil {
-			fmt.Println("Error occurred writing to connection", errConn)
-		}
+	future := ts.apply([]byte(inp))
+	if err = future.Error(); err != nil {
+		return "", err
 	}
-	return gnet.None
+
+	switch rsp := future.Response().(type) {
+	case error:
+		return "", rsp
+	default:
+		return commandReg.Reply(rsp.(string), protocol), nil
+	}
+}
+
+// apply submits data to Raft and waits until it is applied, the latency is recorded in the metrics
+func (ts *Server) apply(data []byte) raft.ApplyFuture {
+	defer ts.metrics.observeRaftApply(time.Now())
+	future := ts.raft.Apply(data, ts.GetRaftApplyTimeout())
+	_ = future.Error()
+	return future
 }
 
 func (ts *Server) RespondErr(c gnet.Conn, err error) {
+	// The error answers the command the client sent last
+	lastCommand, _ := getClientConn(c).lastCommand.Load().(string)
+	ts.metrics.commandError(ts.metricCommand(strings.SplitN(lastCommand, "|", 2)[0]))
 	_, errConn := c.Write([]byte(resp.EncodeError(err.Error())))
 	if errConn != nil {
-		fmt.Println("Error occurred writing to connection", errConn)
+		logger.Error("Error occurred writing to connection", "error", errConn)
 	}
 }
 
 func (ts *Server) OnClose(c gnet.Conn, _ error) gnet.Action {
 	err := ts.connP.Close()
 	if err != nil {
-		fmt.Println("Error occurred closing connection", err.Error())
+		logger.Error("Error occurred closing connection", "error", err)
 	}
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
 	ts.CleanUpClientTransaction(c)
 	ts.CleanUpChannelSubscriptions(c)
+	ts.removeMonitor(c)
+	delete(ts.connectionMap, connID(c))
 	return gnet.None
 }
 
 func (ts *Server) CleanUpClientTransaction(c gnet.Conn) {
-	delete(ts.clientTransaction, c.RemoteAddr().String())
+	delete(ts.clientTransaction, connID(c))
 }
 
 func (ts *Server) CleanUpChannelSubscriptions(c gnet.Conn) {
-	// use connectionSubscription map to delete all subscriptions for this connection
-	if _, ok := ts.connectionSubscription[c.RemoteAddr().String()]; ok {
-		for channel := range ts.connectionSubscription[c.RemoteAddr().String()] {
-			connections, found := ts.channelSubscriptionData.Get([]byte(channel))
-			if found {
-				delete(connections.(map[string]struct{}), c.RemoteAddr().String())
-				ts.channelSubscriptionData, _, _ = ts.channelSubscriptionData.Insert([]byte(channel), connections)
-			}
-		}
-		delete(ts.connectionSubscription, c.RemoteAddr().String())
-	}
+	ts.unsubscribeAll(connID(c))
 }
 
 func (ts *Server) convertRaftToTredsAddress(raftAddr string) (string, error) {
@@ -438,44 +652,6 @@ func (ts *Server) convertRaftToTredsAddress(raftAddr string) (string, error) {
 	return net.JoinHostPort(decodedAddr, stringPort), nil
 }
 
-// readAllRESPData reads all RESP data from the connection as a string
-func readAllRESPData(conn net.Conn) (string, error) {
-	defer conn.Close()
-
-	var result string
-	reader := bufio.NewReader(conn)
-
-	for {
-		// Read data from the connection
-		line, err := reader.ReadString('\n')
-		if err != nil {
-			if err == io.EOF {
-				// End of data
-				break
-			}
-			return "", err
-		}
-
-		// Append the line to the result
-		result += line
-
-		// Check for the end of RESP data
-		// If you expect specific termination (like \r\n or protocol-level signal), add logic here
-		if isEndOfRESP(result) {
-			break
-		}
-	}
-
-	return result, nil
-}
-
-// isEndOfRESP determines if the accumulated data is complete
-// Customize this function based on the RESP structure or data you expect
-func isEndOfRESP(data string) bool {
-	// Example: Customize this condition based on your protocol needs
-	// For now, assume the RESP message ends if the last line ends with \r\n
-	return strings.HasSuffix(data, "\r\n")
-}
 func decodeHexAddress(hexAddr string) (string, error) {
 	if strings.HasPrefix(hexAddr, "?") {
 		hexAddr = hexAddr[1:] // Strip the `?` prefix
@@ -487,6 +663,15 @@ func decodeHexAddress(hexAddr string) (string, error) {
 	return string(bytes), nil
 }
 
+// checkForward refuses to forward requests when the leader can not know who sent them. Without a cluster secret
+// the leader runs them as the default user, which is only right while every connection is the default user.
+func (ts *Server) checkForward() error {
+	if ts.clusterSecret == "" && ts.acl.Custom() {
+		return fmt.Errorf("requests can not be forwarded to the leader, ACL users are set and cluster-secret is not")
+	}
+	return nil
+}
+
 func (ts *Server) ForwardRequest(data []byte) (bool, string, error) {
 	// create a new channel based pool with an initial capacity of 5 and maximum
 	// capacity of 30. The factory will create 5 initial connections and put it
@@ -498,27 +683,46 @@ func (ts *Server) ForwardRequest(data []byte) (bool, string, error) {
 		return false, "", nil
 	}
 
+	if err := ts.checkForward(); err != nil {
+		return false, "", err
+	}
+
 	tredsAddr, err := ts.convertRaftToTredsAddress(string(addr))
 
 	if err != nil {
-		fmt.Println("Error occurred converting raft to treds address", addr)
+		logger.Error("Error occurred converting raft to treds address", "addr", addr, "error", err)
 		return false, "", err
 	}
 
 	conn, err := ts.connP.Dial("tcp", tredsAddr)
 	if err != nil {
-		fmt.Println("Error occurred connecting to treds server", tredsAddr)
+		logger.Error("Error occurred connecting to treds server", "addr", tredsAddr, "error", err)
 		return false, "", nil
 	}
 	defer conn.Close()
+	if ts.clusterSecret != "" {
+		// Authenticate as a node first, the leader then skips the ACL checks already done here
+		data = append([]byte(resp.EncodeStringArray([]string{ClusterAuthCommandName, ts.clusterSecret})), data...)
+	}
 	_, err = conn.Write(data)
 	if err != nil {
-		fmt.Println("Error occurred writing to connection", tredsAddr)
+		logger.Error("Error occurred writing to connection", "addr", tredsAddr, "error", err)
 		return false, "", nil
 	}
-	line, rerr := readAllRESPData(conn)
+	reader := bufio.NewReader(conn)
+	if ts.clusterSecret != "" {
+		authReply, rerr := resp.ReadReply(reader)
+		if rerr != nil {
+			logger.Error("Error occurred reading from connection", "addr", tredsAddr, "error", rerr)
+			return false, "", nil
+		}
+		if strings.HasPrefix(authReply, "-") {
+			return false, "", fmt.Errorf("cluster authentication failed: %s", strings.TrimSpace(authReply[1:]))
+		}
+	}
+	line, rerr := resp.ReadReply(reader)
 	if rerr != nil {
-		fmt.Println("Error occurred reading from connection", tredsAddr)
+		logger.Error("Error occurred reading from connection", "addr", tredsAddr, "error", rerr)
 		return false, "", nil
 	}
 	return true, line, nil
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/panjf2000/gnet/v2"
+	"github.com/stretchr/testify/require"
+	"treds/acl"
+	"treds/commands"
+	"treds/resp"
+)
+
+// contextConn is a gnet.Conn only holding the client state
+type contextConn struct {
+	gnet.Conn
+	client *clientConn
+}
+
+func (c *contextConn) Context() interface{} {
+	return c.client
+}
+
+func TestUnlockedServerCommands(t *testing.T) {
+	registry := NewRegistry()
+	RegisterCommands(registry)
+	// Commands forwarding to the leader or waiting for Raft must not block the other event loops
+	for _, name := range []string{ACLCommandName, MultiCommandName, ExecCommandName, DiscardCommandName, PublishCommandName,
+		SubscribeCommandName, UnsubscribeCommandName, SnapshotCommandName, RestoreCommandName} {
+		reg, err := registry.Retrieve(name)
+		require.NoError(t, err)
+		require.True(t, reg.Unlocked, name)
+	}
+
+	var lockFree bool
+	require.NoError(t, registry.Add(&ServerCommandRegistration{
+		Name:     "WAITRAFT",
+		Unlocked: true,
+		Execute: func(inp string, ts *Server, c gnet.Conn) gnet.Action {
+			lockFree = ts.mu.TryLock()
+			if lockFree {
+				ts.mu.Unlock()
+			}
+			return gnet.None
+		},
+	}))
+	ts := &Server{tredsCommandRegistry: commands.NewRegistry(), tredsServerCommandRegistry: registry, acl: acl.New()}
+	conn := &contextConn{client: &clientConn{user: acl.DefaultUser}}
+	ts.handleCommand(resp.EncodeStringArray([]string{"WAITRAFT"}), "WAITRAFT", nil, conn)
+	require.True(t, lockFree)
+}
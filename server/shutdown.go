@@ -0,0 +1,161 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/panjf2000/gnet/v2"
+	"treds/resp"
+)
+
+// drainPollInterval is how often a shutdown checks for in-flight commands
+const drainPollInterval = 10 * time.Millisecond
+
+// Shutdown stops the server in order: new connections are refused, in-flight commands of RESP, HTTP and gRPC
+// clients are finished, clients with a pending MULTI get an error, leadership is transferred when this node is
+// the leader, a Raft snapshot is taken and the WAL is closed. ctx bounds the drain of the clients.
+// The TLS proxies stop listening and the unix sockets are unlinked first. gnet only closes its TCP listeners with
+// its event loops, so TCP connections reaching them before the event loops stop are refused with ErrShuttingDown.
+func (ts *Server) Shutdown(ctx context.Context) error {
+	if !ts.shuttingDown.CompareAndSwap(false, true) {
+		return fmt.Errorf("server is already shutting down")
+	}
+	logger.Info("Shutting down, draining clients")
+	ts.closeTLSProxies()
+	ts.unlinkUnixSockets()
+
+	ts.mu.Lock()
+	for _, w := range ts.watchers {
+		ts.removeWatcher(w, ErrShuttingDown)
+	}
+	ts.mu.Unlock()
+
+	if ts.httpServer != nil {
+		if err := ts.httpServer.Shutdown(ctx); err != nil {
+			logger.Error("Error occurred shutting down HTTP gateway", "error", err)
+		}
+	}
+	if ts.metricsServer != nil {
+		if err := ts.metricsServer.Shutdown(ctx); err != nil {
+			logger.Error("Error occurred shutting down metrics server", "error", err)
+		}
+	}
+	if ts.grpcServer != nil {
+		stopped := make(chan struct{})
+		go func() {
+			ts.grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			ts.grpcServer.Stop()
+		}
+	}
+
+	if err := ts.drain(ctx); err != nil {
+		logger.Warn("Shutting down with commands in flight", "error", err)
+	}
+	if err := ts.abortTransactions(ctx); err != nil {
+		logger.Warn("Stopping event loops with transaction aborts unsent", "error", err)
+	}
+
+	if err := ts.engine.Stop(ctx); err != nil {
+		logger.Error("Error occurred stopping event loops", "error", err)
+	}
+	// The event loops closed the relayed connections, the relays still deliver the replies written before
+	if err := ts.tlsRelays.wait(ctx); err != nil {
+		logger.Warn("Closing TLS connections with replies in flight", "error", err)
+	}
+
+	if ts.raft.State() == raft.Leader && len(ts.raftPeers()) > 1 {
+		if err := ts.raft.LeadershipTransfer().Error(); err != nil {
+			logger.Error("Error occurred transferring leadership", "error", err)
+		}
+	}
+	if err := ts.raft.Snapshot().Error(); err != nil && !errors.Is(err, raft.ErrNothingNewToSnapshot) {
+		logger.Error("Error occurred taking snapshot", "error", err)
+	}
+	if err := ts.raft.Shutdown().Error(); err != nil {
+		return err
+	}
+	return ts.wal.Close()
+}
+
+// unlinkUnixSockets removes the unix sockets the event loops listen on so clients can not connect to them anymore.
+// The socket the TLS relays connect to is kept for the connections accepted before the proxies closed.
+func (ts *Server) unlinkUnixSockets() {
+	for _, addr := range ts.listenAddrs {
+		path, ok := strings.CutPrefix(addr, unixScheme)
+		if !ok || path == ts.tlsBackend {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			logger.Error("Error occurred removing unix socket", "path", path, "error", err)
+		}
+	}
+}
+
+// drain waits until no command of a RESP or gRPC client is running, or ctx is done
+func (ts *Server) drain(ctx context.Context) error {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for ts.inflight.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// abortTransactions discards the transactions started with MULTI and tells their clients. It returns once the
+// event loops wrote the errors, so stopping them does not drop the errors, or when ctx is done.
+func (ts *Server) abortTransactions(ctx context.Context) error {
+	var written sync.WaitGroup
+	ts.mu.Lock()
+	for id := range ts.clientTransaction {
+		if conn := ts.GetConnection(id); conn != nil {
+			written.Add(1)
+			errConn := conn.AsyncWrite([]byte(resp.EncodeError("EXECABORT Transaction discarded because the server is shutting down")),
+				func(gnet.Conn, error) error {
+					written.Done()
+					return nil
+				})
+			if errConn != nil {
+				written.Done()
+				logger.Error("Error occurred writing to connection", "error", errConn)
+			}
+		}
+		delete(ts.clientTransaction, id)
+	}
+	ts.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		written.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// raftPeers returns the servers of the Raft configuration
+func (ts *Server) raftPeers() []raft.Server {
+	future := ts.raft.GetConfiguration()
+	if err := future.Error(); err != nil {
+		return nil
+	}
+	return future.Configuration().Servers
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/panjf2000/gnet/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShutdownRefusesClients(t *testing.T) {
+	ts, _ := newTestClients(t, 1)
+	ts.shuttingDown.Store(true)
+	out, ok := ts.acceptClient()
+	require.False(t, ok)
+	require.Equal(t, "-ERR server is shutting down\r\n", string(out))
+}
+
+func TestShutdownDrain(t *testing.T) {
+	ts, _ := newTestClients(t, 0)
+	require.NoError(t, ts.drain(context.Background()))
+
+	ts.inflight.Add(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, ts.drain(ctx), context.DeadlineExceeded)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		ts.inflight.Add(-1)
+	}()
+	require.NoError(t, ts.drain(context.Background()))
+}
+
+func TestShutdownAbortsTransactions(t *testing.T) {
+	ts, conns := newTestClients(t, 2)
+	ts.clientTransaction[connID(conns[0])] = []string{"*3\r\n$3\r\nSET\r\n$1\r\nk\r\n$1\r\nv\r\n"}
+
+	require.NoError(t, ts.abortTransactions(context.Background()))
+	require.Empty(t, ts.clientTransaction)
+	require.Equal(t, "-EXECABORT Transaction discarded because the server is shutting down\r\n", string(conns[0].written))
+	require.Empty(t, conns[1].written)
+}
+
+// queuedConn keeps the asynchronous writes queued until flush, like an event loop which did not run them yet
+type queuedConn struct {
+	*fakeConn
+	mu        sync.Mutex
+	callbacks []gnet.AsyncCallback
+}
+
+func (q *queuedConn) AsyncWrite(buf []byte, callback gnet.AsyncCallback) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.written = append(q.written, buf...)
+	q.callbacks = append(q.callbacks, callback)
+	return nil
+}
+
+func (q *queuedConn) flush() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, callback := range q.callbacks {
+		_ = callback(q, nil)
+	}
+	q.callbacks = nil
+}
+
+func TestShutdownWaitsForTransactionAborts(t *testing.T) {
+	ts, conns := newTestClients(t, 1)
+	conn := &queuedConn{fakeConn: conns[0]}
+	ts.connectionMap[connID(conns[0])] = conn
+	ts.clientTransaction[connID(conns[0])] = []string{"*3\r\n$3\r\nSET\r\n$1\r\nk\r\n$1\r\nv\r\n"}
+
+	// The event loops are stopped only once they wrote the aborts, or when the shutdown times out
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, ts.abortTransactions(ctx), context.DeadlineExceeded)
+
+	ts.clientTransaction[connID(conns[0])] = []string{"*3\r\n$3\r\nSET\r\n$1\r\nk\r\n$1\r\nv\r\n"}
+	conn.flush()
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		conn.flush()
+	}()
+	require.NoError(t, ts.abortTransactions(context.Background()))
+}
+
+func TestShutdownUnlinksUnixSockets(t *testing.T) {
+	ts, _ := newTestClients(t, 0)
+	dir := t.TempDir()
+	for _, name := range []string{"treds.sock", "tls.sock"} {
+		listener, err := net.Listen("unix", filepath.Join(dir, name))
+		require.NoError(t, err)
+		defer listener.Close()
+		ts.listenAddrs = append(ts.listenAddrs, unixScheme+filepath.Join(dir, name))
+	}
+	ts.listenAddrs = append(ts.listenAddrs, "tcp://127.0.0.1:7997")
+	ts.tlsBackend = filepath.Join(dir, "tls.sock")
+
+	ts.unlinkUnixSockets()
+	_, err := net.Dial("unix", filepath.Join(dir, "treds.sock"))
+	require.Error(t, err)
+	conn, err := net.Dial("unix", ts.tlsBackend)
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+}
@@ -0,0 +1,260 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/panjf2000/gnet/v2"
+	"treds/acl"
+	"treds/commands"
+	"treds/resp"
+)
+
+const SlowlogCommandName = "SLOWLOG"
+
+const (
+	// slowlogMaxArgs and slowlogMaxArgLen bound the arguments kept per entry, the command name included
+	slowlogMaxArgs   = 32
+	slowlogMaxArgLen = 128
+	// slowlogDefaultCount is the number of entries SLOWLOG GET returns without a count
+	slowlogDefaultCount = 10
+)
+
+// slowlogRedacted are the commands whose arguments are not kept since they can carry passwords, like monitorHidden
+var slowlogRedacted = map[string]struct{}{
+	AuthCommandName:        {},
+	HelloCommandName:       {},
+	ClusterAuthCommandName: {},
+	ACLCommandName:         {},
+}
+
+type slowlogEntry struct {
+	id       int64
+	time     time.Time
+	duration time.Duration
+	args     []string
+	clientID uint64
+	addr     string
+	name     string
+}
+
+// slowLog is a ring buffer of the latest commands which ran longer than the threshold.
+// It has its own lock since store commands are recorded without ts.mu.
+type slowLog struct {
+	mu      sync.Mutex
+	entries []slowlogEntry
+	// head is where the next entry is written, size is the number of entries kept
+	head   int
+	size   int
+	nextID int64
+	// threshold is in microseconds, a negative threshold records nothing
+	threshold atomic.Int64
+}
+
+// configure sets the threshold and the number of entries kept, the latest entries are kept when it shrinks
+func (l *slowLog) configure(threshold int, maxLen int) {
+	l.threshold.Store(int64(threshold))
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if maxLen == len(l.entries) {
+		return
+	}
+	latest := l.latest(maxLen)
+	l.entries = make([]slowlogEntry, maxLen)
+	l.head, l.size = 0, 0
+	for i := len(latest) - 1; i >= 0; i-- {
+		l.push(latest[i])
+	}
+}
+
+// record adds the command to the log when it ran longer than the threshold since start
+func (l *slowLog) record(command string, args []string, c gnet.Conn, start time.Time) {
+	duration := time.Since(start)
+	threshold := l.threshold.Load()
+	if threshold < 0 || duration < time.Duration(threshold)*time.Microsecond {
+		return
+	}
+	if _, redacted := slowlogRedacted[strings.ToUpper(command)]; redacted {
+		args = redactArgs(args)
+	}
+	client := getClientConn(c)
+	entry := slowlogEntry{
+		time:     start,
+		duration: duration,
+		args:     truncateSlowlogArgs(append([]string{command}, args...)),
+		clientID: client.id,
+		addr:     client.addr,
+		name:     client.name,
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.entries) == 0 {
+		return
+	}
+	entry.id = l.nextID
+	l.nextID++
+	l.push(entry)
+}
+
+// redactArgs replaces every argument with (redacted)
+func redactArgs(args []string) []string {
+	redacted := make([]string, len(args))
+	for i := range redacted {
+		redacted[i] = "(redacted)"
+	}
+	return redacted
+}
+
+func (l *slowLog) push(entry slowlogEntry) {
+	l.entries[l.head] = entry
+	l.head = (l.head + 1) % len(l.entries)
+	if l.size < len(l.entries) {
+		l.size++
+	}
+}
+
+// latest returns up to n entries, newest first, every entry when n is negative. It is called with l.mu held.
+func (l *slowLog) latest(n int) []slowlogEntry {
+	if n < 0 || n > l.size {
+		n = l.size
+	}
+	entries := make([]slowlogEntry, 0, n)
+	for i := 1; i <= n; i++ {
+		entries = append(entries, l.entries[(l.head-i+len(l.entries))%len(l.entries)])
+	}
+	return entries
+}
+
+func (l *slowLog) get(n int) []slowlogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.latest(n)
+}
+
+func (l *slowLog) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.size
+}
+
+func (l *slowLog) reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	clear(l.entries)
+	l.head, l.size = 0, 0
+}
+
+// truncateSlowlogArgs keeps at most slowlogMaxArgs arguments of at most slowlogMaxArgLen bytes, like Redis
+func truncateSlowlogArgs(args []string) []string {
+	kept := args
+	if len(args) > slowlogMaxArgs {
+		kept = args[:slowlogMaxArgs-1]
+	}
+	truncated := make([]string, 0, len(kept)+1)
+	for _, arg := range kept {
+		if len(arg) > slowlogMaxArgLen {
+			arg = fmt.Sprintf("%s... (%d more bytes)", arg[:slowlogMaxArgLen], len(arg)-slowlogMaxArgLen)
+		}
+		truncated = append(truncated, arg)
+	}
+	if len(args) > len(kept) {
+		truncated = append(truncated, fmt.Sprintf("... (%d more arguments)", len(args)-len(kept)))
+	}
+	return truncated
+}
+
+func RegisterSlowlogCommand(r ServerCommandRegistry) {
+	r.Add(&ServerCommandRegistration{
+		Name:       SlowlogCommandName,
+		Execute:    executeSlowlog(),
+		Categories: []string{acl.CategoryAdmin},
+		Spec: commands.Spec{
+			Summary: "Returns the commands which ran longer than slowlog-log-slower-than, SLOWLOG GET, LEN and RESET",
+			Group:   commands.GroupServer,
+			Args: []commands.Arg{
+				{Name: "subcommand", Type: commands.ArgString},
+				{Name: "arg", Type: commands.ArgString, Optional: true},
+			},
+		},
+		Subcommands: map[string][]string{
+			"get":   nil,
+			"len":   nil,
+			"reset": nil,
+		},
+	})
+}
+
+// executeSlowlog runs SLOWLOG GET [count]|LEN|RESET, the log is local to a node
+func executeSlowlog() ExecutionHook {
+	return func(inp string, ts *Server, c gnet.Conn) gnet.Action {
+		_, args, err := parseCommand(inp)
+		if err != nil {
+			ts.RespondErr(c, err)
+			return gnet.None
+		}
+		if len(args) == 0 {
+			ts.RespondErr(c, fmt.Errorf("invalid number of arguments"))
+			return gnet.None
+		}
+
+		var res string
+		switch strings.ToUpper(args[0]) {
+		case "GET":
+			res, err = ts.slowlogGet(args[1:])
+		case "LEN":
+			res = resp.EncodeInteger(ts.slowlog.len())
+		case "RESET":
+			ts.slowlog.reset()
+			res = resp.EncodeSimpleString("OK")
+		default:
+			err = fmt.Errorf("unknown subcommand '%s'", args[0])
+		}
+		if err != nil {
+			ts.RespondErr(c, err)
+			return gnet.None
+		}
+		_, errConn := c.Write([]byte(res))
+		if errConn != nil {
+			logger.Error("Error occurred writing to connection", "error", errConn)
+		}
+		return gnet.None
+	}
+}
+
+// slowlogGet returns the latest entries, newest first, as
+// [id, unix timestamp, microseconds, [command args...], client address, client name, client id]
+func (ts *Server) slowlogGet(args []string) (string, error) {
+	count := slowlogDefaultCount
+	if len(args) > 1 {
+		return "", fmt.Errorf("invalid number of arguments")
+	}
+	if len(args) == 1 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < -1 {
+			return "", fmt.Errorf("count should be greater than or equal to -1")
+		}
+		count = n
+	}
+	entries := ts.slowlog.get(count)
+	reply := make([]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		entryArgs := make([]interface{}, 0, len(entry.args))
+		for _, arg := range entry.args {
+			entryArgs = append(entryArgs, arg)
+		}
+		reply = append(reply, []interface{}{
+			int(entry.id),
+			int(entry.time.Unix()),
+			int(entry.duration.Microseconds()),
+			entryArgs,
+			entry.addr,
+			entry.name,
+			int(entry.clientID),
+		})
+	}
+	return resp.EncodeArray(reply), nil
+}
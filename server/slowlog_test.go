@@ -0,0 +1,69 @@
+package server
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"treds/config"
+	"treds/resp"
+)
+
+func TestSlowlog(t *testing.T) {
+	ts, conns := newTestClients(t, 1)
+	require.NoError(t, ts.applyConfig(config.Default()))
+	require.NoError(t, ts.configSet([]string{"slowlog-log-slower-than", "1000", "slowlog-max-len", "2"}))
+
+	// Only commands running longer than the threshold are kept
+	ts.slowlog.record("GET", []string{"k"}, conns[0], time.Now())
+	require.Equal(t, 0, ts.slowlog.len())
+
+	start := time.Now().Add(-time.Second)
+	ts.slowlog.record("KEYS", []string{".*"}, conns[0], start)
+	ts.slowlog.record("DQUERY", []string{"c", "{}"}, conns[0], start)
+	ts.slowlog.record("KEYSZ", []string{"0", "", strings.Repeat("a", 200)}, conns[0], start)
+	require.Equal(t, 2, ts.slowlog.len())
+
+	entries := ts.slowlog.get(-1)
+	require.Equal(t, int64(2), entries[0].id)
+	require.Equal(t, []string{"KEYSZ", "0", "", strings.Repeat("a", 128) + "... (72 more bytes)"}, entries[0].args)
+	require.Equal(t, []string{"DQUERY", "c", "{}"}, entries[1].args)
+	require.Equal(t, connID(conns[0]), entries[0].clientID)
+	require.GreaterOrEqual(t, entries[0].duration, time.Second)
+
+	res, err := ts.slowlogGet([]string{"1"})
+	require.NoError(t, err)
+	require.Equal(t, resp.EncodeArray([]interface{}{[]interface{}{
+		2, int(start.Unix()), int(entries[0].duration.Microseconds()),
+		[]interface{}{"KEYSZ", "0", "", strings.Repeat("a", 128) + "... (72 more bytes)"},
+		"10.0.0.1:5000", "", int(connID(conns[0])),
+	}}), res)
+	_, err = ts.slowlogGet([]string{"-2"})
+	require.Error(t, err)
+
+	// Shrinking keeps the latest entries
+	require.NoError(t, ts.configSet([]string{"slowlog-max-len", "1"}))
+	require.Equal(t, int64(2), ts.slowlog.get(-1)[0].id)
+
+	// Passwords are not kept
+	require.NoError(t, ts.configSet([]string{"slowlog-max-len", "2"}))
+	ts.slowlog.record("ACL", []string{"SETUSER", "alice", ">secret"}, conns[0], start)
+	ts.slowlog.record("auth", []string{"alice", "secret"}, conns[0], start)
+	require.Equal(t, []string{"auth", "(redacted)", "(redacted)"}, ts.slowlog.get(1)[0].args)
+	require.Equal(t, []string{"ACL", "(redacted)", "(redacted)", "(redacted)"}, ts.slowlog.get(2)[1].args)
+
+	ts.slowlog.reset()
+	require.Equal(t, 0, ts.slowlog.len())
+
+	require.NoError(t, ts.configSet([]string{"slowlog-log-slower-than", "-1"}))
+	ts.slowlog.record("KEYS", []string{".*"}, conns[0], start)
+	require.Equal(t, 0, ts.slowlog.len())
+}
+
+func TestTruncateSlowlogArgs(t *testing.T) {
+	args := make([]string, 40)
+	truncated := truncateSlowlogArgs(args)
+	require.Len(t, truncated, slowlogMaxArgs)
+	require.Equal(t, "... (9 more arguments)", truncated[slowlogMaxArgs-1])
+}
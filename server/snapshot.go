@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	"github.com/panjf2000/gnet/v2"
+	"treds/acl"
+	"treds/commands"
 	"treds/resp"
 )
 
@@ -11,8 +13,14 @@ const SnapshotCommandName = "SNAPSHOT"
 
 func RegisterSnapshotCommand(r ServerCommandRegistry) {
 	r.Add(&ServerCommandRegistration{
-		Name:    SnapshotCommandName,
-		Execute: executeSnapshot(),
+		Name:       SnapshotCommandName,
+		Execute:    executeSnapshot(),
+		Unlocked:   true,
+		Categories: []string{acl.CategoryAdmin},
+		Spec: commands.Spec{
+			Summary: "Persists the store on disk",
+			Group:   commands.GroupServer,
+		},
 	})
 }
 
@@ -30,12 +38,15 @@ func executeSnapshot() ExecutionHook {
 		if forwarded {
 			_, errConn := c.Write([]byte(rspFwd))
 			if errConn != nil {
-				fmt.Println("Error occurred writing to connection", errConn)
+				logger.Error("Error occurred writing to connection", "error", errConn)
 			}
 			return gnet.None
 		}
 
-		if _, ok := ts.GetClientTransaction()[c.RemoteAddr().String()]; ok {
+		ts.mu.Lock()
+		_, inTransaction := ts.GetClientTransaction()[connID(c)]
+		ts.mu.Unlock()
+		if inTransaction {
 			ts.RespondErr(c, fmt.Errorf("please run this command outside transaction"))
 			return gnet.None
 		}
@@ -0,0 +1,15 @@
+package server
+
+import "sync/atomic"
+
+// Stats counts the events of a node since it started
+type Stats struct {
+	ConnectionsReceived             atomic.Int64
+	CommandsProcessed               atomic.Int64
+	RejectedConnections             atomic.Int64
+	OutputBufferLimitDisconnections atomic.Int64
+}
+
+func (ts *Server) Stats() *Stats {
+	return &ts.stats
+}
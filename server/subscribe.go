@@ -5,15 +5,25 @@ import (
 	"strings"
 
 	"github.com/panjf2000/gnet/v2"
-	"treds/resp"
+	"treds/acl"
+	"treds/commands"
 )
 
 const SubscribeCommandName = "SUBSCRIBE"
 
 func RegisterSubscribeCommand(r ServerCommandRegistry) {
 	r.Add(&ServerCommandRegistration{
-		Name:    SubscribeCommandName,
-		Execute: executeSubscribeCommandName(),
+		Name:       SubscribeCommandName,
+		Execute:    executeSubscribeCommandName(),
+		Unlocked:   true,
+		Categories: []string{acl.CategoryPubSub},
+		Spec: commands.Spec{
+			Summary: "Subscribes to channels",
+			Group:   commands.GroupPubSub,
+			Args: []commands.Arg{
+				{Name: "channel", Type: commands.ArgString, Multiple: true},
+			},
+		},
 	})
 }
 
@@ -42,7 +52,7 @@ func executeSubscribeCommandName() ExecutionHook {
 		if forwarded {
 			_, errConn := c.Write([]byte(rspFwd))
 			if errConn != nil {
-				fmt.Println("Error occurred writing to connection", errConn)
+				logger.Error("Error occurred writing to connection", "error", errConn)
 			}
 			return gnet.None
 		}
@@ -50,36 +60,18 @@ func executeSubscribeCommandName() ExecutionHook {
 		// make args unique
 		args = unique(args)
 
-		subscriptionData := ts.GetChannelSubscriptionData()
-		// all channels matching unique
-		allChannels := make(map[string]struct{})
-		for _, channel := range args {
-			allChannels[channel] = struct{}{}
-		}
-
-		for channel := range allChannels {
-			prevData, ok := subscriptionData.Get([]byte(channel))
-			if !ok {
-				prevData = make(map[string]struct{})
-			}
-			newData := prevData.(map[string]struct{})
-			newData[c.RemoteAddr().String()] = struct{}{}
-			subscriptionData, _, _ = subscriptionData.Insert([]byte(channel), newData)
-		}
-
-		ts.SetChannelSubscriptionData(subscriptionData)
-		if _, ok := ts.GetConnectionSubscription()[c.RemoteAddr().String()]; !ok {
-			ts.GetConnectionSubscription()[c.RemoteAddr().String()] = make(map[string]struct{})
-		}
+		ts.mu.Lock()
+		ts.subscribe(connID(c), args, false)
+		ts.mu.Unlock()
 
 		response := make([]interface{}, 0)
 		for indx, channel := range args {
 			response = append(response, strings.ToLower(SubscribeCommandName))
 			response = append(response, channel)
-			ts.GetConnectionSubscription()[c.RemoteAddr().String()][channel] = struct{}{}
 			response = append(response, indx+1)
 		}
-		_, errConn := c.Write([]byte(resp.EncodeArray(response)))
+		getClientConn(c).pubsub.Store(true)
+		_, errConn := c.Write([]byte(encodePubSub(c, response)))
 		if errConn != nil {
 			ts.RespondErr(c, errConn)
 		}
@@ -0,0 +1,336 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/raft"
+)
+
+// TLSOptions holds the certificate files used to encrypt the client port and the Raft transport.
+// TLS is enabled when both CertFile and KeyFile are set.
+type TLSOptions struct {
+	CertFile string
+	KeyFile  string
+	// CAFile verifies the certificates of peers, the system pool is used when it is empty
+	CAFile string
+	// AuthClients requires clients of the client port to present a certificate signed by the CA (mutual TLS)
+	AuthClients bool
+}
+
+func (o *TLSOptions) Enabled() bool {
+	return o != nil && o.CertFile != "" && o.KeyFile != ""
+}
+
+func (o *TLSOptions) load() (tls.Certificate, *x509.CertPool, error) {
+	cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("failed to load TLS key pair: %w", err)
+	}
+	if o.CAFile == "" {
+		return cert, nil, nil
+	}
+	caPem, err := os.ReadFile(o.CAFile)
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPem) {
+		return tls.Certificate{}, nil, fmt.Errorf("no certificate found in CA file %s", o.CAFile)
+	}
+	return cert, pool, nil
+}
+
+// ServerConfig returns the configuration of a listener, requireClientCert enables mutual TLS
+func (o *TLSOptions) ServerConfig(requireClientCert bool) (*tls.Config, error) {
+	cert, pool, err := o.load()
+	if err != nil {
+		return nil, err
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    pool,
+		MinVersion:   tls.VersionTLS12,
+	}
+	if requireClientCert {
+		if pool == nil {
+			return nil, fmt.Errorf("mutual TLS requires a CA file")
+		}
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+// ClientConfig returns the configuration used to dial other nodes, the node certificate
+// is presented so peers requiring mutual TLS accept the connection
+func (o *TLSOptions) ClientConfig() (*tls.Config, error) {
+	cert, pool, err := o.load()
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// tlsStreamLayer is a raft.StreamLayer encrypting the Raft traffic between nodes
+type tlsStreamLayer struct {
+	net.Listener
+	advertise    net.Addr
+	clientConfig *tls.Config
+}
+
+func newTLSStreamLayer(bindAddr string, advertise net.Addr, options *TLSOptions) (*tlsStreamLayer, error) {
+	// Raft peers always authenticate each other when a CA is given
+	serverConfig, err := options.ServerConfig(options.CAFile != "")
+	if err != nil {
+		return nil, err
+	}
+	clientConfig, err := options.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	listener, err := tls.Listen("tcp", bindAddr, serverConfig)
+	if err != nil {
+		return nil, err
+	}
+	if advertise == nil {
+		advertise = listener.Addr()
+	}
+	return &tlsStreamLayer{
+		Listener:     listener,
+		advertise:    advertise,
+		clientConfig: clientConfig,
+	}, nil
+}
+
+func (t *tlsStreamLayer) Addr() net.Addr {
+	return t.advertise
+}
+
+func (t *tlsStreamLayer) Dial(address raft.ServerAddress, timeout time.Duration) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	return tls.DialWithDialer(dialer, "tcp", string(address), t.clientConfig)
+}
+
+// StartTLSProxies terminates TLS on the TCP addresses of listenAddrs, gnet has no TLS support of its own.
+// Every connection is relayed to a unix socket only the user running Treds can reach, in a private folder of
+// dataDir, and starts with a PROXY protocol header so the event loops see the address of the client.
+// It returns the addresses the event loops listen on, unix sockets stay in plaintext since they are local.
+// Shutdown stops the proxies.
+func (ts *Server) StartTLSProxies(listenAddrs []string, dataDir string, options *TLSOptions) ([]string, error) {
+	config, err := options.ServerConfig(options.AuthClients)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(dataDir, "run")
+	if err = os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	// The folder may exist with wider permissions
+	if err = os.Chmod(dir, 0o700); err != nil {
+		return nil, err
+	}
+	ts.tlsBackend = filepath.Join(dir, fmt.Sprintf("tls-%d.sock", ts.Port))
+
+	gnetAddrs := []string{unixScheme + ts.tlsBackend}
+	for _, addr := range listenAddrs {
+		if !IsTCPAddr(addr) {
+			gnetAddrs = append(gnetAddrs, addr)
+			continue
+		}
+		listener, errListen := tls.Listen("tcp", strings.TrimPrefix(addr, tcpScheme), config)
+		if errListen != nil {
+			ts.closeTLSProxies()
+			return nil, errListen
+		}
+		go func() {
+			for {
+				conn, errAccept := listener.Accept()
+				if errAccept != nil {
+					return
+				}
+				if ts.tlsRelays.add(conn) {
+					go ts.tlsRelays.relay(conn, ts.tlsBackend)
+				}
+			}
+		}()
+		ts.tlsProxies = append(ts.tlsProxies, listener)
+	}
+	return gnetAddrs, nil
+}
+
+// closeTLSProxies stops accepting TLS connections, the connections relayed already stay open
+func (ts *Server) closeTLSProxies() {
+	for _, proxy := range ts.tlsProxies {
+		_ = proxy.Close()
+	}
+}
+
+// tlsRelays are the TLS connections being relayed to the event loops, with their connection to the event loops
+type tlsRelays struct {
+	mu    sync.Mutex
+	conns map[net.Conn]net.Conn
+	wg    sync.WaitGroup
+	// closed refuses the connections accepted while the proxies were closing, aborted is set once wait closed
+	// the connections
+	closed  bool
+	aborted bool
+}
+
+// wait waits until the relays delivered what the event loops wrote before they stopped. The connections still
+// open when ctx is done are closed.
+func (r *tlsRelays) wait(ctx context.Context) error {
+	r.mu.Lock()
+	r.closed = true
+	r.mu.Unlock()
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		r.mu.Lock()
+		r.aborted = true
+		for conn, backend := range r.conns {
+			_ = conn.Close()
+			if backend != nil {
+				_ = backend.Close()
+			}
+		}
+		r.mu.Unlock()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// add registers a connection before it is relayed, it is closed when the relays are closing
+func (r *tlsRelays) add(conn net.Conn) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		_ = conn.Close()
+		return false
+	}
+	if r.conns == nil {
+		r.conns = make(map[net.Conn]net.Conn)
+	}
+	r.conns[conn] = nil
+	r.wg.Add(1)
+	return true
+}
+
+// relay copies conn, registered with add, to the event loops and back
+func (r *tlsRelays) relay(conn net.Conn, backendPath string) {
+	defer func() {
+		r.mu.Lock()
+		delete(r.conns, conn)
+		r.mu.Unlock()
+		r.wg.Done()
+	}()
+
+	defer conn.Close()
+	backend, err := net.Dial("unix", backendPath)
+	if err != nil {
+		logger.Error("Error occurred connecting to event loop", "error", err)
+		return
+	}
+	defer backend.Close()
+	r.mu.Lock()
+	aborted := r.aborted
+	r.conns[conn] = backend
+	r.mu.Unlock()
+	if aborted {
+		return
+	}
+	if _, err = backend.Write([]byte(proxyHeader(conn.RemoteAddr(), conn.LocalAddr()))); err != nil {
+		return
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(backend, conn)
+		// Let the event loop see the client is gone
+		_ = backend.(*net.UnixConn).CloseWrite()
+	}()
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(conn, backend)
+		_ = conn.Close()
+	}()
+	wg.Wait()
+}
+
+// maxProxyHeader is the longest PROXY protocol v1 header
+const maxProxyHeader = 107
+
+// proxyHeader returns the PROXY protocol v1 header of a connection from remote to local
+func proxyHeader(remote, local net.Addr) string {
+	src, _ := remote.(*net.TCPAddr)
+	dst, _ := local.(*net.TCPAddr)
+	if src == nil || dst == nil {
+		return "PROXY UNKNOWN\r\n"
+	}
+	family := "TCP4"
+	if src.IP.To4() == nil {
+		family = "TCP6"
+	}
+	return fmt.Sprintf("PROXY %s %s %s %d %d\r\n", family, src.IP, dst.IP, src.Port, dst.Port)
+}
+
+// parseProxyHeader returns the client and listener addresses of a PROXY protocol v1 header without its CRLF,
+// they are empty for an UNKNOWN header
+func parseProxyHeader(header string) (string, string, error) {
+	fields := strings.Fields(header)
+	if len(fields) >= 2 && fields[0] == "PROXY" && fields[1] == "UNKNOWN" {
+		return "", "", nil
+	}
+	if len(fields) != 6 || fields[0] != "PROXY" || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return "", "", fmt.Errorf("invalid PROXY header")
+	}
+	return net.JoinHostPort(fields[2], fields[4]), net.JoinHostPort(fields[3], fields[5]), nil
+}
+
+// readProxyHeader buffers data until the PROXY header of a relayed TLS connection is complete, then records the
+// addresses it carries and returns the data following it
+func (ts *Server) readProxyHeader(client *clientConn, data []byte) ([]byte, error) {
+	client.proxyHeader = append(client.proxyHeader, data...)
+	end := bytes.Index(client.proxyHeader, []byte("\r\n"))
+	if end < 0 {
+		if len(client.proxyHeader) > maxProxyHeader {
+			return nil, fmt.Errorf("invalid PROXY header")
+		}
+		return nil, nil
+	}
+	addr, localAddr, err := parseProxyHeader(string(client.proxyHeader[:end]))
+	if err != nil {
+		return nil, err
+	}
+	rest := client.proxyHeader[end+2:]
+	client.proxyHeader = nil
+	client.proxied = false
+	if addr != "" {
+		// CLIENT LIST reads the addresses from other event loops
+		ts.mu.Lock()
+		client.addr, client.localAddr = addr, localAddr
+		ts.mu.Unlock()
+	}
+	return rest, nil
+}
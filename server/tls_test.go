@@ -0,0 +1,112 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxyHeader(t *testing.T) {
+	header := proxyHeader(&net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 5000}, &net.TCPAddr{IP: net.IPv4(10, 0, 0, 2), Port: 7997})
+	require.Equal(t, "PROXY TCP4 10.0.0.1 10.0.0.2 5000 7997\r\n", header)
+	header = proxyHeader(&net.TCPAddr{IP: net.ParseIP("::1"), Port: 5000}, &net.TCPAddr{IP: net.ParseIP("::1"), Port: 7997})
+	require.Equal(t, "PROXY TCP6 ::1 ::1 5000 7997\r\n", header)
+
+	addr, localAddr, err := parseProxyHeader("PROXY TCP6 ::1 ::1 5000 7997")
+	require.NoError(t, err)
+	require.Equal(t, "[::1]:5000", addr)
+	require.Equal(t, "[::1]:7997", localAddr)
+	_, _, err = parseProxyHeader("PING")
+	require.Error(t, err)
+}
+
+func TestReadProxyHeader(t *testing.T) {
+	ts, conns := newTestClients(t, 1)
+	client := getClientConn(conns[0])
+	client.proxied = true
+
+	// The header can arrive in several reads, the commands following it are returned
+	rest, err := ts.readProxyHeader(client, []byte("PROXY TCP4 192.168.1.5 10.0."))
+	require.NoError(t, err)
+	require.Empty(t, rest)
+	require.True(t, client.proxied)
+	rest, err = ts.readProxyHeader(client, []byte("0.2 6000 7997\r\n*1\r\n$4\r\nPING\r\n"))
+	require.NoError(t, err)
+	require.Equal(t, "*1\r\n$4\r\nPING\r\n", string(rest))
+	require.False(t, client.proxied)
+	require.Equal(t, "192.168.1.5:6000", client.addr)
+	require.Equal(t, "10.0.0.2:7997", client.localAddr)
+
+	client.proxied = true
+	_, err = ts.readProxyHeader(client, make([]byte, maxProxyHeader+1))
+	require.Error(t, err)
+}
+
+func TestTLSRelaysWait(t *testing.T) {
+	backendPath := filepath.Join(t.TempDir(), "tls.sock")
+	backend, err := net.Listen("unix", backendPath)
+	require.NoError(t, err)
+	defer backend.Close()
+	// The event loop replies, then stops and closes the connection
+	go func() {
+		conn, errAccept := backend.Accept()
+		if errAccept != nil {
+			return
+		}
+		_, _ = bufio.NewReader(conn).ReadString('\n')
+		_, _ = conn.Write([]byte("+OK\r\n"))
+		_ = conn.Close()
+	}()
+
+	var relays tlsRelays
+	client, conn := net.Pipe()
+	require.True(t, relays.add(conn))
+	go relays.relay(conn, backendPath)
+	received := make(chan []byte, 1)
+	go func() {
+		data, _ := io.ReadAll(client)
+		received <- data
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, relays.wait(ctx))
+	require.Equal(t, "+OK\r\n", string(<-received))
+
+	// Connections accepted once the proxies are closing are refused
+	client, conn = net.Pipe()
+	require.False(t, relays.add(conn))
+	_, err = client.Read(make([]byte, 1))
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestTLSRelaysWaitTimeout(t *testing.T) {
+	backendPath := filepath.Join(t.TempDir(), "tls.sock")
+	backend, err := net.Listen("unix", backendPath)
+	require.NoError(t, err)
+	defer backend.Close()
+
+	var relays tlsRelays
+	client, conn := net.Pipe()
+	go func() {
+		_, _ = io.Copy(io.Discard, client)
+	}()
+	require.True(t, relays.add(conn))
+	go relays.relay(conn, backendPath)
+	require.Eventually(t, func() bool {
+		relays.mu.Lock()
+		defer relays.mu.Unlock()
+		return relays.conns[conn] != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The relayed connection is closed when the event loops did not close it in time
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, relays.wait(ctx), context.DeadlineExceeded)
+}
@@ -3,24 +3,33 @@ package server
 import (
 	"fmt"
 	"io"
-	"log"
 	"strings"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hashicorp/raft"
 	"github.com/panjf2000/gnet/v2"
+	"treds/acl"
 	"treds/commands"
+	"treds/logging"
 	"treds/store"
+	kvstore "treds/store/proto"
 )
 
 const (
 	NilStore = "error Nil Store"
 )
 
+var fsmLogger = logging.Named("fsm")
+
 type TredsFsm struct {
 	cmdRegistry commands.CommandRegistry
 	tredsStore  store.Store
 	conn        gnet.Conn
+	acl         *acl.ACL
+	metrics     *metrics
+	// monitor streams the commands applied to MONITOR clients, snapshots restored are not streamed
+	monitor func(index uint64, command string, args []string)
 }
 
 func (t *TredsFsm) Apply(log *raft.Log) interface{} {
@@ -29,6 +38,12 @@ func (t *TredsFsm) Apply(log *raft.Log) interface{} {
 	if err != nil {
 		return err
 	}
+	if t.monitor != nil {
+		t.monitor(log.Index, command, args)
+	}
+	if strings.ToUpper(command) == ACLCommandName {
+		return applyACL(t.acl, args)
+	}
 	commandReg, err := t.cmdRegistry.Retrieve(strings.ToUpper(command))
 	if err != nil {
 		return err
@@ -42,6 +57,9 @@ func (t *TredsFsm) Apply(log *raft.Log) interface{} {
 
 type snapshot struct {
 	storageSnapshot []byte
+	// started is when the snapshot was requested, it is complete once persisted
+	started time.Time
+	metrics *metrics
 }
 
 func (s *snapshot) Persist(sink raft.SnapshotSink) error {
@@ -51,42 +69,56 @@ func (s *snapshot) Persist(sink raft.SnapshotSink) error {
 	if err := sink.Close(); err != nil {
 		return fmt.Errorf("failed to close snapshot sink: %v", err)
 	}
+	s.metrics.observeSnapshot(s.started, len(s.storageSnapshot))
 	return nil
 }
 
 func (s *snapshot) Release() {}
 
 func (t *TredsFsm) Snapshot() (raft.FSMSnapshot, error) {
+	started := time.Now()
 	defer func(start time.Time) {
-		log.Println("snapshot created", "duration", time.Since(start).String())
-	}(time.Now())
-	fmt.Println("generating snapshot")
+		fsmLogger.Info("Snapshot created", "duration", time.Since(start).String())
+	}(started)
+	fsmLogger.Info("Generating snapshot")
 
 	storageSnapshot, err := t.tredsStore.Snapshot()
 	if err != nil {
 		return nil, err
 	}
+	// Concatenated protobuf messages are merged when decoded, so the ACL users are appended
+	// to the store snapshot without copying it. Stores ignore the users when restoring.
+	users, err := proto.Marshal(&kvstore.KeyValueStore{AclUsers: t.acl.List()})
+	if err != nil {
+		return nil, err
+	}
+	storageSnapshot = append(storageSnapshot, users...)
 	return &snapshot{
 		storageSnapshot: storageSnapshot,
+		started:         started,
+		metrics:         t.metrics,
 	}, nil
 }
 
 func (t *TredsFsm) Restore(old io.ReadCloser) error {
-	fmt.Println("restoring snapshot")
+	fsmLogger.Info("Restoring snapshot")
 	defer old.Close()
 	data, err := io.ReadAll(old)
 	if err != nil {
 		return err
 	}
-	ts := store.NewTredsStore()
-	err = ts.Restore(data)
-	t.tredsStore = ts
-	if err != nil {
+	var users kvstore.KeyValueStore
+	if err = proto.Unmarshal(data, &users); err != nil {
 		return err
 	}
-	return nil
+	// Snapshots taken before ACLs existed have no users, only the default user is kept then
+	if err = t.acl.Load(users.AclUsers); err != nil {
+		return err
+	}
+	// The store is restored in place, it is shared with the event loops serving reads
+	return t.tredsStore.Restore(data)
 }
 
-func NewTredsFsm(registry commands.CommandRegistry, store store.Store) *TredsFsm {
-	return &TredsFsm{cmdRegistry: registry, tredsStore: store}
+func NewTredsFsm(registry commands.CommandRegistry, store store.Store, users *acl.ACL) *TredsFsm {
+	return &TredsFsm{cmdRegistry: registry, tredsStore: store, acl: users}
 }
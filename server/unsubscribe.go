@@ -5,15 +5,25 @@ import (
 	"strings"
 
 	"github.com/panjf2000/gnet/v2"
-	"treds/resp"
+	"treds/acl"
+	"treds/commands"
 )
 
 const UnsubscribeCommandName = "UNSUBSCRIBE"
 
 func RegisterUnsubscribeCommand(r ServerCommandRegistry) {
 	r.Add(&ServerCommandRegistration{
-		Name:    UnsubscribeCommandName,
-		Execute: executeUnsubscribeCommand(),
+		Name:       UnsubscribeCommandName,
+		Execute:    executeUnsubscribeCommand(),
+		Unlocked:   true,
+		Categories: []string{acl.CategoryPubSub},
+		Spec: commands.Spec{
+			Summary: "Unsubscribes from channels",
+			Group:   commands.GroupPubSub,
+			Args: []commands.Arg{
+				{Name: "channel", Type: commands.ArgString, Multiple: true},
+			},
+		},
 	})
 }
 
@@ -42,7 +52,7 @@ func executeUnsubscribeCommand() ExecutionHook {
 		if forwarded {
 			_, errConn := c.Write([]byte(rspFwd))
 			if errConn != nil {
-				fmt.Println("Error occurred writing to connection", errConn)
+				logger.Error("Error occurred writing to connection", "error", errConn)
 			}
 			return gnet.None
 		}
@@ -50,27 +60,20 @@ func executeUnsubscribeCommand() ExecutionHook {
 		// make args unique
 		args = unique(args)
 
-		subscriptionData := ts.GetChannelSubscriptionData()
-
-		for _, channel := range args {
-			prevData, ok := subscriptionData.Get([]byte(channel))
-			if !ok {
-				prevData = make(map[string]struct{})
-			}
-			newData := prevData.(map[string]struct{})
-			delete(newData, c.RemoteAddr().String())
-			subscriptionData, _, _ = subscriptionData.Insert([]byte(channel), newData)
+		ts.mu.Lock()
+		remaining := ts.unsubscribe(connID(c), args, false)
+		ts.mu.Unlock()
+		if remaining == 0 {
+			getClientConn(c).pubsub.Store(false)
 		}
 
-		ts.SetChannelSubscriptionData(subscriptionData)
-
 		response := make([]interface{}, 0)
 		for indx, channel := range args {
 			response = append(response, strings.ToLower(UnsubscribeCommandName))
 			response = append(response, channel)
 			response = append(response, indx+1)
 		}
-		_, errConn := c.Write([]byte(resp.EncodeArray(response)))
+		_, errConn := c.Write([]byte(encodePubSub(c, response)))
 		if errConn != nil {
 			ts.RespondErr(c, errConn)
 		}
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/websocket"
+	"treds/resp"
+)
+
+// websocketFrame is a frame sent by a WebSocket client, op is subscribe, psubscribe, unsubscribe, punsubscribe or ping
+type websocketFrame struct {
+	Op       string   `json:"op"`
+	Channels []string `json:"channels"`
+}
+
+// websocketEvent is a frame sent to a WebSocket client, type is message, subscribe, psubscribe,
+// unsubscribe, punsubscribe, pong or error
+type websocketEvent struct {
+	Type    string `json:"type"`
+	Channel string `json:"channel,omitempty"`
+	Pattern string `json:"pattern,omitempty"`
+	Message string `json:"message,omitempty"`
+	Count   *int   `json:"count,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// websocketUpgrader keeps the default origin check, browsers on other origins are refused
+var websocketUpgrader = websocket.Upgrader{}
+
+// handleWebSocket serves pub/sub over a WebSocket. The client is a subscriber like a RESP connection
+// in subscribe mode, PUBLISH and PPUBLISH deliver to both the same way.
+func (ts *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	username, status, err := ts.httpUser(r)
+	if err != nil {
+		if status == http.StatusUnauthorized {
+			w.Header().Set("WWW-Authenticate", `Basic realm="treds"`)
+		}
+		writeHTTPError(w, status, err)
+		return
+	}
+	conn, err := websocketUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader already replied with an error
+		return
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	conn.SetReadLimit(resp.MaxBulkLength)
+
+	watch := ts.addWatcher(nil, false)
+	defer ts.closeWatcher(watch)
+
+	replies := make(chan []websocketEvent)
+	done := make(chan struct{})
+	writerDone := make(chan struct{})
+	// gorilla/websocket supports one concurrent writer, so every frame is written here
+	go func() {
+		defer close(writerDone)
+		for {
+			var events []websocketEvent
+			select {
+			case <-done:
+				return
+			case <-watch.dropped:
+				_ = conn.WriteJSON(websocketEvent{Type: "error", Error: watch.err.Error()})
+				_ = conn.Close()
+				return
+			case msg := <-watch.messages:
+				events = []websocketEvent{{Type: "message", Channel: msg.Channel, Pattern: msg.Pattern, Message: msg.Message}}
+			case events = <-replies:
+			}
+			for _, event := range events {
+				if errWrite := conn.WriteJSON(event); errWrite != nil {
+					logger.Error("Error occurred writing to websocket", "error", errWrite)
+					_ = conn.Close()
+					return
+				}
+			}
+		}
+	}()
+	defer func() {
+		close(done)
+		<-writerDone
+	}()
+
+	for {
+		_, data, errRead := conn.ReadMessage()
+		if errRead != nil {
+			return
+		}
+		var frame websocketFrame
+		var events []websocketEvent
+		if errJSON := json.Unmarshal(data, &frame); errJSON != nil {
+			events = []websocketEvent{{Type: "error", Error: fmt.Sprintf("invalid JSON frame: %v", errJSON)}}
+		} else {
+			events = ts.websocketOp(username, watch, frame)
+		}
+		select {
+		case replies <- events:
+		case <-writerDone:
+			return
+		}
+	}
+}
+
+// websocketOp applies a frame of a WebSocket client and returns the frames to send back
+func (ts *Server) websocketOp(username string, watch *watcher, frame websocketFrame) []websocketEvent {
+	op := strings.ToLower(frame.Op)
+	var command string
+	switch op {
+	case "ping":
+		return []websocketEvent{{Type: "pong"}}
+	case "subscribe":
+		command = SubscribeCommandName
+	case "psubscribe":
+		command = PSubscribeCommandName
+	case "unsubscribe":
+		command = UnsubscribeCommandName
+	case "punsubscribe":
+		command = PUnsubscribeCommandName
+	default:
+		return []websocketEvent{{Type: "error", Error: fmt.Sprintf("unknown op '%s'", frame.Op)}}
+	}
+	channels := unique(frame.Channels)
+	if len(channels) == 0 {
+		return []websocketEvent{{Type: "error", Error: "channels are required"}}
+	}
+	if err := ts.authorizeUser(username, command, channels); err != nil {
+		return []websocketEvent{{Type: "error", Error: err.Error()}}
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if _, ok := ts.watchers[watch.id]; !ok {
+		// The subscriber was dropped, the writer reports it
+		return nil
+	}
+	prefix := op == "psubscribe" || op == "punsubscribe"
+	if op == "subscribe" || op == "psubscribe" {
+		ts.subscribe(watch.id, channels, prefix)
+	} else {
+		ts.unsubscribe(watch.id, channels, prefix)
+	}
+	count := len(ts.connectionSubscription[watch.id])
+	events := make([]websocketEvent, 0, len(channels))
+	for _, channel := range channels {
+		events = append(events, websocketEvent{Type: op, Channel: channel, Count: &count})
+	}
+	return events
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebSocketPubSub(t *testing.T) {
+	ts, _ := newTestServer(t)
+	httpServer := httptest.NewServer(ts.HTTPHandler())
+	defer httpServer.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(httpServer.URL, "http")+"/v1/pubsub", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	var event websocketEvent
+	require.NoError(t, conn.WriteJSON(websocketFrame{Op: "subscribe", Channels: []string{"news", "news"}}))
+	require.NoError(t, conn.ReadJSON(&event))
+	require.Equal(t, "subscribe", event.Type)
+	require.Equal(t, "news", event.Channel)
+	require.Equal(t, 1, *event.Count)
+
+	ts.mu.Lock()
+	var id uint64
+	for watcherID := range ts.watchers {
+		id = watcherID
+	}
+	require.True(t, ts.deliver(id, Message, "news", "news", "hello"))
+	ts.mu.Unlock()
+
+	event = websocketEvent{}
+	require.NoError(t, conn.ReadJSON(&event))
+	require.Equal(t, websocketEvent{Type: "message", Channel: "news", Message: "hello"}, event)
+
+	require.NoError(t, conn.WriteJSON(websocketFrame{Op: "publish", Channels: []string{"news"}}))
+	event = websocketEvent{}
+	require.NoError(t, conn.ReadJSON(&event))
+	require.Equal(t, "error", event.Type)
+
+	require.NoError(t, conn.WriteJSON(websocketFrame{Op: "unsubscribe", Channels: []string{"news"}}))
+	event = websocketEvent{}
+	require.NoError(t, conn.ReadJSON(&event))
+	require.Equal(t, "unsubscribe", event.Type)
+	require.Equal(t, 0, *event.Count)
+
+	require.NoError(t, conn.Close())
+	require.Eventually(t, func() bool {
+		ts.mu.Lock()
+		defer ts.mu.Unlock()
+		return len(ts.watchers) == 0 && len(ts.connectionSubscription) == 0
+	}, time.Second, 10*time.Millisecond)
+}
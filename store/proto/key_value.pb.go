@@ -1,130 +1,200 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
-// source: key_value.proto
+// versions:
+// 	protoc-gen-go v1.35.2
+// 	protoc        v5.29.3
+// source: store/proto/key_value.proto
 
 package kvstore
 
 import (
-	fmt "fmt"
-	proto "github.com/golang/protobuf/proto"
-	math "math"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
 )
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ = proto.Marshal
-var _ = fmt.Errorf
-var _ = math.Inf
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the proto package it is being compiled against.
-// A compilation error at this line likely means your copy of the
-// proto package needs to be updated.
-const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
 
 // A collection of key-value pairs
 type KeyValueStore struct {
-	Pairs                []*KeyValue `protobuf:"bytes,1,rep,name=pairs,proto3" json:"pairs,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
-	XXX_unrecognized     []byte      `json:"-"`
-	XXX_sizecache        int32       `json:"-"`
-}
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func (m *KeyValueStore) Reset()         { *m = KeyValueStore{} }
-func (m *KeyValueStore) String() string { return proto.CompactTextString(m) }
-func (*KeyValueStore) ProtoMessage()    {}
-func (*KeyValueStore) Descriptor() ([]byte, []int) {
-	return fileDescriptor_40f3a6d8264e424e, []int{0}
+	Pairs []*KeyValue `protobuf:"bytes,1,rep,name=pairs,proto3" json:"pairs,omitempty"`
+	// ACL users in the ACL SETUSER rule format, they are replicated with the snapshot
+	AclUsers []string `protobuf:"bytes,2,rep,name=acl_users,json=aclUsers,proto3" json:"acl_users,omitempty"`
 }
 
-func (m *KeyValueStore) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_KeyValueStore.Unmarshal(m, b)
+func (x *KeyValueStore) Reset() {
+	*x = KeyValueStore{}
+	mi := &file_store_proto_key_value_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
-func (m *KeyValueStore) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_KeyValueStore.Marshal(b, m, deterministic)
-}
-func (m *KeyValueStore) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_KeyValueStore.Merge(m, src)
+
+func (x *KeyValueStore) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *KeyValueStore) XXX_Size() int {
-	return xxx_messageInfo_KeyValueStore.Size(m)
+
+func (*KeyValueStore) ProtoMessage() {}
+
+func (x *KeyValueStore) ProtoReflect() protoreflect.Message {
+	mi := &file_store_proto_key_value_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-func (m *KeyValueStore) XXX_DiscardUnknown() {
-	xxx_messageInfo_KeyValueStore.DiscardUnknown(m)
+
+// Deprecated: Use KeyValueStore.ProtoReflect.Descriptor instead.
+func (*KeyValueStore) Descriptor() ([]byte, []int) {
+	return file_store_proto_key_value_proto_rawDescGZIP(), []int{0}
 }
 
-var xxx_messageInfo_KeyValueStore proto.InternalMessageInfo
+func (x *KeyValueStore) GetPairs() []*KeyValue {
+	if x != nil {
+		return x.Pairs
+	}
+	return nil
+}
 
-func (m *KeyValueStore) GetPairs() []*KeyValue {
-	if m != nil {
-		return m.Pairs
+func (x *KeyValueStore) GetAclUsers() []string {
+	if x != nil {
+		return x.AclUsers
 	}
 	return nil
 }
 
-// A single key-value pair
+// A single key-value pair, keys and values are binary safe
 type KeyValue struct {
-	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
-	Value                string   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func (m *KeyValue) Reset()         { *m = KeyValue{} }
-func (m *KeyValue) String() string { return proto.CompactTextString(m) }
-func (*KeyValue) ProtoMessage()    {}
-func (*KeyValue) Descriptor() ([]byte, []int) {
-	return fileDescriptor_40f3a6d8264e424e, []int{1}
+	Key   []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
 }
 
-func (m *KeyValue) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_KeyValue.Unmarshal(m, b)
-}
-func (m *KeyValue) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_KeyValue.Marshal(b, m, deterministic)
+func (x *KeyValue) Reset() {
+	*x = KeyValue{}
+	mi := &file_store_proto_key_value_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
-func (m *KeyValue) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_KeyValue.Merge(m, src)
-}
-func (m *KeyValue) XXX_Size() int {
-	return xxx_messageInfo_KeyValue.Size(m)
+
+func (x *KeyValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *KeyValue) XXX_DiscardUnknown() {
-	xxx_messageInfo_KeyValue.DiscardUnknown(m)
+
+func (*KeyValue) ProtoMessage() {}
+
+func (x *KeyValue) ProtoReflect() protoreflect.Message {
+	mi := &file_store_proto_key_value_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_KeyValue proto.InternalMessageInfo
+// Deprecated: Use KeyValue.ProtoReflect.Descriptor instead.
+func (*KeyValue) Descriptor() ([]byte, []int) {
+	return file_store_proto_key_value_proto_rawDescGZIP(), []int{1}
+}
 
-func (m *KeyValue) GetKey() string {
-	if m != nil {
-		return m.Key
+func (x *KeyValue) GetKey() []byte {
+	if x != nil {
+		return x.Key
 	}
-	return ""
+	return nil
 }
 
-func (m *KeyValue) GetValue() string {
-	if m != nil {
-		return m.Value
+func (x *KeyValue) GetValue() []byte {
+	if x != nil {
+		return x.Value
 	}
-	return ""
+	return nil
 }
 
-func init() {
-	proto.RegisterType((*KeyValueStore)(nil), "kvstore.KeyValueStore")
-	proto.RegisterType((*KeyValue)(nil), "kvstore.KeyValue")
+var File_store_proto_key_value_proto protoreflect.FileDescriptor
+
+var file_store_proto_key_value_proto_rawDesc = []byte{
+	0x0a, 0x1b, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6b, 0x65,
+	0x79, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x07, 0x6b,
+	0x76, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x22, 0x55, 0x0a, 0x0d, 0x4b, 0x65, 0x79, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x27, 0x0a, 0x05, 0x70, 0x61, 0x69, 0x72, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6b, 0x76, 0x73, 0x74, 0x6f, 0x72, 0x65,
+	0x2e, 0x4b, 0x65, 0x79, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05, 0x70, 0x61, 0x69, 0x72, 0x73,
+	0x12, 0x1b, 0x0a, 0x09, 0x61, 0x63, 0x6c, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x08, 0x61, 0x63, 0x6c, 0x55, 0x73, 0x65, 0x72, 0x73, 0x22, 0x32, 0x0a,
+	0x08, 0x4b, 0x65, 0x79, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x42, 0x1b, 0x5a, 0x19, 0x74, 0x72, 0x65, 0x64, 0x73, 0x2f, 0x73, 0x74, 0x6f, 0x72, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x3b, 0x6b, 0x76, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_store_proto_key_value_proto_rawDescOnce sync.Once
+	file_store_proto_key_value_proto_rawDescData = file_store_proto_key_value_proto_rawDesc
+)
+
+func file_store_proto_key_value_proto_rawDescGZIP() []byte {
+	file_store_proto_key_value_proto_rawDescOnce.Do(func() {
+		file_store_proto_key_value_proto_rawDescData = protoimpl.X.CompressGZIP(file_store_proto_key_value_proto_rawDescData)
+	})
+	return file_store_proto_key_value_proto_rawDescData
 }
 
-func init() {
-	proto.RegisterFile("key_value.proto", fileDescriptor_40f3a6d8264e424e)
+var file_store_proto_key_value_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_store_proto_key_value_proto_goTypes = []any{
+	(*KeyValueStore)(nil), // 0: kvstore.KeyValueStore
+	(*KeyValue)(nil),      // 1: kvstore.KeyValue
+}
+var file_store_proto_key_value_proto_depIdxs = []int32{
+	1, // 0: kvstore.KeyValueStore.pairs:type_name -> kvstore.KeyValue
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
-var fileDescriptor_40f3a6d8264e424e = []byte{
-	// 127 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0xcf, 0x4e, 0xad, 0x8c,
-	0x2f, 0x4b, 0xcc, 0x29, 0x4d, 0xd5, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0xcf, 0x2e, 0x2b,
-	0x2e, 0xc9, 0x2f, 0x4a, 0x55, 0xb2, 0xe0, 0xe2, 0xf5, 0x4e, 0xad, 0x0c, 0x03, 0x49, 0x05, 0x83,
-	0x04, 0x84, 0xd4, 0xb9, 0x58, 0x0b, 0x12, 0x33, 0x8b, 0x8a, 0x25, 0x18, 0x15, 0x98, 0x35, 0xb8,
-	0x8d, 0x04, 0xf5, 0xa0, 0x2a, 0xf5, 0x60, 0xca, 0x82, 0x20, 0xf2, 0x4a, 0x46, 0x5c, 0x1c, 0x30,
-	0x21, 0x21, 0x01, 0x2e, 0xe6, 0xec, 0xd4, 0x4a, 0x09, 0x46, 0x05, 0x46, 0x0d, 0xce, 0x20, 0x10,
-	0x53, 0x48, 0x84, 0x8b, 0x15, 0x6c, 0x9f, 0x04, 0x13, 0x58, 0x0c, 0xc2, 0x49, 0x62, 0x03, 0xdb,
-	0x6e, 0x0c, 0x08, 0x00, 0x00, 0xff, 0xff, 0x4d, 0x68, 0x15, 0x3f, 0x90, 0x00, 0x00, 0x00,
+func init() { file_store_proto_key_value_proto_init() }
+func file_store_proto_key_value_proto_init() {
+	if File_store_proto_key_value_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_store_proto_key_value_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_store_proto_key_value_proto_goTypes,
+		DependencyIndexes: file_store_proto_key_value_proto_depIdxs,
+		MessageInfos:      file_store_proto_key_value_proto_msgTypes,
+	}.Build()
+	File_store_proto_key_value_proto = out.File
+	file_store_proto_key_value_proto_rawDesc = nil
+	file_store_proto_key_value_proto_goTypes = nil
+	file_store_proto_key_value_proto_depIdxs = nil
 }
@@ -0,0 +1,917 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	kvstore "treds/store/proto"
+)
+
+// ShardBy decides how keys are partitioned between the shards of a ShardedStore
+type ShardBy int
+
+const (
+	// ShardByHash places every key on the shard picked by the hash of the key
+	ShardByHash ShardBy = iota
+	// ShardByPrefix places every key on the shard picked by the hash of its top level prefix,
+	// so all keys sharing that prefix live on the same shard
+	ShardByPrefix
+)
+
+// DefaultShardDelimiter separates the top level prefix from the rest of the key
+const DefaultShardDelimiter = ":"
+
+// ParseShardBy parses the name of a partitioning mode, either "hash" or "prefix"
+func ParseShardBy(name string) (ShardBy, error) {
+	switch strings.ToLower(name) {
+	case "hash":
+		return ShardByHash, nil
+	case "prefix":
+		return ShardByPrefix, nil
+	}
+	return 0, fmt.Errorf("unknown shard mode: %s", name)
+}
+
+type shard struct {
+	mu    sync.Mutex
+	store *TredsStore
+}
+
+// ShardedStore partitions the keyspace over several TredsStore shards, each guarded by its own lock,
+// so commands on different shards can run in parallel from different event loops.
+// Commands spanning multiple keys fan out to every shard involved and merge the results,
+// scans are merged in key order so the ordering guarantees of a single store are kept.
+type ShardedStore struct {
+	shards    []*shard
+	shardBy   ShardBy
+	delimiter string
+}
+
+func NewShardedStore(shards int, shardBy ShardBy, delimiter string) *ShardedStore {
+	if shards < 1 {
+		shards = 1
+	}
+	if delimiter == "" {
+		delimiter = DefaultShardDelimiter
+	}
+	ss := &ShardedStore{
+		shards:    make([]*shard, shards),
+		shardBy:   shardBy,
+		delimiter: delimiter,
+	}
+	for i := range ss.shards {
+		ss.shards[i] = &shard{store: NewTredsStore()}
+	}
+	return ss
+}
+
+// Shards returns the number of shards
+func (ss *ShardedStore) Shards() int {
+	return len(ss.shards)
+}
+
+func (ss *ShardedStore) shardIndex(key string) int {
+	if len(ss.shards) == 1 {
+		return 0
+	}
+	if ss.shardBy == ShardByPrefix {
+		if index := strings.Index(key, ss.delimiter); index >= 0 {
+			key = key[:index]
+		}
+	}
+	h, _ := hash(key)
+	return int(h % uint32(len(ss.shards)))
+}
+
+// prefixShard returns the only shard which can hold keys starting with prefix, if there is one
+func (ss *ShardedStore) prefixShard(prefix string) (int, bool) {
+	if len(ss.shards) == 1 {
+		return 0, true
+	}
+	if ss.shardBy == ShardByPrefix && strings.Contains(prefix, ss.delimiter) {
+		return ss.shardIndex(prefix), true
+	}
+	return 0, false
+}
+
+// lock locks and returns the shard owning key
+func (ss *ShardedStore) lock(key string) *TredsStore {
+	sh := ss.shards[ss.shardIndex(key)]
+	sh.mu.Lock()
+	return sh.store
+}
+
+func (ss *ShardedStore) unlock(key string) {
+	ss.shards[ss.shardIndex(key)].mu.Unlock()
+}
+
+// lockKeys locks every shard owning one of keys, always in shard order to avoid deadlocks
+func (ss *ShardedStore) lockKeys(keys []string) func() {
+	used := make([]bool, len(ss.shards))
+	for _, key := range keys {
+		used[ss.shardIndex(key)] = true
+	}
+	for i := range ss.shards {
+		if used[i] {
+			ss.shards[i].mu.Lock()
+		}
+	}
+	return func() {
+		for i := range ss.shards {
+			if used[i] {
+				ss.shards[i].mu.Unlock()
+			}
+		}
+	}
+}
+
+func (ss *ShardedStore) lockAll() func() {
+	for _, sh := range ss.shards {
+		sh.mu.Lock()
+	}
+	return func() {
+		for _, sh := range ss.shards {
+			sh.mu.Unlock()
+		}
+	}
+}
+
+func (ss *ShardedStore) Get(k string) (string, bool, error) {
+	s := ss.lock(k)
+	defer ss.unlock(k)
+	return s.Get(k)
+}
+
+func (ss *ShardedStore) MGet(args []string) ([]string, []bool, error) {
+	unlock := ss.lockKeys(args)
+	defer unlock()
+	response := make([]string, 0, len(args))
+	found := make([]bool, 0, len(args))
+	for _, key := range args {
+		res, ok, err := ss.shards[ss.shardIndex(key)].store.Get(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		response = append(response, res)
+		found = append(found, ok)
+	}
+	return response, found, nil
+}
+
+func (ss *ShardedStore) MSet(kvs []string) error {
+	if len(kvs)%2 != 0 {
+		return fmt.Errorf("wrong number of arguments for key value pairs")
+	}
+	perShard := make(map[int][]string)
+	keys := make([]string, 0, len(kvs)/2)
+	for itr := 0; itr < len(kvs); itr += 2 {
+		index := ss.shardIndex(kvs[itr])
+		perShard[index] = append(perShard[index], kvs[itr], kvs[itr+1])
+		keys = append(keys, kvs[itr])
+	}
+	unlock := ss.lockKeys(keys)
+	defer unlock()
+	// Validate on every shard first so that either all or none of the keys are set
+	for index, pairs := range perShard {
+		if err := ss.shards[index].store.validateMSet(pairs); err != nil {
+			return err
+		}
+	}
+	for index, pairs := range perShard {
+		if err := ss.shards[index].store.MSet(pairs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ss *ShardedStore) Set(k string, v string) error {
+	s := ss.lock(k)
+	defer ss.unlock(k)
+	return s.Set(k, v)
+}
+
+func (ss *ShardedStore) IncrBy(k string, delta int64) (int64, error) {
+	s := ss.lock(k)
+	defer ss.unlock(k)
+	return s.IncrBy(k, delta)
+}
+
+func (ss *ShardedStore) IncrByFloat(k string, delta float64) (float64, error) {
+	s := ss.lock(k)
+	defer ss.unlock(k)
+	return s.IncrByFloat(k, delta)
+}
+
+func (ss *ShardedStore) Delete(k string) error {
+	s := ss.lock(k)
+	defer ss.unlock(k)
+	return s.Delete(k)
+}
+
+func (ss *ShardedStore) PrefixScan(cursor, prefix, count string) ([]string, error) {
+	return ss.prefixScan(cursor, prefix, count, true)
+}
+
+func (ss *ShardedStore) PrefixScanKeys(cursor, prefix, count string) ([]string, error) {
+	return ss.prefixScan(cursor, prefix, count, false)
+}
+
+func (ss *ShardedStore) prefixScan(cursor, prefix, count string, withValue bool) ([]string, error) {
+	if index, ok := ss.prefixShard(prefix); ok {
+		sh := ss.shards[index]
+		sh.mu.Lock()
+		defer sh.mu.Unlock()
+		if withValue {
+			return sh.store.PrefixScan(cursor, prefix, count)
+		}
+		return sh.store.PrefixScanKeys(cursor, prefix, count)
+	}
+	countInt, err := strconv.Atoi(count)
+	if err != nil {
+		return nil, err
+	}
+	unlock := ss.lockAll()
+	defer unlock()
+	sources := make([]scanSource, len(ss.shards))
+	for i, sh := range ss.shards {
+		sources[i] = prefixSource(sh.store, prefix)
+	}
+	return mergeScan(sources, cursor, countInt, withValue)
+}
+
+func (ss *ShardedStore) DeletePrefix(prefix string) (int, error) {
+	if index, ok := ss.prefixShard(prefix); ok {
+		sh := ss.shards[index]
+		sh.mu.Lock()
+		defer sh.mu.Unlock()
+		return sh.store.DeletePrefix(prefix)
+	}
+	unlock := ss.lockAll()
+	defer unlock()
+	deleted := 0
+	for _, sh := range ss.shards {
+		numDel, err := sh.store.DeletePrefix(prefix)
+		if err != nil {
+			return deleted, err
+		}
+		deleted += numDel
+	}
+	return deleted, nil
+}
+
+func (ss *ShardedStore) Keys(cursor, regex string, count int) ([]string, error) {
+	return ss.patternScan(cursor, regex, count, false)
+}
+
+func (ss *ShardedStore) KVS(cursor, regex string, count int) ([]string, error) {
+	return ss.patternScan(cursor, regex, count, true)
+}
+
+func (ss *ShardedStore) patternScan(cursor, regex string, count int, withValue bool) ([]string, error) {
+	if len(ss.shards) == 1 {
+		sh := ss.shards[0]
+		sh.mu.Lock()
+		defer sh.mu.Unlock()
+		if withValue {
+			return sh.store.KVS(cursor, regex, count)
+		}
+		return sh.store.Keys(cursor, regex, count)
+	}
+	rx, err := regexp.Compile(regex)
+	if err != nil {
+		return nil, err
+	}
+	unlock := ss.lockAll()
+	defer unlock()
+	sources := make([]scanSource, len(ss.shards))
+	for i, sh := range ss.shards {
+		sources[i] = patternSource(sh.store, rx)
+	}
+	return mergeScan(sources, cursor, count, withValue)
+}
+
+func (ss *ShardedStore) KeysH(cursor, regex string, count int) ([]string, error) {
+	return ss.typeScan(cursor, regex, count, func(ts *TredsStore) []string {
+		return mapKeys(ts.hashes)
+	}, (*TredsStore).KeysH)
+}
+
+func (ss *ShardedStore) KeysL(cursor, regex string, count int) ([]string, error) {
+	return ss.typeScan(cursor, regex, count, func(ts *TredsStore) []string {
+		return mapKeys(ts.lists)
+	}, (*TredsStore).KeysL)
+}
+
+func (ss *ShardedStore) KeysS(cursor, regex string, count int) ([]string, error) {
+	return ss.typeScan(cursor, regex, count, func(ts *TredsStore) []string {
+		return mapKeys(ts.sets)
+	}, (*TredsStore).KeysS)
+}
+
+func (ss *ShardedStore) KeysZ(cursor, regex string, count int) ([]string, error) {
+	return ss.typeScan(cursor, regex, count, func(ts *TredsStore) []string {
+		return mapKeys(ts.sortedMapsScore)
+	}, (*TredsStore).KeysZ)
+}
+
+// typeScan merges the keys of one store type of every shard, single is used as is for a single shard
+func (ss *ShardedStore) typeScan(cursor, regex string, count int, keys func(*TredsStore) []string,
+	single func(*TredsStore, string, string, int) ([]string, error)) ([]string, error) {
+	if len(ss.shards) == 1 {
+		sh := ss.shards[0]
+		sh.mu.Lock()
+		defer sh.mu.Unlock()
+		return single(sh.store, cursor, regex, count)
+	}
+	rx, err := regexp.Compile(regex)
+	if err != nil {
+		return nil, err
+	}
+	unlock := ss.lockAll()
+	defer unlock()
+	sources := make([]scanSource, len(ss.shards))
+	for i, sh := range ss.shards {
+		sources[i] = keysSource(sh.store, keys(sh.store), rx)
+	}
+	return mergeScan(sources, cursor, count, false)
+}
+
+func (ss *ShardedStore) Size() (int, error) {
+	unlock := ss.lockAll()
+	defer unlock()
+	total := 0
+	for _, sh := range ss.shards {
+		size, err := sh.store.Size()
+		if err != nil {
+			return 0, err
+		}
+		total += size
+	}
+	return total, nil
+}
+
+func (ss *ShardedStore) ZAdd(args []string) error {
+	s := ss.lock(args[0])
+	defer ss.unlock(args[0])
+	return s.ZAdd(args)
+}
+
+func (ss *ShardedStore) ZIncrBy(key string, delta float64, member string) (float64, error) {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.ZIncrBy(key, delta, member)
+}
+
+func (ss *ShardedStore) ZRem(args []string) error {
+	s := ss.lock(args[0])
+	defer ss.unlock(args[0])
+	return s.ZRem(args)
+}
+
+func (ss *ShardedStore) ZCard(key string) (int, error) {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.ZCard(key)
+}
+
+func (ss *ShardedStore) ZScore(args []string) (string, error) {
+	s := ss.lock(args[0])
+	defer ss.unlock(args[0])
+	return s.ZScore(args)
+}
+
+func (ss *ShardedStore) ZRange(key string, startIndex int, endIndex int, withScore bool) ([]string, error) {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.ZRange(key, startIndex, endIndex, withScore)
+}
+
+func (ss *ShardedStore) ZRangeByLexKVS(key, cursor, min, max, count string, withScore bool) ([]string, error) {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.ZRangeByLexKVS(key, cursor, min, max, count, withScore)
+}
+
+func (ss *ShardedStore) ZRangeByLexKeys(key, cursor, min, max, count string, withScore bool) ([]string, error) {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.ZRangeByLexKeys(key, cursor, min, max, count, withScore)
+}
+
+func (ss *ShardedStore) ZRangeByScoreKeys(key, min, max, offset, count string, withScore bool) ([]string, error) {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.ZRangeByScoreKeys(key, min, max, offset, count, withScore)
+}
+
+func (ss *ShardedStore) ZRangeByScoreKVS(key, min, max, offset, count string, withScore bool) ([]string, error) {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.ZRangeByScoreKVS(key, min, max, offset, count, withScore)
+}
+
+func (ss *ShardedStore) ZRevRangeByLexKVS(key, cursor, min, max, count string, withScore bool) ([]string, error) {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.ZRevRangeByLexKVS(key, cursor, min, max, count, withScore)
+}
+
+func (ss *ShardedStore) ZRevRangeByLexKeys(key, cursor, min, max, count string, withScore bool) ([]string, error) {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.ZRevRangeByLexKeys(key, cursor, min, max, count, withScore)
+}
+
+func (ss *ShardedStore) ZRevRangeByScoreKeys(key, min, max, offset, count string, withScore bool) ([]string, error) {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.ZRevRangeByScoreKeys(key, min, max, offset, count, withScore)
+}
+
+func (ss *ShardedStore) ZRevRangeByScoreKVS(key, min, max, offset, count string, withScore bool) ([]string, error) {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.ZRevRangeByScoreKVS(key, min, max, offset, count, withScore)
+}
+
+func (ss *ShardedStore) FlushAll() error {
+	unlock := ss.lockAll()
+	defer unlock()
+	for _, sh := range ss.shards {
+		if err := sh.store.FlushAll(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ss *ShardedStore) LPush(args []string) error {
+	s := ss.lock(args[0])
+	defer ss.unlock(args[0])
+	return s.LPush(args)
+}
+
+func (ss *ShardedStore) RPush(args []string) error {
+	s := ss.lock(args[0])
+	defer ss.unlock(args[0])
+	return s.RPush(args)
+}
+
+func (ss *ShardedStore) LPop(key string, count int) ([]string, error) {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.LPop(key, count)
+}
+
+func (ss *ShardedStore) RPop(key string, count int) ([]string, error) {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.RPop(key, count)
+}
+
+func (ss *ShardedStore) LRem(key string, index int) error {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.LRem(key, index)
+}
+
+func (ss *ShardedStore) LSet(key string, index int, element string) error {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.LSet(key, index, element)
+}
+
+func (ss *ShardedStore) LRange(key string, start, stop int) ([]string, error) {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.LRange(key, start, stop)
+}
+
+func (ss *ShardedStore) LLen(key string) (int, error) {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.LLen(key)
+}
+
+func (ss *ShardedStore) LIndex(args []string) (string, error) {
+	s := ss.lock(args[0])
+	defer ss.unlock(args[0])
+	return s.LIndex(args)
+}
+
+func (ss *ShardedStore) SAdd(key string, members []string) error {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.SAdd(key, members)
+}
+
+func (ss *ShardedStore) SRem(key string, members []string) error {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.SRem(key, members)
+}
+
+func (ss *ShardedStore) SMembers(key string) ([]string, error) {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.SMembers(key)
+}
+
+func (ss *ShardedStore) SIsMember(key string, member string) (bool, error) {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.SIsMember(key, member)
+}
+
+func (ss *ShardedStore) SCard(key string) (int, error) {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.SCard(key)
+}
+
+func (ss *ShardedStore) SUnion(keys []string) ([]string, error) {
+	return ss.setOperation(keys, (*TredsStore).SUnion)
+}
+
+func (ss *ShardedStore) SInter(keys []string) ([]string, error) {
+	return ss.setOperation(keys, (*TredsStore).SInter)
+}
+
+func (ss *ShardedStore) SDiff(keys []string) ([]string, error) {
+	return ss.setOperation(keys, (*TredsStore).SDiff)
+}
+
+// setOperation gathers the sets of keys from their shards into a single store, runs operation
+// on it and returns the members sorted
+func (ss *ShardedStore) setOperation(keys []string, operation func(*TredsStore, []string) ([]string, error)) ([]string, error) {
+	unlock := ss.lockKeys(keys)
+	defer unlock()
+	gathered := NewTredsStore()
+	for _, key := range keys {
+		s := ss.shards[ss.shardIndex(key)].store
+		kd := s.getKeyDetails(key)
+		if kd != -1 && kd != SetStore {
+			return nil, fmt.Errorf("not set store")
+		}
+		if storedSet, ok := s.sets[key]; ok {
+			gathered.sets[key] = storedSet
+		}
+	}
+	res, err := operation(gathered, keys)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(res)
+	return res, nil
+}
+
+func (ss *ShardedStore) HSet(key string, args []string) error {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.HSet(key, args)
+}
+
+func (ss *ShardedStore) HIncrBy(key string, field string, delta int64) (int64, error) {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.HIncrBy(key, field, delta)
+}
+
+func (ss *ShardedStore) HIncrByFloat(key string, field string, delta float64) (float64, error) {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.HIncrByFloat(key, field, delta)
+}
+
+func (ss *ShardedStore) HGet(key string, field string) (string, bool, error) {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.HGet(key, field)
+}
+
+func (ss *ShardedStore) HGetAll(key string) ([]string, error) {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.HGetAll(key)
+}
+
+func (ss *ShardedStore) HLen(key string) (int, error) {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.HLen(key)
+}
+
+func (ss *ShardedStore) HDel(key string, fields []string) error {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.HDel(key, fields)
+}
+
+func (ss *ShardedStore) HExists(key string, field string) (bool, error) {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.HExists(key, field)
+}
+
+func (ss *ShardedStore) HKeys(key string) ([]string, error) {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.HKeys(key)
+}
+
+func (ss *ShardedStore) HVals(key string) ([]string, error) {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.HVals(key)
+}
+
+func (ss *ShardedStore) CleanUpExpiredKeys() {
+	for _, sh := range ss.shards {
+		sh.mu.Lock()
+		sh.store.CleanUpExpiredKeys()
+		sh.mu.Unlock()
+	}
+}
+
+func (ss *ShardedStore) Keyspace() KeyspaceStats {
+	unlock := ss.lockAll()
+	defer unlock()
+	var stats KeyspaceStats
+	for _, sh := range ss.shards {
+		stats = stats.Add(sh.store.Keyspace())
+	}
+	return stats
+}
+
+func (ss *ShardedStore) Expire(key string, at time.Time) error {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.Expire(key, at)
+}
+
+func (ss *ShardedStore) Ttl(key string) int {
+	s := ss.lock(key)
+	defer ss.unlock(key)
+	return s.Ttl(key)
+}
+
+func (ss *ShardedStore) LongestPrefix(prefix string) ([]string, error) {
+	unlock := ss.lockAll()
+	defer unlock()
+	var longest []string
+	for _, sh := range ss.shards {
+		res, err := sh.store.LongestPrefix(prefix)
+		if err != nil {
+			return nil, err
+		}
+		if res != nil && (longest == nil || len(res[0]) > len(longest[0])) {
+			longest = res
+		}
+	}
+	return longest, nil
+}
+
+// Snapshot merges the snapshots of all shards into one, ordered by key.
+// The format is the same as the one of a single TredsStore so snapshots can be restored with any number of shards.
+func (ss *ShardedStore) Snapshot() ([]byte, error) {
+	unlock := ss.lockAll()
+	defer unlock()
+	merged := &kvstore.KeyValueStore{
+		Pairs: make([]*kvstore.KeyValue, 0),
+	}
+	for _, sh := range ss.shards {
+		data, err := sh.store.Snapshot()
+		if err != nil {
+			return nil, err
+		}
+		var shardStore kvstore.KeyValueStore
+		if err = proto.Unmarshal(data, &shardStore); err != nil {
+			return nil, err
+		}
+		merged.Pairs = append(merged.Pairs, shardStore.Pairs...)
+	}
+	if len(merged.Pairs) == 0 {
+		return []byte{}, nil
+	}
+	sort.Slice(merged.Pairs, func(i, j int) bool {
+		return bytes.Compare(merged.Pairs[i].Key, merged.Pairs[j].Key) < 0
+	})
+	return proto.Marshal(merged)
+}
+
+// Restore replaces the content of every shard with the snapshot in data
+func (ss *ShardedStore) Restore(data []byte) error {
+	var deserializedStore kvstore.KeyValueStore
+	err := proto.Unmarshal(data, &deserializedStore)
+	if err != nil {
+		logger.Error("Error deserializing KeyValueStore", "error", err)
+		return err
+	}
+	unlock := ss.lockAll()
+	defer unlock()
+	for _, sh := range ss.shards {
+		// The expired keys are counted since the node started, a restore does not reset them
+		expiredKeys := sh.store.expiredKeys
+		sh.store = NewTredsStore()
+		sh.store.expiredKeys = expiredKeys
+	}
+	for _, pair := range deserializedStore.Pairs {
+		s := ss.shards[ss.shardIndex(string(pair.Key))].store
+		s.tree, _, _ = s.tree.Insert(pair.Key, string(pair.Value))
+	}
+	return nil
+}
+
+func (ss *ShardedStore) DCreateCollection(args []string) error {
+	s := ss.lock(args[0])
+	defer ss.unlock(args[0])
+	return s.DCreateCollection(args)
+}
+
+func (ss *ShardedStore) DDropCollection(args []string) error {
+	s := ss.lock(args[0])
+	defer ss.unlock(args[0])
+	return s.DDropCollection(args)
+}
+
+func (ss *ShardedStore) DInsert(args []string) (string, error) {
+	s := ss.lock(args[0])
+	defer ss.unlock(args[0])
+	return s.DInsert(args)
+}
+
+func (ss *ShardedStore) DQuery(args []string) ([]string, error) {
+	s := ss.lock(args[0])
+	defer ss.unlock(args[0])
+	return s.DQuery(args)
+}
+
+func (ss *ShardedStore) DExplain(args []string) (string, error) {
+	s := ss.lock(args[0])
+	defer ss.unlock(args[0])
+	return s.DExplain(args)
+}
+
+func (ss *ShardedStore) VCreate(args []string) error {
+	s := ss.lock(args[0])
+	defer ss.unlock(args[0])
+	return s.VCreate(args)
+}
+
+func (ss *ShardedStore) VInsert(args []string) (string, error) {
+	s := ss.lock(args[0])
+	defer ss.unlock(args[0])
+	return s.VInsert(args)
+}
+
+func (ss *ShardedStore) VSearch(args []string) ([][]string, error) {
+	s := ss.lock(args[0])
+	defer ss.unlock(args[0])
+	return s.VSearch(args)
+}
+
+func (ss *ShardedStore) VDelete(args []string) (bool, error) {
+	s := ss.lock(args[0])
+	defer ss.unlock(args[0])
+	return s.VDelete(args)
+}
+
+// scanSource returns the keys of one shard in ascending order, skipping expired keys
+type scanSource func() (key string, value string, found bool)
+
+func prefixSource(ts *TredsStore, prefix string) scanSource {
+	iterator := ts.tree.Root().Iterator()
+	iterator.SeekPrefix([]byte(prefix))
+	return func() (string, string, bool) {
+		for {
+			key, value, found := iterator.Next()
+			if !found {
+				return "", "", false
+			}
+			if ts.hasExpired(string(key)) {
+				continue
+			}
+			return string(key), value.(string), true
+		}
+	}
+}
+
+func patternSource(ts *TredsStore, rx *regexp.Regexp) scanSource {
+	iterator := ts.tree.Root().Iterator()
+	iterator.PatternMatch(rx)
+	return func() (string, string, bool) {
+		for {
+			key, value, found := iterator.Next()
+			if !found {
+				return "", "", false
+			}
+			if ts.hasExpired(string(key)) {
+				continue
+			}
+			return string(key), value.(string), true
+		}
+	}
+}
+
+func keysSource(ts *TredsStore, keys []string, rx *regexp.Regexp) scanSource {
+	sort.Strings(keys)
+	index := 0
+	return func() (string, string, bool) {
+		for index < len(keys) {
+			key := keys[index]
+			index++
+			if !rx.MatchString(key) || ts.hasExpired(key) {
+				continue
+			}
+			return key, "", true
+		}
+		return "", "", false
+	}
+}
+
+func mapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// mergeScan walks sources in ascending key order and pages through them with the same
+// cursor semantics as the scans of a single TredsStore, the cursor is the hash of the last key returned
+func mergeScan(sources []scanSource, cursor string, count int, withValue bool) ([]string, error) {
+	startHash, err := strconv.Atoi(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	type head struct {
+		key, value string
+		found      bool
+	}
+	heads := make([]head, len(sources))
+	for i, source := range sources {
+		heads[i].key, heads[i].value, heads[i].found = source()
+	}
+	next := func() (string, string, bool) {
+		smallest := -1
+		for i := range heads {
+			if heads[i].found && (smallest < 0 || heads[i].key < heads[smallest].key) {
+				smallest = i
+			}
+		}
+		if smallest < 0 {
+			return "", "", false
+		}
+		key, value := heads[smallest].key, heads[smallest].value
+		heads[smallest].key, heads[smallest].value, heads[smallest].found = sources[smallest]()
+		return key, value, true
+	}
+
+	seenHash := false
+	if cursor == "0" {
+		seenHash = true
+	}
+	nextCursor := uint32(0)
+
+	result := make([]string, 0)
+
+	for {
+		key, value, found := next()
+		if !found {
+			break
+		}
+		hashKey, herr := hash(key)
+		if herr != nil {
+			return nil, herr
+		}
+		if !seenHash && hashKey == uint32(startHash) {
+			seenHash = true
+			continue
+		}
+		if seenHash && count > 0 {
+			result = append(result, key)
+			if withValue {
+				result = append(result, value)
+			}
+			nextCursor = hashKey
+			count--
+		}
+		if count == 0 {
+			break
+		}
+	}
+	if count != 0 {
+		nextCursor = uint32(0)
+	}
+	result = append(result, strconv.Itoa(int(nextCursor)))
+	return result, nil
+}
@@ -0,0 +1,173 @@
+package store
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// scanAll pages through scan with the returned cursor until it is exhausted
+func scanAll(t *testing.T, scan func(cursor string) ([]string, error), stride int) []string {
+	t.Helper()
+	all := make([]string, 0)
+	cursor := "0"
+	for {
+		res, err := scan(cursor)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		cursor = res[len(res)-1]
+		all = append(all, res[:len(res)-1]...)
+		if cursor == "0" || len(res)-1 < stride {
+			return all
+		}
+	}
+}
+
+func TestShardedStore_ScanOrder(t *testing.T) {
+	single := NewTredsStore()
+	sharded := NewShardedStore(4, ShardByHash, DefaultShardDelimiter)
+	for i := 0; i < 50; i++ {
+		key := fmt.Sprintf("user:%02d", i)
+		if err := single.Set(key, fmt.Sprint(i)); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if err := sharded.Set(key, fmt.Sprint(i)); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	expected := scanAll(t, func(cursor string) ([]string, error) {
+		return single.PrefixScan(cursor, "user:", "7")
+	}, 14)
+	got := scanAll(t, func(cursor string) ([]string, error) {
+		return sharded.PrefixScan(cursor, "user:", "7")
+	}, 14)
+	if len(got) != 100 || !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	expected = scanAll(t, func(cursor string) ([]string, error) {
+		return single.Keys(cursor, "user:1.*", 3)
+	}, 3)
+	got = scanAll(t, func(cursor string) ([]string, error) {
+		return sharded.Keys(cursor, "user:1.*", 3)
+	}, 3)
+	if len(got) != 10 || !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestShardedStore_ShardByPrefix(t *testing.T) {
+	sharded := NewShardedStore(8, ShardByPrefix, DefaultShardDelimiter)
+	for _, key := range []string{"user:1", "user:2", "user:3:name"} {
+		if sharded.shardIndex(key) != sharded.shardIndex("user") {
+			t.Fatalf("expected %s on the shard of its top level prefix", key)
+		}
+	}
+	index, ok := sharded.prefixShard("user:")
+	if !ok || index != sharded.shardIndex("user") {
+		t.Fatalf("expected a prefix scan of user: to use a single shard")
+	}
+	if _, ok = sharded.prefixShard("us"); ok {
+		t.Fatalf("expected a prefix scan of us to use every shard")
+	}
+}
+
+func TestShardedStore_MSetAtomic(t *testing.T) {
+	sharded := NewShardedStore(4, ShardByHash, DefaultShardDelimiter)
+	if err := sharded.LPush([]string{"list", "a"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := sharded.MSet([]string{"k1", "v1", "k2", "v2", "list", "v3"}); err == nil {
+		t.Fatalf("expected an error setting a list key")
+	}
+	values, found, err := sharded.MGet([]string{"k1", "k2"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(found, []bool{false, false}) {
+		t.Fatalf("expected no key to be set, got %v", values)
+	}
+}
+
+func TestShardedStore_SetOperations(t *testing.T) {
+	sharded := NewShardedStore(4, ShardByHash, DefaultShardDelimiter)
+	_ = sharded.SAdd("s1", []string{"d", "a", "c"})
+	_ = sharded.SAdd("s2", []string{"b", "c", "e"})
+
+	union, err := sharded.SUnion([]string{"s1", "s2"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(union, []string{"a", "b", "c", "d", "e"}) {
+		t.Fatalf("expected sorted union, got %v", union)
+	}
+
+	inter, err := sharded.SInter([]string{"s1", "s2"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(inter, []string{"c"}) {
+		t.Fatalf("expected [c], got %v", inter)
+	}
+
+	diff, err := sharded.SDiff([]string{"s1", "s2"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(diff, []string{"a", "d"}) {
+		t.Fatalf("expected [a d], got %v", diff)
+	}
+}
+
+func TestShardedStore_SnapshotRestore(t *testing.T) {
+	sharded := NewShardedStore(4, ShardByHash, DefaultShardDelimiter)
+	for i := 0; i < 20; i++ {
+		_ = sharded.Set(fmt.Sprintf("key%d", i), fmt.Sprintf("value%d", i))
+	}
+	data, err := sharded.Snapshot()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Snapshots do not depend on the number of shards
+	restored := NewShardedStore(3, ShardByPrefix, DefaultShardDelimiter)
+	_ = restored.Set("stale", "value")
+	if err = restored.Restore(data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	size, _ := restored.Size()
+	if size != 20 {
+		t.Fatalf("expected 20 keys, got %d", size)
+	}
+	value, _, _ := restored.Get("key7")
+	if value != "value7" {
+		t.Fatalf("expected value7, got %s", value)
+	}
+}
+
+func TestShardedStore_Keyspace(t *testing.T) {
+	ss := NewShardedStore(4, ShardByHash, DefaultShardDelimiter)
+	for i := 0; i < 10; i++ {
+		if err := ss.Set(fmt.Sprintf("key%d", i), "v"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	_ = ss.HSet("hash", []string{"f", "v"})
+	_ = ss.SAdd("set", []string{"m"})
+	_ = ss.Expire("key0", time.Now().Add(-time.Second))
+	_ = ss.Expire("key1", time.Now().Add(time.Hour))
+
+	want := KeyspaceStats{Keys: 10, Sets: 1, Hashes: 1, Expires: 2}
+	if got := ss.Keyspace(); got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	ss.CleanUpExpiredKeys()
+	want = KeyspaceStats{Keys: 9, Sets: 1, Hashes: 1, Expires: 1, ExpiredKeys: 1}
+	if got := ss.Keyspace(); got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
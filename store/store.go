@@ -3,10 +3,12 @@ package store
 import "time"
 
 type Store interface {
-	Get(string) (string, error)
-	MGet([]string) ([]string, error)
+	Get(string) (string, bool, error)
+	MGet([]string) ([]string, []bool, error)
 	MSet([]string) error
 	Set(string, string) error
+	IncrBy(string, int64) (int64, error)
+	IncrByFloat(string, float64) (float64, error)
 	Delete(string) error
 	PrefixScan(string, string, string) ([]string, error)
 	PrefixScanKeys(string, string, string) ([]string, error)
@@ -22,6 +24,7 @@ type Store interface {
 	ZRem([]string) error
 	ZCard(string) (int, error)
 	ZScore([]string) (string, error)
+	ZIncrBy(string, float64, string) (float64, error)
 	ZRange(string, int, int, bool) ([]string, error)
 	ZRangeByLexKVS(string, string, string, string, string, bool) ([]string, error)
 	ZRangeByLexKeys(string, string, string, string, string, bool) ([]string, error)
@@ -50,7 +53,9 @@ type Store interface {
 	SInter([]string) ([]string, error)
 	SDiff([]string) ([]string, error)
 	HSet(string, []string) error
-	HGet(string, string) (string, error)
+	HIncrBy(string, string, int64) (int64, error)
+	HIncrByFloat(string, string, float64) (float64, error)
+	HGet(string, string) (string, bool, error)
 	HGetAll(string) ([]string, error)
 	HLen(string) (int, error)
 	HDel(string, []string) error
@@ -58,6 +63,7 @@ type Store interface {
 	HKeys(string) ([]string, error)
 	HVals(string) ([]string, error)
 	CleanUpExpiredKeys()
+	Keyspace() KeyspaceStats
 	Expire(key string, at time.Time) error
 	Ttl(key string) int
 	LongestPrefix(string) ([]string, error)
@@ -73,3 +79,32 @@ type Store interface {
 	VSearch([]string) ([][]string, error)
 	VDelete([]string) (bool, error)
 }
+
+// KeyspaceStats counts the keys of each store, Expires the keys having an expiry and
+// ExpiredKeys the keys removed once they expired
+type KeyspaceStats struct {
+	Keys        int
+	SortedMaps  int
+	Lists       int
+	Sets        int
+	Hashes      int
+	Collections int
+	Vectors     int
+	Expires     int
+	ExpiredKeys int64
+}
+
+// Add returns the sum of s and other
+func (s KeyspaceStats) Add(other KeyspaceStats) KeyspaceStats {
+	return KeyspaceStats{
+		Keys:        s.Keys + other.Keys,
+		SortedMaps:  s.SortedMaps + other.SortedMaps,
+		Lists:       s.Lists + other.Lists,
+		Sets:        s.Sets + other.Sets,
+		Hashes:      s.Hashes + other.Hashes,
+		Collections: s.Collections + other.Collections,
+		Vectors:     s.Vectors + other.Vectors,
+		Expires:     s.Expires + other.Expires,
+		ExpiredKeys: s.ExpiredKeys + other.ExpiredKeys,
+	}
+}
@@ -2,8 +2,10 @@ package store
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"hash/fnv"
+	"math"
 	"reflect"
 	"regexp"
 	"sort"
@@ -23,13 +25,28 @@ import (
 	"github.com/tidwall/gjson"
 	"golang.org/x/sync/errgroup"
 	"treds/datastructures/hnsw"
+	"treds/logging"
 	kvstore "treds/store/proto"
 )
 
+var logger = logging.Named("store")
+
 const NilResp = "(nil)"
 const Unique = "unique"
 const IndexSuffix = "_index"
 
+// Errors of the numeric commands like INCRBY, HINCRBYFLOAT and ZINCRBY
+var (
+	// ErrNotInteger is returned when the value incremented is not a 64 bit integer
+	ErrNotInteger = errors.New("value is not an integer or out of range")
+	// ErrNotFloat is returned when the value incremented is not a finite float
+	ErrNotFloat = errors.New("value is not a valid float")
+	// ErrOverflow is returned when an integer increment overflows 64 bits
+	ErrOverflow = errors.New("increment or decrement would overflow")
+	// ErrNotFinite is returned when a float increment results in NaN or Infinity
+	ErrNotFinite = errors.New("increment would produce NaN or Infinity")
+)
+
 type Type int
 
 const (
@@ -123,6 +140,8 @@ type TredsStore struct {
 
 	// Expiry
 	expiry map[string]time.Time
+	// expiredKeys counts the keys removed once they expired
+	expiredKeys int64
 }
 
 func NewTredsStore() *TredsStore {
@@ -144,10 +163,25 @@ func (ts *TredsStore) CleanUpExpiredKeys() {
 	for key, _ := range ts.expiry {
 		if ts.hasExpired(key) {
 			_ = ts.Delete(key)
+			ts.expiredKeys++
 		}
 	}
 }
 
+func (ts *TredsStore) Keyspace() KeyspaceStats {
+	return KeyspaceStats{
+		Keys:        ts.tree.Len(),
+		SortedMaps:  len(ts.sortedMaps),
+		Lists:       len(ts.lists),
+		Sets:        len(ts.sets),
+		Hashes:      len(ts.hashes),
+		Collections: len(ts.collections),
+		Vectors:     len(ts.vectors),
+		Expires:     len(ts.expiry),
+		ExpiredKeys: ts.expiredKeys,
+	}
+}
+
 func (ts *TredsStore) hasExpired(key string) bool {
 	expired := false
 	now := time.Now()
@@ -160,6 +194,7 @@ func (ts *TredsStore) hasExpired(key string) bool {
 func (ts *TredsStore) getKeyDetails(key string) Type {
 	if ts.hasExpired(key) {
 		_ = ts.Delete(key)
+		ts.expiredKeys++
 		return -1
 	}
 	return ts.getKeyStore(key)
@@ -185,75 +220,73 @@ func (ts *TredsStore) getKeyStore(key string) Type {
 	return -1
 }
 
-func (ts *TredsStore) Get(k string) (string, error) {
+func (ts *TredsStore) Get(k string) (string, bool, error) {
 	storeType := ts.getKeyDetails(k)
 	if storeType != KeyValueStore {
-		return NilResp, nil
+		return "", false, nil
 	}
 	v, ok := ts.tree.Get([]byte(k))
 	if !ok {
-		return NilResp, nil
+		return "", false, nil
 	}
-	return v.(string), nil
+	return v.(string), true, nil
 }
 
 func (ts *TredsStore) MSet(kvs []string) error {
-	args := strings.Join(kvs, " ")
-	keyValues, err := splitCommandWithQuotes(args)
-	if err != nil {
+	// Validate every pair first so that either all or none of the keys are set
+	if err := ts.validateMSet(kvs); err != nil {
 		return err
 	}
-	var g errgroup.Group
-	var mu sync.Mutex
-	for itr := 0; itr < len(keyValues); itr += 2 {
-		g.Go(func() error {
-			mu.Lock()
-			validKey := validateKey(keyValues[itr])
-			if !validKey {
-				return fmt.Errorf("invalid key: %s", keyValues[itr])
-			}
-			err = ts.Set(keyValues[itr], keyValues[itr+1])
-			mu.Unlock()
-			if err != nil {
-				return err
-			}
-			return nil
-		})
+	for itr := 0; itr < len(kvs); itr += 2 {
+		if err := ts.Set(kvs[itr], kvs[itr+1]); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	if err := g.Wait(); err != nil {
-		return err
+// validateMSet checks that every key value pair of an MSET can be set
+func (ts *TredsStore) validateMSet(kvs []string) error {
+	if len(kvs)%2 != 0 {
+		return fmt.Errorf("wrong number of arguments for key value pairs")
+	}
+	for itr := 0; itr < len(kvs); itr += 2 {
+		if !validateKey(kvs[itr]) {
+			return fmt.Errorf("invalid key: %s", kvs[itr])
+		}
+		kd := ts.getKeyDetails(kvs[itr])
+		if kd != -1 && kd != KeyValueStore {
+			return fmt.Errorf("not key value store")
+		}
 	}
 	return nil
 }
 
-func (ts *TredsStore) MGet(args []string) ([]string, error) {
+func (ts *TredsStore) MGet(args []string) ([]string, []bool, error) {
 	results := make([]string, len(args))
+	found := make([]bool, len(args))
 	var g errgroup.Group
 	var mu sync.Mutex
 	for i, arg := range args {
 		index := i
 		key := arg
 		g.Go(func() error {
-			res, err := ts.Get(key)
+			res, ok, err := ts.Get(key)
 			if err != nil {
 				return err
 			}
 			mu.Lock()
 			results[index] = res
+			found[index] = ok
 			mu.Unlock()
 			return nil
 		})
 	}
 
 	if err := g.Wait(); err != nil {
-		return nil, err
-	}
-	response := make([]string, 0)
-	for _, res := range results {
-		response = append(response, res)
+		return nil, nil, err
 	}
-	return response, nil
+	return results, found, nil
 }
 
 func (ts *TredsStore) Set(k string, v string) error {
@@ -263,13 +296,9 @@ func (ts *TredsStore) Set(k string, v string) error {
 	}
 	validKey := validateKey(k)
 	if !validKey {
-		return fmt.Errorf("invalid key: %s", v)
-	}
-	parsedArgs, err := splitCommandWithQuotes(v)
-	if err != nil {
-		return err
+		return fmt.Errorf("invalid key: %s", k)
 	}
-	ts.tree, _, _ = ts.tree.Insert([]byte(k), parsedArgs[0])
+	ts.tree, _, _ = ts.tree.Insert([]byte(k), v)
 	return nil
 }
 
@@ -285,6 +314,77 @@ func (ts *TredsStore) Delete(k string) error {
 	return nil
 }
 
+// IncrBy adds delta to the integer value of k and returns the result, a missing key counts as 0.
+// The expiry of k is kept.
+func (ts *TredsStore) IncrBy(k string, delta int64) (int64, error) {
+	kd := ts.getKeyDetails(k)
+	if kd != -1 && kd != KeyValueStore {
+		return 0, fmt.Errorf("not key value store")
+	}
+	validKey := validateKey(k)
+	if !validKey {
+		return 0, fmt.Errorf("invalid key: %s", k)
+	}
+	current := "0"
+	if v, ok := ts.tree.Get([]byte(k)); ok {
+		current = v.(string)
+	}
+	result, err := incrementInt(current, delta)
+	if err != nil {
+		return 0, err
+	}
+	ts.tree, _, _ = ts.tree.Insert([]byte(k), strconv.FormatInt(result, 10))
+	return result, nil
+}
+
+// IncrByFloat adds delta to the float value of k and returns the result, a missing key counts as 0.
+// The expiry of k is kept.
+func (ts *TredsStore) IncrByFloat(k string, delta float64) (float64, error) {
+	kd := ts.getKeyDetails(k)
+	if kd != -1 && kd != KeyValueStore {
+		return 0, fmt.Errorf("not key value store")
+	}
+	validKey := validateKey(k)
+	if !validKey {
+		return 0, fmt.Errorf("invalid key: %s", k)
+	}
+	current := "0"
+	if v, ok := ts.tree.Get([]byte(k)); ok {
+		current = v.(string)
+	}
+	result, err := incrementFloat(current, delta)
+	if err != nil {
+		return 0, err
+	}
+	ts.tree, _, _ = ts.tree.Insert([]byte(k), strconv.FormatFloat(result, 'f', -1, 64))
+	return result, nil
+}
+
+// incrementInt adds delta to the integer value
+func incrementInt(value string, delta int64) (int64, error) {
+	current, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, ErrNotInteger
+	}
+	if (delta > 0 && current > math.MaxInt64-delta) || (delta < 0 && current < math.MinInt64-delta) {
+		return 0, ErrOverflow
+	}
+	return current + delta, nil
+}
+
+// incrementFloat adds delta to the float value, the result has to be finite
+func incrementFloat(value string, delta float64) (float64, error) {
+	current, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(current) || math.IsInf(current, 0) {
+		return 0, ErrNotFloat
+	}
+	result := current + delta
+	if math.IsNaN(result) || math.IsInf(result, 0) {
+		return 0, ErrNotFinite
+	}
+	return result, nil
+}
+
 func (ts *TredsStore) PrefixScan(cursor, prefix, count string) ([]string, error) {
 	startHash, err := strconv.Atoi(cursor)
 	if err != nil {
@@ -761,9 +861,9 @@ func (ts *TredsStore) ZAdd(args []string) error {
 	if kd != -1 && kd != SortedMapStore {
 		return fmt.Errorf("not sorted map store")
 	}
-	parsedArgs, pErr := splitCommandWithQuotes(strings.Join(args[1:], " "))
-	if pErr != nil {
-		return pErr
+	parsedArgs := args[1:]
+	if len(parsedArgs)%3 != 0 {
+		return fmt.Errorf("expected score, key and value triples")
 	}
 	validKey := validateKey(args[0])
 	if !validKey {
@@ -792,6 +892,10 @@ func (ts *TredsStore) ZAdd(args []string) error {
 		if err != nil {
 			return err
 		}
+		// A member changing score leaves the bucket of its old score
+		if oldScore, found := sm[parsedArgs[itr+1]]; found && oldScore != score {
+			removeFromScore(tm, oldScore, parsedArgs[itr+1])
+		}
 		sm[parsedArgs[itr+1]] = score
 		radixTree := radix_tree.New()
 		storedRadixTree, found := tm.Get(score)
@@ -801,30 +905,7 @@ func (ts *TredsStore) ZAdd(args []string) error {
 		sortedKeyMap, _, _ = sortedKeyMap.Insert([]byte(parsedArgs[itr+1]), parsedArgs[itr+2])
 		radixTree, _, _ = radixTree.Insert([]byte(parsedArgs[itr+1]), parsedArgs[itr+2])
 		tm.Put(score, radixTree)
-		_, radixTreeFloor := tm.Lower(score)
-		if radixTreeFloor != nil {
-			tree := radixTreeFloor.(*radix_tree.Tree)
-			maxLeaf, foundMaxLeaf := tree.Root().MaximumLeaf()
-			minLeaf, foundMinLeaf := radixTree.Root().MinimumLeaf()
-			if foundMaxLeaf {
-				maxLeaf.SetNextLeaf(minLeaf)
-			}
-			if foundMinLeaf {
-				minLeaf.SetPrevLeaf(maxLeaf)
-			}
-		}
-		_, radixTreeCeiling := tm.Greater(score)
-		if radixTreeCeiling != nil {
-			tree := radixTreeCeiling.(*radix_tree.Tree)
-			minLeaf, foundMaxLeaf := tree.Root().MinimumLeaf()
-			maxLeaf, foundMinLeaf := radixTree.Root().MaximumLeaf()
-			if foundMaxLeaf {
-				maxLeaf.SetNextLeaf(minLeaf)
-			}
-			if foundMinLeaf {
-				minLeaf.SetPrevLeaf(maxLeaf)
-			}
-		}
+		linkScore(tm, score)
 	}
 	ts.sortedMaps[args[0]] = tm
 	ts.sortedMapsScore[args[0]] = sm
@@ -841,48 +922,13 @@ func (ts *TredsStore) ZRem(args []string) error {
 	if !ok {
 		return nil
 	}
-	for itr := 1; itr < len(args); itr += 1 {
-		key := []byte(args[itr])
-		score, found := ts.sortedMapsScore[args[0]]
-		if !found {
-			continue
-		}
-		scoreFloat := score[string(key)]
-		storedRadixTree, found := storedTm.Get(scoreFloat)
+	scores := ts.sortedMapsScore[args[0]]
+	for _, member := range args[1:] {
+		score, found := scores[member]
 		if !found {
 			continue
 		}
-		radixTree := storedRadixTree.(*radix_tree.Tree)
-		radixTree, _, _ = radixTree.Delete([]byte(args[itr]))
-		if radixTree.Len() == 0 {
-			storedTm.Remove(scoreFloat)
-		} else {
-			storedTm.Put(scoreFloat, radixTree)
-		}
-		_, radixTreeFloor := storedTm.Lower(scoreFloat)
-		if radixTreeFloor != nil {
-			tree := radixTreeFloor.(*radix_tree.Tree)
-			maxLeaf, foundMaxLeaf := tree.Root().MaximumLeaf()
-			minLeaf, foundMinLeaf := radixTree.Root().MinimumLeaf()
-			if foundMaxLeaf {
-				maxLeaf.SetNextLeaf(minLeaf)
-			}
-			if foundMinLeaf {
-				minLeaf.SetPrevLeaf(maxLeaf)
-			}
-		}
-		_, radixTreeCeiling := storedTm.Greater(scoreFloat)
-		if radixTreeCeiling != nil {
-			tree := radixTreeCeiling.(*radix_tree.Tree)
-			minLeaf, foundMinLeaf := tree.Root().MinimumLeaf()
-			maxLeaf, foundMaxLeaf := radixTree.Root().MaximumLeaf()
-			if foundMaxLeaf {
-				maxLeaf.SetNextLeaf(minLeaf)
-			}
-			if foundMinLeaf {
-				minLeaf.SetPrevLeaf(maxLeaf)
-			}
-		}
+		removeFromScore(storedTm, score, member)
 	}
 	ts.sortedMaps[args[0]] = storedTm
 	for _, arg := range args[1:] {
@@ -892,6 +938,79 @@ func (ts *TredsStore) ZRem(args []string) error {
 	return nil
 }
 
+// ZIncrBy adds delta to the score of member in the sorted map key and returns the new score.
+// A missing member is added with a score of delta and an empty value.
+func (ts *TredsStore) ZIncrBy(key string, delta float64, member string) (float64, error) {
+	kd := ts.getKeyDetails(key)
+	if kd != -1 && kd != SortedMapStore {
+		return 0, fmt.Errorf("not sorted map store")
+	}
+	score, found := ts.sortedMapsScore[key][member]
+	value := ""
+	if found {
+		stored, _ := ts.sortedMapsKeys[key].Get([]byte(member))
+		value = stored.(string)
+	}
+	score += delta
+	if math.IsNaN(score) || math.IsInf(score, 0) {
+		return 0, ErrNotFinite
+	}
+	// ZAdd moves the member to the bucket of its new score
+	if err := ts.ZAdd([]string{key, strconv.FormatFloat(score, 'f', -1, 64), member, value}); err != nil {
+		return 0, err
+	}
+	return score, nil
+}
+
+// removeFromScore removes member from the bucket of score in tm, the bucket is dropped once empty
+func removeFromScore(tm *treemap.Map, score float64, member string) {
+	stored, found := tm.Get(score)
+	if !found {
+		return
+	}
+	radixTree, _, _ := stored.(*radix_tree.Tree).Delete([]byte(member))
+	if radixTree.Len() == 0 {
+		tm.Remove(score)
+	} else {
+		tm.Put(score, radixTree)
+	}
+	linkScore(tm, score)
+}
+
+// linkScore links the leaves of the bucket of score to the buckets of the scores around it, or these buckets
+// together when there is no bucket for score, so the leaves of a sorted map are linked in score order
+func linkScore(tm *treemap.Map, score float64) {
+	lower, greater, bucket := radix_tree.New(), radix_tree.New(), radix_tree.New()
+	if _, stored := tm.Lower(score); stored != nil {
+		lower = stored.(*radix_tree.Tree)
+	}
+	if _, stored := tm.Greater(score); stored != nil {
+		greater = stored.(*radix_tree.Tree)
+	}
+	if stored, found := tm.Get(score); found {
+		bucket = stored.(*radix_tree.Tree)
+	}
+	lowerMax, _ := lower.Root().MaximumLeaf()
+	greaterMin, _ := greater.Root().MinimumLeaf()
+	first, found := bucket.Root().MinimumLeaf()
+	last, _ := bucket.Root().MaximumLeaf()
+	if !found {
+		first, last = greaterMin, lowerMax
+	}
+	if lowerMax != nil {
+		lowerMax.SetNextLeaf(first)
+	}
+	if first != nil {
+		first.SetPrevLeaf(lowerMax)
+	}
+	if greaterMin != nil {
+		greaterMin.SetPrevLeaf(last)
+	}
+	if last != nil {
+		last.SetNextLeaf(greaterMin)
+	}
+}
+
 func (ts *TredsStore) ZRange(key string, startIndex int, endIndex int, withScore bool) ([]string, error) {
 	kd := ts.getKeyDetails(key)
 	if kd != -1 && kd != SortedMapStore {
@@ -1424,11 +1543,7 @@ func (ts *TredsStore) LPush(args []string) error {
 	if !ok {
 		storedList = doublylinkedlist.New()
 	}
-	parsedArgs, err := splitCommandWithQuotes(strings.Join(args[1:], " "))
-	if err != nil {
-		return err
-	}
-	for _, arg := range parsedArgs {
+	for _, arg := range args[1:] {
 		storedList.Prepend(arg)
 	}
 	ts.lists[key] = storedList
@@ -1449,11 +1564,7 @@ func (ts *TredsStore) RPush(args []string) error {
 	if !ok {
 		storedList = doublylinkedlist.New()
 	}
-	parsedArgs, err := splitCommandWithQuotes(strings.Join(args[1:], " "))
-	if err != nil {
-		return err
-	}
-	for _, arg := range parsedArgs {
+	for _, arg := range args[1:] {
 		storedList.Append(arg)
 	}
 	ts.lists[key] = storedList
@@ -1611,16 +1722,12 @@ func (ts *TredsStore) SAdd(key string, members []string) error {
 	if !validKey {
 		return fmt.Errorf("invalid key")
 	}
-	parsedArgs, err := splitCommandWithQuotes(strings.Join(members, " "))
-	if err != nil {
-		return err
-	}
 	storedSet, ok := ts.sets[key]
 	if !ok {
 		storedSet = hashset.New()
 		ts.sets[key] = storedSet
 	}
-	for _, member := range parsedArgs {
+	for _, member := range members {
 		storedSet.Add(member)
 	}
 	return nil
@@ -1631,15 +1738,11 @@ func (ts *TredsStore) SRem(key string, members []string) error {
 	if kd != -1 && kd != SetStore {
 		return fmt.Errorf("not set store")
 	}
-	parsedArgs, err := splitCommandWithQuotes(strings.Join(members, " "))
-	if err != nil {
-		return err
-	}
 	storedSet, ok := ts.sets[key]
 	if !ok {
 		return nil
 	}
-	for _, member := range parsedArgs {
+	for _, member := range members {
 		storedSet.Remove(member)
 	}
 	return nil
@@ -1775,41 +1878,97 @@ func (ts *TredsStore) HSet(key string, args []string) error {
 	if !validKey {
 		return fmt.Errorf("invalid key")
 	}
+	if len(args)%2 != 0 {
+		return fmt.Errorf("expected field value pairs")
+	}
+	for iter := 0; iter < len(args); iter += 2 {
+		validKey = validateKey(args[iter])
+		if !validKey {
+			return fmt.Errorf("invalid key")
+		}
+	}
 	storedMap, ok := ts.hashes[key]
 	if !ok {
 		storedMap = hashmap.New()
 		ts.hashes[key] = storedMap
 	}
-	parsedArgs, err := splitCommandWithQuotes(strings.Join(args, " "))
+	for iter := 0; iter < len(args); iter += 2 {
+		storedMap.Put(args[iter], args[iter+1])
+	}
+	return nil
+}
+
+// HIncrBy adds delta to the integer value of field in the hash key and returns the result,
+// a missing field counts as 0
+func (ts *TredsStore) HIncrBy(key string, field string, delta int64) (int64, error) {
+	current, err := ts.hashField(key, field)
 	if err != nil {
-		return err
+		return 0, err
 	}
-	for iter := 0; iter < storedMap.Size(); iter += 2 {
-		validKey = validateKey(parsedArgs[iter])
-		if !validKey {
-			return fmt.Errorf("invalid key")
-		}
+	result, err := incrementInt(current, delta)
+	if err != nil {
+		return 0, err
+	}
+	ts.hashPut(key, field, strconv.FormatInt(result, 10))
+	return result, nil
+}
+
+// HIncrByFloat adds delta to the float value of field in the hash key and returns the result,
+// a missing field counts as 0
+func (ts *TredsStore) HIncrByFloat(key string, field string, delta float64) (float64, error) {
+	current, err := ts.hashField(key, field)
+	if err != nil {
+		return 0, err
 	}
-	for iter := 0; iter < len(parsedArgs); iter += 2 {
-		storedMap.Put(parsedArgs[iter], parsedArgs[iter+1])
+	result, err := incrementFloat(current, delta)
+	if err != nil {
+		return 0, err
 	}
-	return nil
+	ts.hashPut(key, field, strconv.FormatFloat(result, 'f', -1, 64))
+	return result, nil
 }
 
-func (ts *TredsStore) HGet(key string, field string) (string, error) {
+// hashField returns the value of field in the hash key, "0" when it is missing
+func (ts *TredsStore) hashField(key string, field string) (string, error) {
 	kd := ts.getKeyDetails(key)
 	if kd != -1 && kd != HashStore {
 		return "", fmt.Errorf("not hash store")
 	}
+	if !validateKey(key) || !validateKey(field) {
+		return "", fmt.Errorf("invalid key")
+	}
+	if storedMap, ok := ts.hashes[key]; ok {
+		if v, found := storedMap.Get(field); found {
+			return v.(string), nil
+		}
+	}
+	return "0", nil
+}
+
+// hashPut sets field in the hash key, creating the hash when missing
+func (ts *TredsStore) hashPut(key string, field string, value string) {
 	storedMap, ok := ts.hashes[key]
 	if !ok {
-		return NilResp, nil
+		storedMap = hashmap.New()
+		ts.hashes[key] = storedMap
+	}
+	storedMap.Put(field, value)
+}
+
+func (ts *TredsStore) HGet(key string, field string) (string, bool, error) {
+	kd := ts.getKeyDetails(key)
+	if kd != -1 && kd != HashStore {
+		return "", false, fmt.Errorf("not hash store")
+	}
+	storedMap, ok := ts.hashes[key]
+	if !ok {
+		return "", false, nil
 	}
 	val, found := storedMap.Get(field)
 	if !found {
-		return NilResp, nil
+		return "", false, nil
 	}
-	return val.(string), nil
+	return val.(string), true, nil
 }
 
 func (ts *TredsStore) HGetAll(key string) ([]string, error) {
@@ -1954,8 +2113,8 @@ func (ts *TredsStore) Snapshot() ([]byte, error) {
 			return nil, err
 		}
 		store.Pairs = append(store.Pairs, &kvstore.KeyValue{
-			Key:   string(minLeaf.Key()),
-			Value: valueString,
+			Key:   minLeaf.Key(),
+			Value: []byte(valueString),
 		})
 		minLeaf = minLeaf.GetNextLeaf()
 	}
@@ -1970,14 +2129,13 @@ func (ts *TredsStore) Restore(data []byte) error {
 	var deserializedStore kvstore.KeyValueStore
 	err := proto.Unmarshal(data, &deserializedStore)
 	if err != nil {
-		fmt.Println("Error deserializing KeyValueStore:", err)
+		logger.Error("Error deserializing KeyValueStore", "error", err)
 		return err
 	}
-	// Print the deserialized key-value pairs
-	ts.tree = radix_tree.New()
-	fmt.Println("Deserialized KeyValueStore:")
+	_ = ts.FlushAll()
+	logger.Debug("Restoring KeyValueStore", "pairs", len(deserializedStore.Pairs))
 	for _, pair := range deserializedStore.Pairs {
-		ts.tree, _, _ = ts.tree.Insert([]byte(pair.Key), pair.Value)
+		ts.tree, _, _ = ts.tree.Insert(pair.Key, string(pair.Value))
 	}
 	return nil
 }
@@ -1,6 +1,9 @@
 package store
 
 import (
+	"errors"
+	"math"
+	"reflect"
 	"testing"
 )
 
@@ -9,17 +12,17 @@ func TestTredsStore_Get(t *testing.T) {
 	store := NewTredsStore()
 
 	// Test getting a non-existent key
-	value, err := store.Get("nonexistent")
+	_, found, err := store.Get("nonexistent")
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
-	if value != NilResp {
-		t.Fatalf("expected %s, got %s", NilResp, value)
+	if found {
+		t.Fatalf("expected nonexistent to be missing")
 	}
 
 	// Test setting and then getting a key
 	store.Set("key1", "value1")
-	value, err = store.Get("key1")
+	value, _, err := store.Get("key1")
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -56,7 +59,7 @@ func TestTredsStore_Set(t *testing.T) {
 		t.Fatalf("expected no error, got %v", err)
 	}
 
-	value, err := store.Get("key1")
+	value, _, err := store.Get("key1")
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -76,12 +79,12 @@ func TestTredsStore_Delete(t *testing.T) {
 		t.Fatalf("expected no error, got %v", err)
 	}
 
-	value, err := store.Get("key1")
+	_, found, err := store.Get("key1")
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
-	if value != NilResp {
-		t.Fatalf("expected %s, got %s", NilResp, value)
+	if found {
+		t.Fatalf("expected key1 to be missing")
 	}
 
 	// Test deleting a non-existent key
@@ -143,15 +146,15 @@ func TestTredsStore_DeletePrefix(t *testing.T) {
 		t.Fatalf("expected no error, got %v", err)
 	}
 
-	value, err := store.Get("key1")
+	_, found, err := store.Get("key1")
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
-	if value != NilResp {
-		t.Fatalf("expected %s, got %s", NilResp, value)
+	if found {
+		t.Fatalf("expected key1 to be missing")
 	}
 
-	value, err = store.Get("other")
+	value, _, err := store.Get("other")
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -197,3 +200,187 @@ func TestTredsStore_KVS(t *testing.T) {
 	//	t.Fatalf("expected %s, got %s", expected, result)
 	//}
 }
+
+func TestTredsStore_BinarySafe(t *testing.T) {
+	store := NewTredsStore()
+
+	key := "{\"user\": \"1\""
+	value := "line one\r\nline \"two\" [unbalanced\x00\xff"
+
+	err := store.Set(key, value)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	got, _, err := store.Get(key)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got != value {
+		t.Fatalf("expected %q, got %q", value, got)
+	}
+
+	err = store.HSet("hash", []string{"field with space", value})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	got, _, err = store.HGet("hash", "field with space")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got != value {
+		t.Fatalf("expected %q, got %q", value, got)
+	}
+
+	// Snapshot must keep non UTF-8 bytes intact
+	data, err := store.Snapshot()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	restored := NewTredsStore()
+	err = restored.Restore(data)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	got, _, err = restored.Get(key)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got != value {
+		t.Fatalf("expected %q, got %q", value, got)
+	}
+}
+
+func TestTredsStore_IncrBy(t *testing.T) {
+	store := NewTredsStore()
+
+	got, err := store.IncrBy("counter", 5)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got != 5 {
+		t.Fatalf("expected 5, got %d", got)
+	}
+	got, err = store.IncrBy("counter", -7)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got != -2 {
+		t.Fatalf("expected -2, got %d", got)
+	}
+	value, _, _ := store.Get("counter")
+	if value != "-2" {
+		t.Fatalf("expected -2, got %s", value)
+	}
+
+	store.Set("name", "treds")
+	if _, err = store.IncrBy("name", 1); !errors.Is(err, ErrNotInteger) {
+		t.Fatalf("expected %v, got %v", ErrNotInteger, err)
+	}
+	store.Set("max", "9223372036854775807")
+	if _, err = store.IncrBy("max", 1); !errors.Is(err, ErrOverflow) {
+		t.Fatalf("expected %v, got %v", ErrOverflow, err)
+	}
+	// A failed increment leaves the value unchanged
+	value, _, _ = store.Get("max")
+	if value != "9223372036854775807" {
+		t.Fatalf("expected 9223372036854775807, got %s", value)
+	}
+}
+
+func TestTredsStore_IncrByFloat(t *testing.T) {
+	store := NewTredsStore()
+
+	store.Set("price", "10.5")
+	got, err := store.IncrByFloat("price", 0.1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got != 10.6 {
+		t.Fatalf("expected 10.6, got %v", got)
+	}
+	value, _, _ := store.Get("price")
+	if value != "10.6" {
+		t.Fatalf("expected 10.6, got %s", value)
+	}
+
+	store.Set("name", "treds")
+	if _, err = store.IncrByFloat("name", 1); !errors.Is(err, ErrNotFloat) {
+		t.Fatalf("expected %v, got %v", ErrNotFloat, err)
+	}
+	if _, err = store.IncrByFloat("price", math.Inf(1)); !errors.Is(err, ErrNotFinite) {
+		t.Fatalf("expected %v, got %v", ErrNotFinite, err)
+	}
+}
+
+func TestTredsStore_HIncrBy(t *testing.T) {
+	store := NewTredsStore()
+
+	got, err := store.HIncrBy("hash", "visits", 3)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got != 3 {
+		t.Fatalf("expected 3, got %d", got)
+	}
+	gotFloat, err := store.HIncrByFloat("hash", "visits", 0.5)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if gotFloat != 3.5 {
+		t.Fatalf("expected 3.5, got %v", gotFloat)
+	}
+	if _, err = store.HIncrBy("hash", "visits", 1); !errors.Is(err, ErrNotInteger) {
+		t.Fatalf("expected %v, got %v", ErrNotInteger, err)
+	}
+	value, _, _ := store.HGet("hash", "visits")
+	if value != "3.5" {
+		t.Fatalf("expected 3.5, got %s", value)
+	}
+}
+
+func TestTredsStore_ZIncrBy(t *testing.T) {
+	store := NewTredsStore()
+
+	err := store.ZAdd([]string{"scores", "1", "a", "va", "2", "b", "vb", "3", "c", "vc"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// b moves past c, the bucket of 2 is emptied and a gets linked to c
+	got, err := store.ZIncrBy("scores", 2, "b")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got != 4 {
+		t.Fatalf("expected 4, got %v", got)
+	}
+	// A new member is added with an empty value
+	if _, err = store.ZIncrBy("scores", 2.5, "d"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := []string{"1", "a", "va", "2.5", "d", "", "3", "c", "vc", "4", "b", "vb"}
+	members, err := store.ZRangeByScoreKVS("scores", "0", "10", "0", "10", true)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(members, expected) {
+		t.Fatalf("expected %v, got %v", expected, members)
+	}
+
+	// ZAdd moves a member changing score too
+	if err = store.ZAdd([]string{"scores", "0", "c", "vc2"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected = []string{"0", "c", "vc2", "1", "a", "va", "2.5", "d", "", "4", "b", "vb"}
+	members, err = store.ZRangeByScoreKVS("scores", "0", "10", "0", "10", true)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(members, expected) {
+		t.Fatalf("expected %v, got %v", expected, members)
+	}
+
+	store.Set("name", "treds")
+	if _, err = store.ZIncrBy("name", 1, "a"); err == nil {
+		t.Fatalf("expected an error for a key value key")
+	}
+}